@@ -2,13 +2,17 @@ package app
 
 import (
 	"context"
+	"errors"
 	"image"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/mum4k/termdash"
 	"github.com/mum4k/termdash/terminal/tcell"
 	"github.com/mum4k/termdash/terminal/terminalapi"
 	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
 )
 
 type App struct {
@@ -18,31 +22,122 @@ type App struct {
   errorCh    chan error
   term       *tcell.Terminal
   size       image.Point
+  // ticking counts the running intervals, saved when the app quits.
+  ticking    *sync.WaitGroup
+
+  // Used to apply reloaded settings
+  config   *pomodoro.IntervalConfig
+  pal      *palette
+  w        *widgets
+  summary  *summary
+  profiles *profileSwitcher
+  hook     *timewarriorHook
+  meetings *meetingGuard
+  board    *teamBoard
 }
 
-func New(config *pomodoro.IntervalConfig) (*App, error) {
+func New(config *pomodoro.IntervalConfig, s settings.Settings) (*App, error) {
+  th, err := newTheme(s.Theme)
+  if err != nil {
+    return nil, err
+  }
+  pal := &palette{t: th}
+
   ctx, cancel := context.WithCancel(context.Background())
 
-  quitter := func(k *terminalapi.Keyboard) {
-    if k.Key == 'q' || k.Key == 'Q' {
+  var (
+    h  *history
+    p  *profileSwitcher
+    n  *noteEditor
+    t  *taskPicker
+    f  *filterPicker
+    g  *meetingGuard
+    tb *teamBoard
+    w  *widgets
+  )
+  redrawCh := make(chan bool)
+  errorCh := make(chan error)
+  ticking := &sync.WaitGroup{}
+
+  quit, profile := key(s.Keys.Quit), key(s.Keys.Profile)
+  internal, external := key(s.Keys.Internal), key(s.Keys.External)
+  note, task := key(s.Keys.Note), key(s.Keys.Task)
+  filter, shorten := key(s.Keys.Filter), key(s.Keys.Shorten)
+  team := key(s.Keys.Board)
+  keys := func(k *terminalapi.Keyboard) {
+    if n.editing() {
+      n.keyboard(k.Key)
+      return
+    }
+
+    switch {
+    case isKey(k.Key, quit):
       cancel()
+      return
+    case isKey(k.Key, profile):
+      p.next()
+      return
+    case isKey(k.Key, internal):
+      interrupt(ctx, config, w, pomodoro.InterruptionInternal, redrawCh,
+        errorCh)
+      return
+    case isKey(k.Key, external):
+      interrupt(ctx, config, w, pomodoro.InterruptionExternal, redrawCh,
+        errorCh)
+      return
+    case isKey(k.Key, note):
+      n.open()
+      return
+    case isKey(k.Key, task):
+      t.next()
+      return
+    case isKey(k.Key, filter):
+      f.next()
+      return
+    case isKey(k.Key, shorten):
+      g.shorten()
+      return
+    case isKey(k.Key, team):
+      tb.keyboard()
+      return
     }
+
+    h.keyboard(k)
   }
 
-  redrawCh := make(chan bool)
-  errorCh := make(chan error)
+  w, err = newWidgets(ctx, pal, errorCh)
+  if err != nil {
+    return nil, err
+  }
+
+  p = newProfileSwitcher(ctx, config, s, w, redrawCh, errorCh)
+  n = newNoteEditor(ctx, config, w, redrawCh, errorCh)
+  t = newTaskPicker(ctx, config, w, redrawCh, errorCh)
+  g = newMeetingGuard(ctx, config, s.Calendar, w, s.Keys, redrawCh, errorCh)
 
-  w, err := newWidgets(ctx, errorCh)
+  sum, err := newSummary(ctx, config, pal, redrawCh, errorCh)
   if err != nil {
     return nil, err
   }
 
-  s, err := newSummary(ctx, config, redrawCh, errorCh)
+  f = newFilterPicker(ctx, config, w, sum, redrawCh, errorCh)
+
+  h, err = newHistory(ctx, config, sum, pal, key(s.Keys.History), redrawCh,
+    errorCh)
   if err != nil {
     return nil, err
   }
 
-  b, err := newButtonSet(ctx, config, w, s, redrawCh, errorCh)
+  tb, err = newTeamBoard(ctx, config, s.TeamURLs(), sum, h, pal, redrawCh,
+    errorCh)
+  if err != nil {
+    return nil, err
+  }
+
+  hook := newTimewarriorHook(s.Export)
+
+  b, err := newButtonSet(ctx, config, w, sum, n, hook, g, th, s.Keys,
+    s.Goals, ticking, redrawCh, errorCh)
   if err != nil {
     return nil, err
   }
@@ -52,13 +147,16 @@ func New(config *pomodoro.IntervalConfig) (*App, error) {
     return nil, err
   }
 
-  c, err := newGrid(b, w, s, term)
+  c, err := newGrid(b, w, sum, s.Keys, term)
   if err != nil {
     return nil, err
   }
 
+  h.c = c
+  tb.c = c
+
   controller, err := termdash.NewController(term, c,
-    termdash.KeyboardSubscriber(quitter))
+    termdash.KeyboardSubscriber(keys))
   if err != nil {
     return nil, err
   }
@@ -69,9 +167,60 @@ func New(config *pomodoro.IntervalConfig) (*App, error) {
     redrawCh:   redrawCh,
     errorCh:    errorCh,
     term:       term,
+    ticking:    ticking,
+    config:     config,
+    pal:        pal,
+    w:          w,
+    summary:    sum,
+    profiles:   p,
+    hook:       hook,
+    meetings:   g,
+    board:      tb,
   }, nil
 }
 
+// Reload applies settings changed while the app runs, or shows err if
+// they couldn't be loaded. Invalid settings are reported in the info panel
+// and the previous ones are kept. Duration changes apply from the next
+// interval.
+func (a *App) Reload(s settings.Settings, err error) {
+  if err == nil {
+    err = a.reload(s)
+  }
+
+  info := "Settings reloaded"
+  if err != nil {
+    info = "Keeping the previous settings. " +
+      strings.ReplaceAll(err.Error(), "\n", "; ")
+  }
+
+  a.w.update([]int{}, "", info, "", a.redrawCh)
+}
+
+func (a *App) reload(s settings.Settings) error {
+  th, err := newTheme(s.Theme)
+  if err != nil {
+    return err
+  }
+
+  if err := a.profiles.reload(s); err != nil {
+    return err
+  }
+
+  err = pomodoro.Reconfigure(a.ctx, a.config)
+  if err != nil && !errors.Is(err, pomodoro.ErrIntervalRunning) {
+    return err
+  }
+
+  a.hook.reload(s.Export)
+  a.meetings.reload(s.Calendar)
+  a.board.reload(s.TeamURLs())
+  a.pal.set(th)
+  a.summary.update(a.redrawCh)
+
+  return nil
+}
+
 func (a *App) resize() error {
   if a.size.Eq(a.term.Size()) {
     return nil
@@ -85,6 +234,25 @@ func (a *App) resize() error {
   return a.controller.Redraw()
 }
 
+// wait waits for the running interval to be saved as cancelled. The
+// widgets may still be updated meanwhile, so their channels are drained.
+func (a *App) wait() {
+  done := make(chan struct{})
+  go func() {
+    a.ticking.Wait()
+    close(done)
+  }()
+
+  for {
+    select {
+    case <-done:
+      return
+    case <-a.redrawCh:
+    case <-a.errorCh:
+    }
+  }
+}
+
 func (a *App) Run() error {
   defer a.term.Close()
   defer a.controller.Close()
@@ -103,6 +271,7 @@ func (a *App) Run() error {
         return err
       }
     case <-a.ctx.Done():
+      a.wait()
       return nil
     case <-ticker.C:
       if err := a.resize(); err != nil {
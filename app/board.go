@@ -0,0 +1,231 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/xasterKies/pomanalyzer/board"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// boardRefresh is how often the team board reads the status of the
+// members again. The time left counts down every second in between.
+const boardRefresh = 5 * time.Second
+
+// teamBoard shows what each member of the team is doing in place of the
+// summaries, from the shared database and the status served by the
+// members listed in the team setting.
+type teamBoard struct {
+  txtBoard *text.Text
+
+  // c is the root container, used to swap the board and the summaries.
+  c *container.Container
+  h *history
+
+  visible atomic.Bool
+
+  mu       sync.Mutex
+  urls     []string
+  statuses []board.Status
+  err      error
+
+  pal    *palette
+  client *http.Client
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  s        *summary
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newTeamBoard(ctx context.Context, config *pomodoro.IntervalConfig,
+  urls []string, s *summary, h *history, pal *palette,
+  redrawCh chan<- bool, errorCh chan<- error) (*teamBoard, error) {
+
+  txt, err := text.New()
+  if err != nil {
+    return nil, err
+  }
+
+  b := &teamBoard{
+    txtBoard: txt,
+    h:        h,
+    urls:     urls,
+    pal:      pal,
+    client:   &http.Client{Timeout: 2 * time.Second},
+    ctx:      ctx,
+    config:   config,
+    s:        s,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+  h.board = b
+
+  go b.run()
+
+  return b, nil
+}
+
+// reload reads the status of the members at urls from the next refresh.
+func (b *teamBoard) reload(urls []string) {
+  b.mu.Lock()
+  defer b.mu.Unlock()
+
+  b.urls = urls
+}
+
+// keyboard shows or hides the board when its key is pressed. It's called
+// by the controller, so the work happens in a goroutine.
+func (b *teamBoard) keyboard() {
+  go func() {
+    if err := b.flip(); err != nil {
+      b.errorCh <- err
+      return
+    }
+
+    b.redrawCh <- true
+  }()
+}
+
+func (b *teamBoard) flip() error {
+  if b.visible.Load() {
+    b.visible.Store(false)
+    return showSummary(b.c, b.s)
+  }
+
+  b.h.hide()
+  b.visible.Store(true)
+  if err := showBoard(b.c, b); err != nil {
+    return err
+  }
+
+  b.refresh()
+  return b.render()
+}
+
+// hide marks the board hidden, when the history takes its place.
+func (b *teamBoard) hide() {
+  b.visible.Store(false)
+}
+
+// run refreshes the board while it's shown, until the app quits.
+func (b *teamBoard) run() {
+  ticker := time.NewTicker(time.Second)
+  defer ticker.Stop()
+
+  var last time.Time
+  for {
+    select {
+    case <-b.ctx.Done():
+      return
+    case now := <-ticker.C:
+      if !b.visible.Load() {
+        continue
+      }
+
+      if now.Sub(last) >= boardRefresh {
+        b.refresh()
+        last = now
+      }
+      if err := b.render(); err != nil {
+        b.errorCh <- err
+        return
+      }
+
+      select {
+      case b.redrawCh <- true:
+      case <-b.ctx.Done():
+        return
+      }
+    }
+  }
+}
+
+// refresh reads the status of the members. Errors reading the database
+// are shown on the board, as the members may still be reached.
+func (b *teamBoard) refresh() {
+  b.mu.Lock()
+  urls := b.urls
+  b.mu.Unlock()
+
+  statuses, err := board.Collect(b.ctx, b.config, b.client, urls,
+    time.Now())
+
+  b.mu.Lock()
+  defer b.mu.Unlock()
+
+  b.err = err
+  if err == nil {
+    b.statuses = statuses
+  }
+}
+
+func (b *teamBoard) render() error {
+  b.mu.Lock()
+  defer b.mu.Unlock()
+
+  b.txtBoard.Reset()
+
+  if b.err != nil {
+    if err := b.txtBoard.Write(b.err.Error()+"\n\n",
+      text.WriteCellOpts(cell.FgColor(cell.ColorRed))); err != nil {
+      return err
+    }
+  }
+
+  if len(b.statuses) == 0 {
+    return b.txtBoard.Write("No team members yet. Share a database, or " +
+      "list the members' metrics servers in the team setting.\n")
+  }
+
+  header := fmt.Sprintf("%-20s  %-12s  %8s  %9s  %s\n", "USER", "NOW",
+    "LEFT", "POMODOROS", "FOCUS")
+  if err := b.txtBoard.Write(header); err != nil {
+    return err
+  }
+
+  now := time.Now()
+  th := b.pal.get()
+  for _, s := range b.statuses {
+    if s.Err != nil {
+      line := fmt.Sprintf("%-20s  %s\n", s.User, s.Err)
+      if err := b.txtBoard.Write(line,
+        text.WriteCellOpts(cell.FgColor(cell.ColorRed))); err != nil {
+        return err
+      }
+      continue
+    }
+
+    left := "-"
+    if s.Activity != "idle" {
+      left = s.Left(now).Round(time.Second).String()
+    }
+
+    line := fmt.Sprintf("%-20s  %-12s  %8s  %9d  %s\n", s.User, s.Activity,
+      left, s.Pomodoros, s.Focus.Round(time.Minute))
+
+    opts := []text.WriteOption{}
+    switch s.Activity {
+    case "focusing":
+      opts = append(opts, text.WriteCellOpts(cell.FgColor(th.pomodoro)))
+    case "on break":
+      opts = append(opts, text.WriteCellOpts(cell.FgColor(th.brk)))
+    case "paused":
+      opts = append(opts, text.WriteCellOpts(cell.FgColor(th.accent)))
+    }
+
+    if err := b.txtBoard.Write(line, opts...); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
@@ -3,10 +3,13 @@ package app
 import (
 	"context"
 	"fmt"
+	"strings"
+	"sync"
+	"time"
 
-	"github.com/mum4k/termdash/cell"
 	"github.com/mum4k/termdash/widgets/button"
 	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
 )
 
 type buttonSet struct {
@@ -15,24 +18,62 @@ type buttonSet struct {
 }
 
 func newButtonSet(ctx context.Context, config *pomodoro.IntervalConfig,
-  w *widgets, s *summary,
-  redrawCh chan<- bool, errorCh chan<- error) (*buttonSet, error) {
+  w *widgets, s *summary, n *noteEditor, hook *timewarriorHook,
+  g *meetingGuard, th theme, keys settings.Keys, goals settings.Goals,
+  ticking *sync.WaitGroup, redrawCh chan<- bool,
+  errorCh chan<- error) (*buttonSet, error) {
+
+  // idle shows the goal progress while nothing runs.
+  idle := func() {
+    info := "Nothing running..."
+    progress, err := goalText(ctx, config, goals)
+    if err != nil {
+      errorCh <- err
+      return
+    }
+    if progress != "" {
+      info += " " + progress
+    }
 
+    w.update([]int{}, "", info, "", redrawCh)
+  }
+
+  // startInterval is counted by ticking until the interval is saved, so
+  // the app can wait for it when quitting.
   startInterval := func() {
-    i, err := pomodoro.GetInterval(config)
+    defer ticking.Done()
+
+    i, err := pomodoro.GetInterval(ctx, config)
     errorCh <- err
 
+    if warning := g.check(i, time.Now()); warning != "" {
+      w.update([]int{}, "", warning, "", redrawCh)
+      return
+    }
+
     start := func(i pomodoro.Interval) {
       message := "Take a break"
       if i.Category == pomodoro.CategoryPomodoro {
         message = "Focus on your task"
+        if i.Task != "" {
+          message = "Focus on " + i.Task
+        }
       }
 
       w.update([]int{}, i.Category, message, "", redrawCh)
     }
 
-    end := func(pomodoro.Interval) {
-      w.update([]int{}, "", "Nothing running...", "", redrawCh)
+    end := func(i pomodoro.Interval) {
+      idle()
+      if i.Category == pomodoro.CategoryPomodoro {
+        w.update([]int{}, "", fmt.Sprintf(
+          "Pomodoro done, press %s to add a note", strings.ToUpper(keys.Note)),
+          "", redrawCh)
+      }
+      if err := hook.record(i); err != nil {
+        w.update([]int{}, "", "Not recorded in Timewarrior: "+err.Error(), "",
+          redrawCh)
+      }
       s.update(redrawCh)
     }
 
@@ -49,13 +90,13 @@ func newButtonSet(ctx context.Context, config *pomodoro.IntervalConfig,
   }
 
   pauseInterval := func() {
-    i, err := pomodoro.GetInterval(config)
+    i, err := pomodoro.GetInterval(ctx, config)
     if err != nil {
       errorCh <- err
       return
     }
 
-    if err := i.Pause(config); err != nil {
+    if err := i.Pause(ctx, config); err != nil {
       if err == pomodoro.ErrIntervalNotRunning {
         return
       }
@@ -65,12 +106,26 @@ func newButtonSet(ctx context.Context, config *pomodoro.IntervalConfig,
     w.update([]int{}, "", "Paused... press start to continue", "", redrawCh)
   }
 
-  btStart, err := button.New("(s)tart", func() error {
+  startKey, pauseKey := key(keys.Start), key(keys.Pause)
+  startLabel, pauseLabel := keyLabel("start", startKey),
+    keyLabel("pause", pauseKey)
+
+  width := startLabel
+  if len(pauseLabel) > len(width) {
+    width = pauseLabel
+  }
+
+  // The buttons' keys are typed into the note while it's being edited
+  btStart, err := button.New(startLabel, func() error {
+    if n.editing() || ctx.Err() != nil {
+      return nil
+    }
+    ticking.Add(1)
     go startInterval()
     return nil
   },
-    button.GlobalKey('s'),
-    button.WidthFor("(p)ause"),
+    button.GlobalKey(startKey),
+    button.WidthFor(width),
     button.Height(2),
   )
 
@@ -78,12 +133,15 @@ func newButtonSet(ctx context.Context, config *pomodoro.IntervalConfig,
     return nil, err
   }
 
-  btPause, err := button.New("(p)ause", func() error {
+  btPause, err := button.New(pauseLabel, func() error {
+    if n.editing() {
+      return nil
+    }
     go pauseInterval()
     return nil
   },
-    button.FillColor(cell.ColorNumber(220)),
-    button.GlobalKey('p'),
+    button.FillColor(th.accent),
+    button.GlobalKey(pauseKey),
     button.Height(2),
   )
 
@@ -91,5 +149,9 @@ func newButtonSet(ctx context.Context, config *pomodoro.IntervalConfig,
     return nil, err
   }
 
+  if goals.Daily > 0 || goals.Weekly > 0 {
+    go idle()
+  }
+
   return &buttonSet{btStart, btPause}, nil
 }
\ No newline at end of file
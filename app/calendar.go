@@ -0,0 +1,157 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/ical"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// minShortened is the shortest pomodoro offered before a meeting.
+const minShortened = 5 * time.Minute
+
+// meetingGuard warns before starting a pomodoro that would run into a
+// meeting of the calendar file, and offers to shorten it so it ends
+// before.
+type meetingGuard struct {
+  mu sync.Mutex
+  // path is the calendar file, none if empty.
+  path string
+  // warned is the pomodoro warned about, and meeting the start of the
+  // meeting it runs into.
+  warned  int64
+  meeting time.Time
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  w        *widgets
+  keys     settings.Keys
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newMeetingGuard(ctx context.Context, config *pomodoro.IntervalConfig,
+  path string, w *widgets, keys settings.Keys, redrawCh chan<- bool,
+  errorCh chan<- error) *meetingGuard {
+
+  return &meetingGuard{
+    path:     path,
+    ctx:      ctx,
+    config:   config,
+    w:        w,
+    keys:     keys,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+}
+
+// reload reads meetings from path from the next pomodoro.
+func (g *meetingGuard) reload(path string) {
+  g.mu.Lock()
+  defer g.mu.Unlock()
+
+  g.path = path
+}
+
+// check returns a warning if pomodoro i, about to start at now, runs into
+// a meeting. Each pomodoro is only warned about once, so starting it again
+// goes ahead.
+func (g *meetingGuard) check(i pomodoro.Interval, now time.Time) string {
+  g.mu.Lock()
+  defer g.mu.Unlock()
+
+  if g.path == "" || i.Category != pomodoro.CategoryPomodoro ||
+    i.State != pomodoro.StateNotStarted || i.ID == g.warned {
+    return ""
+  }
+
+  start := strings.ToUpper(g.keys.Start)
+  m, ok, err := g.next(now, now.Add(i.PlannedDuration))
+  if err != nil {
+    g.warned, g.meeting = i.ID, time.Time{}
+    return fmt.Sprintf("Can't read the calendar: %s. Press %s to start anyway",
+      err, start)
+  }
+  if !ok {
+    return ""
+  }
+
+  g.warned, g.meeting = i.ID, m.Start
+  at := m.Start.In(g.config.Location).Format("15:04")
+
+  fit := m.Start.Sub(now).Truncate(time.Minute)
+  if fit < minShortened {
+    return fmt.Sprintf("%s at %s is about to start. Press %s to start anyway",
+      m.Summary, at, start)
+  }
+
+  return fmt.Sprintf(
+    "%s at %s cuts this pomodoro short. Press %s for %s, or %s to start anyway",
+    m.Summary, at, strings.ToUpper(g.keys.Shorten), fit, start)
+}
+
+// next returns the first meeting of the calendar between start and end.
+// The file is read every time, so changes to it apply right away.
+func (g *meetingGuard) next(start, end time.Time) (ical.Meeting, bool,
+  error) {
+
+  f, err := os.Open(g.path)
+  if err != nil {
+    return ical.Meeting{}, false, err
+  }
+  defer f.Close()
+
+  c, err := ical.Read(f, g.config.Location)
+  if err != nil {
+    return ical.Meeting{}, false, err
+  }
+
+  m, ok := c.Next(start, end)
+  return m, ok, nil
+}
+
+// shorten plans the pomodoro warned about to end before its meeting. It's
+// called by the controller, so the work happens in a goroutine.
+func (g *meetingGuard) shorten() {
+  go func() {
+    g.mu.Lock()
+    id, meeting := g.warned, g.meeting
+    g.mu.Unlock()
+
+    i, err := pomodoro.GetInterval(g.ctx, g.config)
+    if err != nil {
+      g.errorCh <- err
+      return
+    }
+    if meeting.IsZero() || i.ID != id {
+      return
+    }
+
+    fit := time.Until(meeting).Truncate(time.Minute)
+    if fit < minShortened {
+      g.w.update([]int{}, "", "Too close to the meeting for a pomodoro", "",
+        g.redrawCh)
+      return
+    }
+
+    _, err = i.Shorten(g.ctx, g.config, fit)
+    if errors.Is(err, pomodoro.ErrIntervalRunning) {
+      return
+    }
+    if err != nil {
+      g.errorCh <- err
+      return
+    }
+
+    info := fmt.Sprintf("Next pomodoro set to %s, press %s to start", fit,
+      strings.ToUpper(g.keys.Start))
+    g.w.update([]int{}, "", info, "", g.redrawCh)
+  }()
+}
@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// filterPicker cycles the charts through the projects worked on recently.
+type filterPicker struct {
+  mu sync.Mutex
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  w        *widgets
+  sum      *summary
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newFilterPicker(ctx context.Context, config *pomodoro.IntervalConfig,
+  w *widgets, sum *summary, redrawCh chan<- bool,
+  errorCh chan<- error) *filterPicker {
+
+  return &filterPicker{
+    ctx:      ctx,
+    config:   config,
+    w:        w,
+    sum:      sum,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+}
+
+// next narrows the charts to the project after the current one, or shows
+// all projects again after the last one. Projects of the last 30 days are
+// offered, parents before their subprojects. It's called by the
+// controller, so the work happens in a goroutine.
+func (p *filterPicker) next() {
+  go func() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    end := time.Now()
+    projects, err := pomodoro.Projects(p.ctx, end.AddDate(0, 0, -30), end,
+      p.config)
+    if err != nil {
+      p.errorCh <- err
+      return
+    }
+
+    if len(projects) == 0 {
+      p.w.update([]int{}, "",
+        "No projects yet, record them with --project", "", p.redrawCh)
+      return
+    }
+
+    current := p.config.Filter.Project
+    next := ""
+    if current == "" {
+      next = projects[0]
+    }
+    for k, name := range projects {
+      if name == current && k+1 < len(projects) {
+        next = projects[k+1]
+      }
+    }
+
+    p.config.SetFilter(pomodoro.Filter{Project: next})
+    p.sum.update(p.redrawCh)
+    p.w.update([]int{}, "", filterText(next), "", p.redrawCh)
+  }()
+}
+
+// filterText describes the project the charts are narrowed to.
+func filterText(project string) string {
+  if project == "" {
+    return "Charts show all projects"
+  }
+
+  return fmt.Sprintf("Charts show %s only", project)
+}
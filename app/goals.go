@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// goalText describes the progress towards the daily and weekly goals, or
+// returns an empty string if none is set. The week is the last 7 days, as
+// in the weekly summary.
+func goalText(ctx context.Context, config *pomodoro.IntervalConfig,
+  goals settings.Goals) (string, error) {
+
+  now := time.Now()
+  parts := []string{}
+
+  count := func(start, end time.Time) (int, error) {
+    list, err := pomodoro.List(ctx, config, pomodoro.Query{
+      Start:         start,
+      End:           end,
+      Categories:    []string{pomodoro.CategoryPomodoro},
+      States:        []int{pomodoro.StateDone},
+      ExcludeManual: config.ExcludeManual,
+    })
+    return len(list), err
+  }
+
+  if goals.Daily > 0 {
+    start, end := config.DayBounds(now)
+    n, err := count(start, end)
+    if err != nil {
+      return "", err
+    }
+    parts = append(parts, fmt.Sprintf("%d/%d pomodoros today", n,
+      goals.Daily))
+  }
+
+  if goals.Weekly > 0 {
+    start, _ := config.DayBounds(now.AddDate(0, 0, -6))
+    _, end := config.DayBounds(now)
+    n, err := count(start, end)
+    if err != nil {
+      return "", err
+    }
+    parts = append(parts, fmt.Sprintf("%d/%d this week", n, goals.Weekly))
+  }
+
+  return strings.Join(parts, ", "), nil
+}
@@ -1,14 +1,18 @@
 package app
 
 import (
+  "fmt"
+  "strings"
+
   "github.com/mum4k/termdash/align"
   "github.com/mum4k/termdash/container"
   "github.com/mum4k/termdash/container/grid"
   "github.com/mum4k/termdash/linestyle"
   "github.com/mum4k/termdash/terminal/terminalapi"
+  "github.com/xasterKies/pomanalyzer/settings"
 )
 
-func newGrid(b *buttonSet, w *widgets, s *summary,
+func newGrid(b *buttonSet, w *widgets, s *summary, keys settings.Keys,
   t terminalapi.Terminal) (*container.Container, error) {
 
   builder := grid.New()
@@ -19,7 +23,12 @@ func newGrid(b *buttonSet, w *widgets, s *summary,
       grid.ColWidthPercWithOpts(30,
         []container.Option{
           container.Border(linestyle.Light),
-          container.BorderTitle("Press Q to Quit"),
+          container.BorderTitle(fmt.Sprintf(
+            "Press %s to Quit, %s for History, %s for Profile, %s to Note, %s for Task, %s to Filter, %s for Team",
+            strings.ToUpper(keys.Quit), strings.ToUpper(keys.History),
+            strings.ToUpper(keys.Profile), strings.ToUpper(keys.Note),
+            strings.ToUpper(keys.Task), strings.ToUpper(keys.Filter),
+            strings.ToUpper(keys.Board))),
         },
         // Add inside row
         grid.RowHeightPerc(80,
@@ -58,21 +67,11 @@ func newGrid(b *buttonSet, w *widgets, s *summary,
     ),
   )
 
-  // Add third row
+  // Add third row, which shows the summaries or the history
   builder.Add(
-    grid.RowHeightPerc(60,
-      grid.ColWidthPerc(30,
-        grid.Widget(s.bcDay,
-          container.Border(linestyle.Light),
-          container.BorderTitle("Daily Summary (minutes)"),
-        ),
-      ),
-      grid.ColWidthPerc(70,
-        grid.Widget(s.lcWeekly,
-          container.Border(linestyle.Light),
-          container.BorderTitle("Weekly Summary"),
-        ),
-      ),
+    grid.RowHeightPercWithOpts(60,
+      []container.Option{container.ID(bottomID)},
+      summaryColumns(s)...,
     ),
   )
 
@@ -87,4 +86,62 @@ func newGrid(b *buttonSet, w *widgets, s *summary,
   }
 
   return c, nil
-}
\ No newline at end of file
+}
+// bottomID identifies the container of the third row.
+const bottomID = "bottom"
+
+func summaryColumns(s *summary) []grid.Element {
+  return []grid.Element{
+    grid.ColWidthPerc(30,
+      grid.Widget(s.bcDay,
+        container.Border(linestyle.Light),
+        container.BorderTitle("Daily Summary (minutes)"),
+      ),
+    ),
+    grid.ColWidthPerc(70,
+      grid.Widget(s.lcWeekly,
+        container.Border(linestyle.Light),
+        container.BorderTitle("Weekly Summary"),
+      ),
+    ),
+  }
+}
+
+// showSummary places the summaries in the third row.
+func showSummary(c *container.Container, s *summary) error {
+  builder := grid.New()
+  builder.Add(summaryColumns(s)...)
+
+  gridOpts, err := builder.Build()
+  if err != nil {
+    return err
+  }
+
+  opts := append([]container.Option{
+    container.Clear(),
+    container.Border(linestyle.None),
+  }, gridOpts...)
+
+  return c.Update(bottomID, opts...)
+}
+
+// showHistory places the history in the third row.
+func showHistory(c *container.Container, h *history) error {
+  return c.Update(bottomID,
+    container.Clear(),
+    container.Border(linestyle.Light),
+    container.BorderTitle(
+      "History: ↑↓ select, +/- minute, (c)ategory, (d)one, (x) delete, (a)dd"),
+    container.PlaceWidget(h.txtHistory),
+  )
+}
+
+// showBoard places the team board in the third row.
+func showBoard(c *container.Container, b *teamBoard) error {
+  return c.Update(bottomID,
+    container.Clear(),
+    container.Border(linestyle.Light),
+    container.BorderTitle("Team: who's focusing"),
+    container.PlaceWidget(b.txtBoard),
+  )
+}
@@ -0,0 +1,348 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/container"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/mum4k/termdash/terminal/terminalapi"
+	"github.com/mum4k/termdash/widgets/text"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// historySize is the number of recent intervals shown in the history.
+const historySize = 20
+
+// draftStep is how much the keys move the start or change the duration of
+// a manual interval being entered.
+const draftStep = 5 * time.Minute
+
+// history lists the latest intervals in place of the summaries and lets
+// the user fix them from the keyboard.
+type history struct {
+  txtHistory *text.Text
+
+  // c is the root container, used to swap the history and the summaries.
+  c *container.Container
+  // board is hidden when the history is shown in its place.
+  board *teamBoard
+
+  mu        sync.Mutex
+  visible   bool
+  intervals []pomodoro.Interval
+  selected  int
+  deleting  bool
+  message   string
+
+  // draft is the manual interval being entered, if any.
+  draft *pomodoro.Interval
+
+  // toggle is the key that shows and hides the history.
+  toggle keyboard.Key
+  pal    *palette
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  s        *summary
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newHistory(ctx context.Context, config *pomodoro.IntervalConfig,
+  s *summary, pal *palette, toggle keyboard.Key, redrawCh chan<- bool,
+  errorCh chan<- error) (*history, error) {
+
+  txt, err := text.New()
+  if err != nil {
+    return nil, err
+  }
+
+  return &history{
+    txtHistory: txt,
+    toggle:     toggle,
+    pal:        pal,
+    ctx:        ctx,
+    config:     config,
+    s:          s,
+    redrawCh:   redrawCh,
+    errorCh:    errorCh,
+  }, nil
+}
+
+// keyboard handles the history keys. It's called by the controller for
+// every key press, so the work happens in a goroutine.
+func (h *history) keyboard(k *terminalapi.Keyboard) {
+  go func() {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+
+    if err := h.handle(k.Key); err != nil {
+      h.errorCh <- err
+      return
+    }
+
+    h.redrawCh <- true
+  }()
+}
+
+// hide marks the history hidden, when the team board takes its place.
+func (h *history) hide() {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+
+  h.visible = false
+  h.draft = nil
+}
+
+func (h *history) handle(key keyboard.Key) error {
+  if isKey(key, h.toggle) {
+    h.visible = !h.visible
+    h.draft = nil
+    if !h.visible {
+      return showSummary(h.c, h.s)
+    }
+
+    h.message = ""
+    if h.board != nil {
+      h.board.hide()
+    }
+    if err := h.load(); err != nil {
+      return err
+    }
+    if err := showHistory(h.c, h); err != nil {
+      return err
+    }
+
+    return h.render()
+  }
+
+  if !h.visible {
+    return nil
+  }
+
+  deleting := h.deleting
+  h.deleting = false
+  h.message = ""
+
+  if h.draft != nil {
+    return h.handleDraft(key)
+  }
+
+  switch key {
+  case keyboard.KeyArrowUp, 'k':
+    if h.selected > 0 {
+      h.selected--
+    }
+  case keyboard.KeyArrowDown, 'j':
+    if h.selected < len(h.intervals)-1 {
+      h.selected++
+    }
+  case '+', '=':
+    return h.edit(func(i *pomodoro.Interval) {
+      i.ActualDuration += time.Minute
+    })
+  case '-':
+    return h.edit(func(i *pomodoro.Interval) {
+      i.ActualDuration -= time.Minute
+      if i.ActualDuration < 0 {
+        i.ActualDuration = 0
+      }
+    })
+  case 'c':
+    return h.edit(func(i *pomodoro.Interval) {
+      i.Category = nextHistoryCategory(i.Category)
+    })
+  case 'd':
+    return h.edit(func(i *pomodoro.Interval) {
+      if i.State == pomodoro.StateDone {
+        i.State = pomodoro.StateCancelled
+        return
+      }
+      i.State = pomodoro.StateDone
+    })
+  case 'a':
+    d := h.config.Duration(pomodoro.CategoryPomodoro)
+    h.draft = &pomodoro.Interval{
+      StartTime:      time.Now().Truncate(draftStep).Add(-d),
+      ActualDuration: d,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           h.config.Task,
+    }
+  case 'x':
+    if len(h.intervals) == 0 {
+      break
+    }
+
+    id := h.intervals[h.selected].ID
+    if !deleting {
+      h.deleting = true
+      h.message = fmt.Sprintf("Press x again to delete interval %d", id)
+      break
+    }
+
+    return h.apply(pomodoro.Delete(h.ctx, h.config, id))
+  default:
+    return nil
+  }
+
+  return h.render()
+}
+
+// handleDraft handles the keys of the manual interval form.
+func (h *history) handleDraft(key keyboard.Key) error {
+  switch key {
+  case keyboard.KeyArrowLeft:
+    h.draft.StartTime = h.draft.StartTime.Add(-draftStep)
+  case keyboard.KeyArrowRight:
+    h.draft.StartTime = h.draft.StartTime.Add(draftStep)
+  case '+', '=':
+    h.draft.ActualDuration += draftStep
+  case '-':
+    if h.draft.ActualDuration > draftStep {
+      h.draft.ActualDuration -= draftStep
+    }
+  case 'c':
+    h.draft.Category = nextHistoryCategory(h.draft.Category)
+  case keyboard.KeyEnter:
+    _, err := pomodoro.AddManual(h.ctx, h.config, *h.draft)
+    if err == nil {
+      h.draft = nil
+    }
+    return h.apply(err)
+  case keyboard.KeyEsc:
+    h.draft = nil
+  default:
+    return nil
+  }
+
+  return h.render()
+}
+
+// edit applies fn to the selected interval.
+func (h *history) edit(fn func(*pomodoro.Interval)) error {
+  if len(h.intervals) == 0 {
+    return nil
+  }
+
+  _, err := pomodoro.Edit(h.ctx, h.config, h.intervals[h.selected].ID, fn)
+  return h.apply(err)
+}
+
+// apply refreshes the history and the summaries after a change. Errors
+// caused by the user are shown in the history instead of ending the app.
+func (h *history) apply(err error) error {
+  if err != nil {
+    if !errors.Is(err, pomodoro.ErrInvalidInterval) &&
+      !errors.Is(err, pomodoro.ErrIntervalRunning) &&
+      !errors.Is(err, pomodoro.ErrInvalidState) &&
+      !errors.Is(err, pomodoro.ErrInvalidID) {
+      return err
+    }
+
+    h.message = err.Error()
+    return h.render()
+  }
+
+  if err := h.load(); err != nil {
+    return err
+  }
+
+  h.s.update(h.redrawCh)
+  return h.render()
+}
+
+func (h *history) load() error {
+  list, err := pomodoro.List(h.ctx, h.config, pomodoro.Query{
+    Limit:      historySize,
+    Descending: true,
+  })
+  if err != nil {
+    return err
+  }
+
+  h.intervals = list
+  if h.selected >= len(list) {
+    h.selected = len(list) - 1
+  }
+  if h.selected < 0 {
+    h.selected = 0
+  }
+
+  return nil
+}
+
+func (h *history) render() error {
+  h.txtHistory.Reset()
+
+  if h.draft != nil {
+    line := fmt.Sprintf("New manual interval: %s  %s  %s\n"+
+      "←→ start, +/- duration, (c)ategory, Enter to save, Esc to cancel\n\n",
+      h.draft.StartTime.In(h.config.Location).Format("2006-01-02 15:04"),
+      h.draft.Category, h.draft.ActualDuration)
+
+    if err := h.txtHistory.Write(line,
+      text.WriteCellOpts(cell.FgColor(cell.ColorGreen))); err != nil {
+      return err
+    }
+  }
+
+  if len(h.intervals) == 0 {
+    return h.txtHistory.Write("No intervals yet.\n")
+  }
+
+  for k, i := range h.intervals {
+    start := "-"
+    if !i.StartTime.IsZero() {
+      start = i.StartTime.In(h.config.Location).Format("2006-01-02 15:04")
+    }
+
+    line := fmt.Sprintf("%5d  %-16s  %-10s  %7s / %-7s  %-10s  %s\n",
+      i.ID, start, i.Category,
+      i.ActualDuration.Round(time.Second), i.PlannedDuration,
+      stateLabel(i), i.Task)
+
+    opts := []text.WriteOption{}
+    if k == h.selected {
+      opts = append(opts, text.WriteCellOpts(
+        cell.FgColor(cell.ColorBlack), cell.BgColor(h.pal.get().accent)))
+    }
+
+    if err := h.txtHistory.Write(line, opts...); err != nil {
+      return err
+    }
+  }
+
+  if h.message != "" {
+    if err := h.txtHistory.Write("\n"+h.message+"\n",
+      text.WriteCellOpts(cell.FgColor(cell.ColorRed))); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func stateLabel(i pomodoro.Interval) string {
+  if i.Manual {
+    return pomodoro.StateName(i.State) + " (manual)"
+  }
+
+  return pomodoro.StateName(i.State)
+}
+
+// nextHistoryCategory cycles through the interval categories.
+func nextHistoryCategory(category string) string {
+  switch category {
+  case pomodoro.CategoryPomodoro:
+    return pomodoro.CategoryShortBreak
+  case pomodoro.CategoryShortBreak:
+    return pomodoro.CategoryLongBreak
+  default:
+    return pomodoro.CategoryPomodoro
+  }
+}
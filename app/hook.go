@@ -0,0 +1,50 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+	"github.com/xasterKies/pomanalyzer/timesheet"
+)
+
+// timewarriorHook appends the pomodoros done in the dashboard to the
+// Timewarrior database when export.timewarrior is set.
+type timewarriorHook struct {
+  mu sync.Mutex
+  s  settings.Export
+}
+
+func newTimewarriorHook(s settings.Export) *timewarriorHook {
+  return &timewarriorHook{s: s}
+}
+
+// reload applies the export settings from the next pomodoro.
+func (h *timewarriorHook) reload(s settings.Export) {
+  h.mu.Lock()
+  defer h.mu.Unlock()
+
+  h.s = s
+}
+
+// record appends i to the Timewarrior database if it's a done pomodoro
+// and the hook is on.
+func (h *timewarriorHook) record(i pomodoro.Interval) error {
+  if i.Category != pomodoro.CategoryPomodoro || i.State != pomodoro.StateDone {
+    return nil
+  }
+
+  h.mu.Lock()
+  defer h.mu.Unlock()
+
+  if !h.s.Timewarrior {
+    return nil
+  }
+
+  db, err := timesheet.TimewarriorDB(h.s.TimewarriorDB)
+  if err != nil {
+    return err
+  }
+
+  return timesheet.AppendTimewarrior(db, i, h.s.Mapping())
+}
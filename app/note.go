@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// noteEvents is how many keys can be typed ahead of the note editor.
+const noteEvents = 64
+
+// noteEvent opens the note editor, or is a key typed into it.
+type noteEvent struct {
+  open bool
+  key  keyboard.Key
+}
+
+// noteEditor types a note for the latest pomodoro in the info panel.
+// While it's open it receives every key, and the buttons ignore theirs.
+// Opening it and the keys typed are handled in order by one goroutine, so
+// keys typed while the note loads aren't lost.
+type noteEditor struct {
+  events chan noteEvent
+
+  mu     sync.Mutex
+  active bool
+  id     int64
+  text   []rune
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  w        *widgets
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newNoteEditor(ctx context.Context, config *pomodoro.IntervalConfig,
+  w *widgets, redrawCh chan<- bool, errorCh chan<- error) *noteEditor {
+
+  n := &noteEditor{
+    events:   make(chan noteEvent, noteEvents),
+    ctx:      ctx,
+    config:   config,
+    w:        w,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+
+  go n.run()
+
+  return n
+}
+
+// run handles the events until the app quits.
+func (n *noteEditor) run() {
+  for {
+    select {
+    case <-n.ctx.Done():
+      return
+    case e := <-n.events:
+      n.mu.Lock()
+      if e.open {
+        n.load()
+      } else {
+        n.handle(e.key)
+      }
+      n.mu.Unlock()
+    }
+  }
+}
+
+// editing reports whether a note is being typed.
+func (n *noteEditor) editing() bool {
+  n.mu.Lock()
+  defer n.mu.Unlock()
+
+  return n.active
+}
+
+// open starts a note for the latest pomodoro, showing its current note.
+// It's called by the controller, so the note loads in the background.
+func (n *noteEditor) open() {
+  n.mu.Lock()
+  n.active = true
+  n.mu.Unlock()
+
+  n.events <- noteEvent{open: true}
+}
+
+// keyboard queues the keys typed while the editor is open, to be handled
+// once the note has loaded.
+func (n *noteEditor) keyboard(k keyboard.Key) {
+  n.events <- noteEvent{key: k}
+}
+
+func (n *noteEditor) load() {
+  i, err := pomodoro.LastPomodoro(n.ctx, n.config)
+  if errors.Is(err, pomodoro.ErrNoIntervals) ||
+    (err == nil && i.State == pomodoro.StateRunning) {
+    n.active = false
+    n.w.update([]int{}, "", "Notes are added once a pomodoro ends", "",
+      n.redrawCh)
+    return
+  }
+  if err != nil {
+    n.active = false
+    n.errorCh <- err
+    return
+  }
+
+  n.id = i.ID
+  n.text = []rune(i.Note)
+  n.render()
+}
+
+func (n *noteEditor) handle(k keyboard.Key) {
+  if !n.active || n.id == 0 {
+    return
+  }
+
+  switch {
+  case k == keyboard.KeyEnter:
+    id := n.id
+    _, err := pomodoro.Edit(n.ctx, n.config, id,
+      func(i *pomodoro.Interval) { i.Note = string(n.text) })
+    n.close()
+    if err != nil {
+      n.w.update([]int{}, "", err.Error(), "", n.redrawCh)
+      return
+    }
+    n.w.update([]int{}, "", fmt.Sprintf("Note saved to pomodoro %d", id),
+      "", n.redrawCh)
+    return
+  case k == keyboard.KeyEsc:
+    n.close()
+    n.w.update([]int{}, "", "Note discarded", "", n.redrawCh)
+    return
+  case k == keyboard.KeyBackspace || k == keyboard.KeyBackspace2:
+    if len(n.text) > 0 {
+      n.text = n.text[:len(n.text)-1]
+    }
+  case k >= keyboard.KeySpace:
+    n.text = append(n.text, rune(k))
+  default:
+    return
+  }
+
+  n.render()
+}
+
+func (n *noteEditor) close() {
+  n.active = false
+  n.id = 0
+  n.text = nil
+}
+
+func (n *noteEditor) render() {
+  n.w.update([]int{}, "", fmt.Sprintf(
+    "Note for pomodoro %d (Enter to save, Esc to cancel):\n%s_", n.id,
+    string(n.text)), "", n.redrawCh)
+}
+
+// interrupt logs an interruption of kind in the running pomodoro and shows
+// the interruptions so far.
+func interrupt(ctx context.Context, config *pomodoro.IntervalConfig,
+  w *widgets, kind string, redrawCh chan<- bool, errorCh chan<- error) {
+
+  go func() {
+    i, err := pomodoro.Interrupt(ctx, config, kind)
+    if errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+      w.update([]int{}, "", "Interruptions are logged during a pomodoro", "",
+        redrawCh)
+      return
+    }
+    if err != nil {
+      errorCh <- err
+      return
+    }
+
+    w.update([]int{}, "", fmt.Sprintf("Focus on your task  %s  (%d internal, %d external)",
+      i.Marks(), i.InternalInterruptions, i.ExternalInterruptions), "",
+      redrawCh)
+  }()
+}
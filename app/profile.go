@@ -0,0 +1,112 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// profileSwitcher cycles through the profiles between intervals.
+type profileSwitcher struct {
+  mu      sync.Mutex
+  s       settings.Settings
+  current string
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  w        *widgets
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newProfileSwitcher(ctx context.Context, config *pomodoro.IntervalConfig,
+  s settings.Settings, w *widgets, redrawCh chan<- bool,
+  errorCh chan<- error) *profileSwitcher {
+
+  return &profileSwitcher{
+    s:        s,
+    current:  config.Profile,
+    ctx:      ctx,
+    config:   config,
+    w:        w,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+}
+
+// next switches to the profile after the current one, or to no profile
+// after the last one. It's called by the controller, so the work happens
+// in a goroutine.
+func (p *profileSwitcher) next() {
+  go func() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    names := append([]string{""}, p.s.ProfileNames()...)
+    name := names[0]
+    for k, n := range names {
+      if n == p.current && k+1 < len(names) {
+        name = names[k+1]
+      }
+    }
+
+    err := pomodoro.Reconfigure(p.ctx, p.config)
+    if errors.Is(err, pomodoro.ErrIntervalRunning) {
+      p.w.update([]int{}, "", "Finish the current interval to switch profile",
+        "", p.redrawCh)
+      return
+    }
+    if err != nil {
+      p.errorCh <- err
+      return
+    }
+
+    s, err := p.s.WithProfile(name)
+    if err != nil {
+      p.errorCh <- err
+      return
+    }
+    p.config.Set(s.Configure)
+    p.current = name
+
+    p.w.update([]int{}, "", profileText(s), "", p.redrawCh)
+  }()
+}
+
+// reload applies reloaded settings to the config, keeping the profile
+// picked from the dashboard unless the profile setting changed.
+func (p *profileSwitcher) reload(s settings.Settings) error {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  current := p.current
+  if s.Profile != p.s.Profile {
+    current = s.Profile
+  }
+
+  applied, err := s.WithProfile(current)
+  if err != nil {
+    return err
+  }
+
+  p.config.Set(applied.Configure)
+  p.s = s
+  p.current = current
+
+  return nil
+}
+
+// profileText describes the profile in use.
+func profileText(s settings.Settings) string {
+  name := s.Profile
+  if name == "" {
+    name = "No profile"
+  }
+
+  return fmt.Sprintf("%s: %s/%s/%s, long break every %d", name, s.Pomodoro,
+    s.ShortBreak, s.LongBreak, s.Cycle)
+}
@@ -0,0 +1,81 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mum4k/termdash/cell"
+	"github.com/mum4k/termdash/keyboard"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// theme holds the dashboard colors.
+type theme struct {
+  pomodoro cell.Color
+  brk      cell.Color
+  accent   cell.Color
+}
+
+func newTheme(t settings.Theme) (theme, error) {
+  colors := []cell.Color{}
+  for _, name := range []string{t.Pomodoro, t.Break, t.Accent} {
+    n, err := settings.Color(name)
+    if err != nil {
+      return theme{}, err
+    }
+    colors = append(colors, cell.ColorNumber(n))
+  }
+
+  return theme{
+    pomodoro: colors[0],
+    brk:      colors[1],
+    accent:   colors[2],
+  }, nil
+}
+
+// palette holds the theme in use, which changes when the settings are
+// reloaded.
+type palette struct {
+  mu sync.RWMutex
+  t  theme
+}
+
+func (p *palette) get() theme {
+  p.mu.RLock()
+  defer p.mu.RUnlock()
+
+  return p.t
+}
+
+func (p *palette) set(t theme) {
+  p.mu.Lock()
+  defer p.mu.Unlock()
+
+  p.t = t
+}
+
+// key returns the key bound by a key setting.
+func key(k string) keyboard.Key {
+  r, _ := utf8.DecodeRuneInString(strings.ToLower(k))
+  return keyboard.Key(r)
+}
+
+// isKey reports whether the pressed key k matches the bound one, ignoring
+// case.
+func isKey(k, bound keyboard.Key) bool {
+  return k == bound || k == keyboard.Key(unicode.ToUpper(rune(bound)))
+}
+
+// keyLabel names an action after its key, as "(s)tart" when the key is
+// the action's first letter or "start (x)" otherwise.
+func keyLabel(action string, k keyboard.Key) string {
+  r := string(rune(k))
+  if strings.HasPrefix(action, r) {
+    return "(" + r + ")" + strings.TrimPrefix(action, r)
+  }
+
+  return fmt.Sprintf("%s (%s)", action, r)
+}
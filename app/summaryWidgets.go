@@ -25,7 +25,7 @@ func (s *summary) update(redrawCh chan<- bool) {
 }
 
 func newSummary(ctx context.Context, config *pomodoro.IntervalConfig,
-  redrawCh chan<- bool, errorCh chan<- error) (*summary, error) {
+  pal *palette, redrawCh chan<- bool, errorCh chan<- error) (*summary, error) {
 
   s := &summary{}
   var err error
@@ -33,12 +33,12 @@ func newSummary(ctx context.Context, config *pomodoro.IntervalConfig,
   s.updateDaily = make(chan bool)
   s.updateWeekly = make(chan bool)
 
-  s.bcDay, err = newBarChart(ctx, config, s.updateDaily, errorCh)
+  s.bcDay, err = newBarChart(ctx, config, pal, s.updateDaily, errorCh)
   if err != nil {
     return nil, err
   }
 
-  s.lcWeekly, err = newLineChart(ctx, config, s.updateWeekly, errorCh)
+  s.lcWeekly, err = newLineChart(ctx, config, pal, s.updateWeekly, errorCh)
   if err != nil {
     return nil, err
   }
@@ -47,15 +47,12 @@ func newSummary(ctx context.Context, config *pomodoro.IntervalConfig,
 }
 
 func newBarChart(ctx context.Context, config *pomodoro.IntervalConfig,
-  update <-chan bool, errorCh chan<- error) (*barchart.BarChart, error) {
+  pal *palette, update <-chan bool,
+  errorCh chan<- error) (*barchart.BarChart, error) {
 
   // Initialize BarChart
   bc, err := barchart.New(
     barchart.ShowValues(),
-    barchart.BarColors([]cell.Color{
-      cell.ColorBlue,
-      cell.ColorYellow,
-    }),
     barchart.ValueColors([]cell.Color{
       cell.ColorBlack,
       cell.ColorBlack,
@@ -71,16 +68,21 @@ func newBarChart(ctx context.Context, config *pomodoro.IntervalConfig,
 
   // Update function for BarChart
   updateWidget := func() error {
-    ds, err := pomodoro.DailySummary(time.Now(), config)
+    ds, err := pomodoro.DailySummary(ctx, time.Now(), config)
     if err != nil {
       return err
     }
 
+    th := pal.get()
     return bc.Values(
       []int{int(ds[0].Minutes()),
         int(ds[1].Minutes())},
       int(math.Max(ds[0].Minutes(),
         ds[1].Minutes())*1.1)+1,
+      barchart.BarColors([]cell.Color{
+        th.pomodoro,
+        th.brk,
+      }),
     )
   }
 
@@ -105,7 +107,8 @@ func newBarChart(ctx context.Context, config *pomodoro.IntervalConfig,
 }
 
 func newLineChart(ctx context.Context, config *pomodoro.IntervalConfig,
-  update <-chan bool, errorCh chan<- error) (*linechart.LineChart, error) {
+  pal *palette, update <-chan bool,
+  errorCh chan<- error) (*linechart.LineChart, error) {
 
   // Initialize LineChart
   lc, err := linechart.New(
@@ -122,13 +125,14 @@ func newLineChart(ctx context.Context, config *pomodoro.IntervalConfig,
 
   // Update function for LineChart
   updateWidget := func() error {
-    ws, err := pomodoro.RangeSummary(time.Now(), 7, config)
+    ws, err := pomodoro.RangeSummary(ctx, time.Now(), 7, config)
     if err != nil {
       return err
     }
 
+    th := pal.get()
     err = lc.Series(ws[0].Name, ws[0].Values,
-      linechart.SeriesCellOpts(cell.FgColor(cell.ColorBlue)),
+      linechart.SeriesCellOpts(cell.FgColor(th.pomodoro)),
       linechart.SeriesXLabels(ws[0].Labels),
     )
     if err != nil {
@@ -136,7 +140,7 @@ func newLineChart(ctx context.Context, config *pomodoro.IntervalConfig,
     }
 
     return lc.Series(ws[1].Name, ws[1].Values,
-      linechart.SeriesCellOpts(cell.FgColor(cell.ColorYellow)),
+      linechart.SeriesCellOpts(cell.FgColor(th.brk)),
       linechart.SeriesXLabels(ws[1].Labels),
     )
   }
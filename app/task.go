@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// taskPicker cycles through the open tasks of the task list.
+type taskPicker struct {
+  mu sync.Mutex
+
+  ctx      context.Context
+  config   *pomodoro.IntervalConfig
+  w        *widgets
+  redrawCh chan<- bool
+  errorCh  chan<- error
+}
+
+func newTaskPicker(ctx context.Context, config *pomodoro.IntervalConfig,
+  w *widgets, redrawCh chan<- bool, errorCh chan<- error) *taskPicker {
+
+  return &taskPicker{
+    ctx:      ctx,
+    config:   config,
+    w:        w,
+    redrawCh: redrawCh,
+    errorCh:  errorCh,
+  }
+}
+
+// next picks the open task after the current one, or no task after the
+// last one. The running pomodoro keeps its task. It's called by the
+// controller, so the work happens in a goroutine.
+func (p *taskPicker) next() {
+  go func() {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+
+    list, err := pomodoro.Tasks(p.ctx, p.config, false)
+    if err != nil {
+      p.errorCh <- err
+      return
+    }
+
+    if len(list) == 0 {
+      p.w.update([]int{}, "", `No open tasks, add them with "pomo task add"`,
+        "", p.redrawCh)
+      return
+    }
+
+    _, current := p.config.CurrentTask()
+    next := pomodoro.TaskProgress{}
+    if current == 0 {
+      next = list[0]
+    }
+    for k, t := range list {
+      if t.ID == current && k+1 < len(list) {
+        next = list[k+1]
+      }
+    }
+
+    // A pomodoro waiting to start is dropped so it's created for the new
+    // task
+    err = pomodoro.Reconfigure(p.ctx, p.config)
+    if err != nil && !errors.Is(err, pomodoro.ErrIntervalRunning) {
+      p.errorCh <- err
+      return
+    }
+
+    p.config.SetTask(next.Task)
+    p.w.update([]int{}, "", taskText(next), "", p.redrawCh)
+  }()
+}
+
+// taskText describes the task picked and its progress.
+func taskText(t pomodoro.TaskProgress) string {
+  if t.ID == 0 {
+    return "No task"
+  }
+
+  if t.Estimate == 0 {
+    return fmt.Sprintf("Task: %s, %d pomodoros done", t.Name, t.Pomodoros)
+  }
+
+  return fmt.Sprintf("Task: %s, %d of %d pomodoros done", t.Name,
+    t.Pomodoros, t.Estimate)
+}
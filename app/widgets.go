@@ -42,7 +42,8 @@ func (w *widgets) update(timer []int, txtType, txtInfo, txtTimer string,
   redrawCh <- true
 }
 
-func newWidgets(ctx context.Context, errorCh chan<- error) (*widgets, error) {
+func newWidgets(ctx context.Context, pal *palette,
+  errorCh chan<- error) (*widgets, error) {
 
   w := &widgets{}
   var err error
@@ -52,7 +53,7 @@ func newWidgets(ctx context.Context, errorCh chan<- error) (*widgets, error) {
   w.updateTxtInfo = make(chan string)
   w.updateTxtTimer = make(chan string)
 
-  w.donTimer, err = newDonut(ctx, w.updateDonTimer, errorCh)
+  w.donTimer, err = newDonut(ctx, pal, w.updateDonTimer, errorCh)
   if err != nil {
     return nil, err
   }
@@ -99,12 +100,12 @@ func newText(ctx context.Context, updateText <-chan string,
   return txt, nil
 }
 
-func newDonut(ctx context.Context, donUpdater <-chan []int,
+func newDonut(ctx context.Context, pal *palette, donUpdater <-chan []int,
   errorCh chan<- error) (*donut.Donut, error) {
 
   don, err := donut.New(
     donut.Clockwise(),
-    donut.CellOpts(cell.FgColor(cell.ColorBlue)),
+    donut.CellOpts(cell.FgColor(pal.get().pomodoro)),
   )
 
   if err != nil {
@@ -116,7 +117,8 @@ func newDonut(ctx context.Context, donUpdater <-chan []int,
       select {
       case d := <-donUpdater:
         if d[0] <= d[1] {
-          errorCh <- don.Absolute(d[0], d[1])
+          errorCh <- don.Absolute(d[0], d[1],
+            donut.CellOpts(cell.FgColor(pal.get().pomodoro)))
         }
       case <-ctx.Done():
         return
@@ -0,0 +1,212 @@
+// Package board gathers what each member of a team is doing, from a
+// shared database or from the status served by the pomo of each member,
+// so those in the middle of a pomodoro can be left alone.
+package board
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// Path is where the status of the user is served, next to the metrics.
+const Path = "/status"
+
+// ErrStatus is returned when a member's status can't be fetched.
+var ErrStatus = errors.New("Can't get the status")
+
+// Status is what a member is doing, and what they've done today.
+type Status struct {
+	User string `json:"user"`
+	// Activity is "focusing", "on break", "paused" or "idle".
+	Activity string `json:"activity"`
+	// Category is that of the current interval, if any.
+	Category  string        `json:"category,omitempty"`
+	Remaining time.Duration `json:"-"`
+	Pomodoros int           `json:"pomodoros_today"`
+	Focus     time.Duration `json:"-"`
+	// Seconds carry Remaining and Focus in JSON.
+	RemainingSeconds float64 `json:"remaining_seconds"`
+	FocusSeconds     float64 `json:"focus_seconds_today"`
+
+	// URL is where the status was fetched from, empty if it comes from
+	// the shared database.
+	URL string `json:"-"`
+	// Err is why it couldn't be fetched.
+	Err error `json:"-"`
+	// At is when it was read, to count the remaining time down.
+	At time.Time `json:"-"`
+}
+
+// FromMember returns the status of member m read at now.
+func FromMember(m pomodoro.Member, now time.Time) Status {
+	s := Status{
+		User:      m.User,
+		Activity:  m.Activity(),
+		Pomodoros: m.Pomodoros,
+		Focus:     m.Focus,
+		At:        now,
+	}
+	if m.Busy() {
+		s.Category = m.Current.Category
+		s.Remaining = m.Current.Remaining()
+	}
+
+	return s
+}
+
+// Running reports whether the timer of the member is running.
+func (s Status) Running() bool {
+	return s.Err == nil && (s.Activity == "focusing" ||
+		s.Activity == "on break")
+}
+
+// Left returns the time left in the member's interval at now, counting
+// down from when the status was read while the timer runs.
+func (s Status) Left(now time.Time) time.Duration {
+	left := s.Remaining
+	if s.Running() && !s.At.IsZero() {
+		left -= now.Sub(s.At)
+	}
+	if left < 0 {
+		return 0
+	}
+
+	return left
+}
+
+// Self returns the status of the configured user today.
+func Self(ctx context.Context, config *pomodoro.IntervalConfig,
+	now time.Time) (Status, error) {
+
+	start, end := config.DayBounds(now)
+	m, err := pomodoro.MemberSummary(ctx, start, end, config)
+	if err != nil {
+		return Status{}, err
+	}
+
+	return FromMember(m, now), nil
+}
+
+// Handler returns a handler serving the status of the configured user as
+// JSON.
+func Handler(config *pomodoro.IntervalConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, err := Self(r.Context(), config, time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		s.RemainingSeconds = s.Remaining.Seconds()
+		s.FocusSeconds = s.Focus.Seconds()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(s)
+	})
+}
+
+// Fetch returns the status served at u, the address of the metrics
+// server of a member such as http://alice:9191, with or without the
+// status path.
+func Fetch(ctx context.Context, client *http.Client, u string) (Status,
+	error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL(u),
+		nil)
+	if err != nil {
+		return Status{}, fmt.Errorf("%w of %s: %w", ErrStatus, u, err)
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return Status{}, fmt.Errorf("%w of %s: %w", ErrStatus, u, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return Status{}, fmt.Errorf("%w of %s: %s", ErrStatus, u, res.Status)
+	}
+
+	var s Status
+	if err := json.NewDecoder(res.Body).Decode(&s); err != nil {
+		return Status{}, fmt.Errorf("%w of %s: %w", ErrStatus, u, err)
+	}
+
+	s.Remaining = time.Duration(s.RemainingSeconds * float64(time.Second))
+	s.Focus = time.Duration(s.FocusSeconds * float64(time.Second))
+	s.URL = u
+
+	return s, nil
+}
+
+// statusURL adds the status path to u unless it has a path already.
+func statusURL(u string) string {
+	p, err := url.Parse(u)
+	if err != nil || strings.Trim(p.Path, "/") != "" {
+		return u
+	}
+
+	p.Path = Path
+	return p.String()
+}
+
+// Collect returns the status of every member of the shared database of
+// config today and of the members serving their status at urls, sorted by
+// name. The status fetched from a member replaces the one read from the
+// database. Members that can't be reached are listed under their address
+// with the error.
+func Collect(ctx context.Context, config *pomodoro.IntervalConfig,
+	client *http.Client, urls []string, now time.Time) ([]Status, error) {
+
+	start, end := config.DayBounds(now)
+	team, err := pomodoro.TeamSummary(ctx, start, end, config)
+	if err != nil {
+		return nil, err
+	}
+
+	byUser := map[string]Status{}
+	for _, m := range team {
+		byUser[m.User] = FromMember(m, now)
+	}
+
+	fetched := make([]Status, len(urls))
+	var wg sync.WaitGroup
+	for k, u := range urls {
+		wg.Add(1)
+		go func(k int, u string) {
+			defer wg.Done()
+
+			s, err := Fetch(ctx, client, u)
+			if err != nil {
+				s = Status{User: u, URL: u, Err: err}
+			}
+			s.At = now
+			fetched[k] = s
+		}(k, u)
+	}
+	wg.Wait()
+
+	for _, s := range fetched {
+		byUser[s.User] = s
+	}
+
+	list := make([]Status, 0, len(byUser))
+	for _, s := range byUser {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(a, b int) bool {
+		return list[a].User < list[b].User
+	})
+
+	return list, nil
+}
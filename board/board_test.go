@@ -0,0 +1,145 @@
+package board_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/board"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+// focus returns a config of user on repo, with a pomodoro running since
+// the start of today for 5 minutes.
+func focus(t *testing.T, repo pomodoro.Repository,
+	user string) *pomodoro.IntervalConfig {
+
+	t.Helper()
+
+	ctx := context.Background()
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+	config.User = user
+
+	i, err := pomodoro.GetInterval(ctx, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// As the timer of user would
+	start, _ := config.DayBounds(time.Now())
+	i.StartTime = start
+	i.State = pomodoro.StateRunning
+	i.ActualDuration = 5 * time.Minute
+	if err := repo.Update(ctx, i); err != nil {
+		t.Fatal(err)
+	}
+
+	return config
+}
+
+func memory(t *testing.T) pomodoro.Repository {
+	t.Helper()
+
+	repo, err := repository.Open("memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return repo
+}
+
+func TestFetch(t *testing.T) {
+	config := focus(t, memory(t), "alice")
+
+	ts := httptest.NewServer(board.Handler(config))
+	defer ts.Close()
+
+	s, err := board.Fetch(context.Background(), ts.Client(), ts.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.User != "alice" || s.Activity != "focusing" ||
+		s.Category != pomodoro.CategoryPomodoro ||
+		s.Remaining != 20*time.Minute || s.Focus != 5*time.Minute ||
+		s.Pomodoros != 0 || s.URL != ts.URL {
+		t.Errorf("Expected alice focusing with 20m left, got %+v", s)
+	}
+
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+
+	if _, err := board.Fetch(context.Background(), missing.Client(),
+		missing.URL); !errors.Is(err, board.ErrStatus) {
+		t.Errorf("Expected error %q, got %v", board.ErrStatus, err)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	shared := memory(t)
+	config := focus(t, shared, "alice")
+
+	// Bob shares the database, Carol serves her status
+	focus(t, shared, "bob")
+	carol := httptest.NewServer(board.Handler(focus(t, memory(t), "carol")))
+	defer carol.Close()
+
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+
+	now := time.Now()
+	list, err := board.Collect(context.Background(), config, carol.Client(),
+		[]string{carol.URL, gone.URL}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []string{"alice", "bob", "carol", gone.URL}
+	if len(list) != len(exp) {
+		t.Fatalf("Expected %d members, got %+v", len(exp), list)
+	}
+	for k, user := range exp {
+		if list[k].User != user {
+			t.Errorf("Expected member %d to be %s, got %s", k, user,
+				list[k].User)
+		}
+	}
+
+	for _, s := range list[:3] {
+		if s.Err != nil || !s.Running() || s.Left(now) != 20*time.Minute {
+			t.Errorf("Expected %s focusing with 20m left, got %+v", s.User, s)
+		}
+	}
+	if list[3].Err == nil || list[3].Running() {
+		t.Errorf("Expected %s unreachable, got %+v", gone.URL, list[3])
+	}
+}
+
+func TestLeft(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		activity string
+		after    time.Duration
+		exp      time.Duration
+	}{
+		{"focusing", 3 * time.Minute, 7 * time.Minute},
+		{"on break", 12 * time.Minute, 0},
+		{"paused", 3 * time.Minute, 10 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.activity, func(t *testing.T) {
+			s := board.Status{Activity: tc.activity,
+				Remaining: 10 * time.Minute, At: at}
+
+			if left := s.Left(at.Add(tc.after)); left != tc.exp {
+				t.Errorf("Expected %s left, got %s", tc.exp, left)
+			}
+		})
+	}
+}
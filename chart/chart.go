@@ -0,0 +1,280 @@
+// Package chart draws the dashboard charts as standalone SVG or PNG
+// images, to attach to documents and chat messages.
+package chart
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// Formats of the images.
+const (
+	FormatSVG = "svg"
+	FormatPNG = "png"
+)
+
+// ErrUnknownFormat is returned for image formats other than svg and png.
+var ErrUnknownFormat = errors.New("Unknown chart format")
+
+// ParseFormat returns the format named name, in any case.
+func ParseFormat(name string) (string, error) {
+	switch f := strings.ToLower(name); f {
+	case FormatSVG, FormatPNG:
+		return f, nil
+	}
+
+	return "", fmt.Errorf("%w %q, use svg or png", ErrUnknownFormat, name)
+}
+
+// Kind is how the series of a chart are drawn.
+type Kind int
+
+// Kinds of charts.
+const (
+	Bar Kind = iota
+	Line
+)
+
+// Series is a named list of values, one per label of the chart.
+type Series struct {
+	Name  string
+	Color color.RGBA
+	// Colors override Color for single values, if set.
+	Colors []color.RGBA
+	Values []float64
+	// Tips are the tooltips of the values in SVG images, if any.
+	Tips []string
+}
+
+// Chart is a bar or line chart of one or more series.
+type Chart struct {
+	Title  string
+	Kind   Kind
+	Labels []string
+	Series []Series
+	// Format formats the values on the Y axis and, if ShowValues is set,
+	// above the bars. It defaults to %g.
+	Format     func(float64) string
+	ShowValues bool
+	// Width and Height are the size of the image in pixels, 640x320 if 0.
+	Width  int
+	Height int
+}
+
+// Write writes c to w as an image in format.
+func (c Chart) Write(w io.Writer, format string) error {
+	switch format {
+	case FormatSVG:
+		_, err := io.WriteString(w, c.SVG())
+		return err
+	case FormatPNG:
+		return c.PNG(w)
+	}
+
+	return fmt.Errorf("%w %q, use svg or png", ErrUnknownFormat, format)
+}
+
+func (c Chart) format(v float64) string {
+	if c.Format == nil {
+		return fmt.Sprintf("%g", v)
+	}
+
+	return c.Format(v)
+}
+
+func (c Chart) size() (int, int) {
+	w, h := c.Width, c.Height
+	if w <= 0 {
+		w = 640
+	}
+	if h <= 0 {
+		h = 320
+	}
+
+	return w, h
+}
+
+// Margins around the plot area, for the title, the legend and the axis
+// labels.
+const (
+	marginTop    = 40
+	marginLeft   = 56
+	marginRight  = 16
+	marginBottom = 32
+	ticks        = 4
+)
+
+// layout is the position of the plot area of a chart and the scale of
+// its Y axis.
+type layout struct {
+	left, top, right, bottom float64
+	max, step                float64
+	slot                     float64
+}
+
+func (c Chart) layout() layout {
+	w, h := c.size()
+	l := layout{
+		left:   marginLeft,
+		top:    marginTop,
+		right:  float64(w - marginRight),
+		bottom: float64(h - marginBottom),
+	}
+
+	var max float64
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+	l.step = niceStep(max / ticks)
+	l.max = l.step * math.Max(1, math.Ceil(max/l.step))
+
+	if len(c.Labels) > 0 {
+		l.slot = (l.right - l.left) / float64(len(c.Labels))
+	}
+
+	return l
+}
+
+// niceStep rounds v up to 1, 2 or 5 times a power of ten.
+func niceStep(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+
+	p := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if v <= m*p {
+			return m * p
+		}
+	}
+
+	return 10 * p
+}
+
+// ticks returns the values of the Y axis grid lines, from 0 to max.
+func (l layout) ticks() []float64 {
+	var t []float64
+	n := int(math.Round(l.max / l.step))
+	for k := 0; k <= n; k++ {
+		// Round off float errors like 0.30000000000000004
+		t = append(t, math.Round(float64(k)*l.step*1e6)/1e6)
+	}
+
+	return t
+}
+
+// x returns the center of the slot of label i.
+func (l layout) x(i int) float64 {
+	return l.left + l.slot*(float64(i)+0.5)
+}
+
+// y returns the height of value v.
+func (l layout) y(v float64) float64 {
+	return l.bottom - (l.bottom-l.top)*v/l.max
+}
+
+// bar returns the left edge and width of the bar of series s of label i,
+// the bars of a label standing side by side.
+func (l layout) bar(i, s, n int) (float64, float64) {
+	group := l.slot * 0.7
+	width := group / float64(n)
+	return l.left + l.slot*float64(i) + (l.slot-group)/2 + width*float64(s),
+		width
+}
+
+// Week returns a line chart of the pomodoro and break series computed by
+// pomodoro.RangeSummary, oldest day first. Values are shown in minutes,
+// or in hours for days longer than two hours.
+func Week(series []pomodoro.LineSeries, colors []color.RGBA) Chart {
+	c := Chart{Title: "Focus time by day", Kind: Line}
+
+	var max float64
+	for _, s := range series {
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+	unit, suffix := time.Minute, "m"
+	if max >= (2 * time.Hour).Seconds() {
+		unit, suffix = time.Hour, "h"
+	}
+	c.Format = func(v float64) string {
+		return fmt.Sprintf("%g%s", v, suffix)
+	}
+
+	for k, s := range series {
+		n := len(s.Values)
+		out := Series{Name: s.Name, Color: pick(colors, k),
+			Values: make([]float64, n), Tips: make([]string, n)}
+
+		for i, v := range s.Values {
+			// RangeSummary starts from the last day
+			j := n - 1 - i
+			d := time.Duration(v * float64(time.Second))
+			out.Values[j] = v / unit.Seconds()
+			out.Tips[j] = fmt.Sprintf("%s %s: %s", s.Name, s.Labels[i],
+				d.Round(time.Second))
+			if k == 0 {
+				if c.Labels == nil {
+					c.Labels = make([]string, n)
+				}
+				c.Labels[j] = s.Labels[i]
+			}
+		}
+
+		c.Series = append(c.Series, out)
+	}
+
+	return c
+}
+
+// Day returns a bar chart of the pomodoro and break time of a day
+// computed by pomodoro.DailySummary, in whole minutes like the dashboard.
+func Day(day time.Time, ds []time.Duration, colors []color.RGBA) Chart {
+	c := Chart{
+		Title:      day.Format("Mon 2 Jan 2006"),
+		Kind:       Bar,
+		Labels:     []string{"Pomodoro", "Break"},
+		ShowValues: true,
+		Format: func(v float64) string {
+			return fmt.Sprintf("%gm", v)
+		},
+	}
+
+	s := Series{Name: "Minutes", Color: pick(colors, 0)}
+	for k, d := range ds {
+		s.Colors = append(s.Colors, pick(colors, k))
+		s.Values = append(s.Values, math.Trunc(d.Minutes()))
+		s.Tips = append(s.Tips, d.Round(time.Second).String())
+	}
+	c.Series = []Series{s}
+
+	return c
+}
+
+// color returns the color of value i.
+func (s Series) color(i int) color.RGBA {
+	if i < len(s.Colors) {
+		return s.Colors[i]
+	}
+
+	return s.Color
+}
+
+// pick returns color k, or gray if there are not enough colors.
+func pick(colors []color.RGBA, k int) color.RGBA {
+	if k < len(colors) {
+		return colors[k]
+	}
+
+	return color.RGBA{0x80, 0x80, 0x80, 0xff}
+}
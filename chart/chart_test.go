@@ -0,0 +1,131 @@
+package chart_test
+
+import (
+	"bytes"
+	"errors"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/chart"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+var (
+	blue   = color.RGBA{0x00, 0x00, 0xee, 0xff}
+	yellow = color.RGBA{0xcd, 0xcd, 0x00, 0xff}
+)
+
+func TestParseFormat(t *testing.T) {
+	if f, err := chart.ParseFormat("PNG"); err != nil || f != chart.FormatPNG {
+		t.Errorf("Expected png, got %q, %v", f, err)
+	}
+
+	_, err := chart.ParseFormat("gif")
+	if !errors.Is(err, chart.ErrUnknownFormat) {
+		t.Errorf("Expected error %q, got %q", chart.ErrUnknownFormat, err)
+	}
+}
+
+func TestTermColor(t *testing.T) {
+	testCases := []struct {
+		n   int
+		exp color.RGBA
+	}{
+		{4, blue},
+		{3, yellow},
+		{220, color.RGBA{0xff, 0xd7, 0x00, 0xff}},
+		{16, color.RGBA{0x00, 0x00, 0x00, 0xff}},
+		{244, color.RGBA{0x80, 0x80, 0x80, 0xff}},
+	}
+
+	for _, tc := range testCases {
+		if c := chart.TermColor(tc.n); c != tc.exp {
+			t.Errorf("Expected color %d to be %v, got %v", tc.n, tc.exp, c)
+		}
+	}
+}
+
+func TestWeek(t *testing.T) {
+	// As returned by RangeSummary, from the last day
+	series := []pomodoro.LineSeries{
+		{
+			Name:   "Pomodoro",
+			Labels: map[int]string{0: "14/Oct", 1: "13/Oct", 2: "12/Oct"},
+			Values: []float64{3000, 0, 1500},
+		},
+		{
+			Name:   "Break",
+			Labels: map[int]string{0: "14/Oct", 1: "13/Oct", 2: "12/Oct"},
+			Values: []float64{300, 0, 600},
+		},
+	}
+
+	c := chart.Week(series, []color.RGBA{blue, yellow})
+
+	if exp := []string{"12/Oct", "13/Oct", "14/Oct"}; strings.Join(c.Labels, " ") !=
+		strings.Join(exp, " ") {
+		t.Errorf("Expected labels %v, got %v", exp, c.Labels)
+	}
+	if len(c.Series) != 2 || c.Series[0].Values[0] != 25 ||
+		c.Series[0].Values[2] != 50 || c.Series[1].Values[0] != 10 {
+		t.Errorf("Expected the series in minutes, oldest first, got %v",
+			c.Series)
+	}
+
+	svg := c.SVG()
+	for _, exp := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg" width="640" height="320"`,
+		">Focus time by day</text>",
+		`stroke="#0000ee"`,
+		"<title>Pomodoro 14/Oct: 50m0s</title>",
+		">12/Oct</text>",
+		">Break</text>",
+		">60m</text>",
+	} {
+		if !strings.Contains(svg, exp) {
+			t.Errorf("Expected %q in:\n%s", exp, svg)
+		}
+	}
+}
+
+func TestDay(t *testing.T) {
+	day := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	ds := []time.Duration{100*time.Minute + 40*time.Second, 20 * time.Minute}
+
+	c := chart.Day(day, ds, []color.RGBA{blue, yellow})
+	c.Width, c.Height = 320, 200
+
+	svg := c.SVG()
+	for _, exp := range []string{
+		">Wed 14 Oct 2026</text>",
+		`fill="#0000ee"><title>1h40m40s</title>`,
+		`fill="#cdcd00"><title>20m0s</title>`,
+		">100m</text>",
+	} {
+		if !strings.Contains(svg, exp) {
+			t.Errorf("Expected %q in:\n%s", exp, svg)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf, chart.FormatPNG); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 320 || b.Dy() != 200 {
+		t.Errorf("Expected a 320x200 image, got %v", b)
+	}
+
+	// The middle of the pomodoro bar is in the theme color
+	r, g, b, _ := img.At(90, 150).RGBA()
+	if got := (color.RGBA{uint8(r >> 8), uint8(g >> 8), uint8(b >> 8), 0xff}); got != blue {
+		t.Errorf("Expected the pomodoro bar in %v, got %v", blue, got)
+	}
+}
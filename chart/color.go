@@ -0,0 +1,40 @@
+package chart
+
+import "image/color"
+
+// ansi are the RGB values of the first 16 terminal colors, as in xterm.
+var ansi = [16]color.RGBA{
+	{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x00, 0x00, 0xff},
+	{0x00, 0xcd, 0x00, 0xff}, {0xcd, 0xcd, 0x00, 0xff},
+	{0x00, 0x00, 0xee, 0xff}, {0xcd, 0x00, 0xcd, 0xff},
+	{0x00, 0xcd, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+	{0x7f, 0x7f, 0x7f, 0xff}, {0xff, 0x00, 0x00, 0xff},
+	{0x00, 0xff, 0x00, 0xff}, {0xff, 0xff, 0x00, 0xff},
+	{0x5c, 0x5c, 0xff, 0xff}, {0xff, 0x00, 0xff, 0xff},
+	{0x00, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff},
+}
+
+// TermColor returns the RGB value of the terminal color number n, from
+// 0 to 255, so images use the colors of the dashboard theme.
+func TermColor(n int) color.RGBA {
+	switch {
+	case n < 0 || n > 255:
+		return color.RGBA{0x80, 0x80, 0x80, 0xff}
+	case n < 16:
+		return ansi[n]
+	case n < 232:
+		// 6x6x6 color cube
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + 40*v)
+		}
+		return color.RGBA{level(n / 36), level(n / 6 % 6), level(n % 6), 0xff}
+	}
+
+	// Grayscale ramp
+	v := uint8(8 + 10*(n-232))
+	return color.RGBA{v, v, v, 0xff}
+}
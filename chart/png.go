@@ -0,0 +1,154 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+var (
+	white = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	black = color.RGBA{0x22, 0x22, 0x22, 0xff}
+	grid  = color.RGBA{0xdd, 0xdd, 0xdd, 0xff}
+	axis  = color.RGBA{0x44, 0x44, 0x44, 0xff}
+)
+
+// Text anchors, as in SVG.
+const (
+	anchorStart = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// PNG writes c to w as a PNG image. It draws the same chart as SVG, with
+// a fixed-size bitmap font and without tooltips.
+func (c Chart) PNG(w io.Writer) error {
+	width, height := c.size()
+	l := c.layout()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(white), image.Point{},
+		draw.Src)
+
+	if c.Title != "" {
+		text(img, marginLeft, 24, c.Title, black, anchorStart)
+	}
+
+	for _, t := range l.ticks() {
+		y := l.y(t)
+		line(img, l.left, y, l.right, y, 1, grid)
+		text(img, l.left-6, y+4, c.format(t), black, anchorEnd)
+	}
+	line(img, l.left, l.bottom, l.right, l.bottom, 1, axis)
+
+	for i, name := range c.Labels {
+		text(img, l.x(i), l.bottom+18, name, black, anchorMiddle)
+	}
+
+	for k, s := range c.Series {
+		for i, v := range s.Values {
+			if i >= len(c.Labels) {
+				break
+			}
+
+			if c.Kind == Line {
+				if i > 0 {
+					line(img, l.x(i-1), l.y(s.Values[i-1]), l.x(i), l.y(v), 2,
+						s.Color)
+				}
+				continue
+			}
+
+			x, bw := l.bar(i, k, len(c.Series))
+			y := l.y(v)
+			rect(img, x, y, x+bw, l.bottom, s.color(i))
+			if c.ShowValues && v > 0 {
+				text(img, x+bw/2, y-4, c.format(v), black, anchorMiddle)
+			}
+		}
+
+		if c.Kind == Line {
+			for i, v := range s.Values {
+				if i < len(c.Labels) {
+					dot(img, l.x(i), l.y(v), 3, s.color(i))
+				}
+			}
+		}
+	}
+
+	if len(c.Series) > 1 {
+		x := l.right
+		for k := len(c.Series) - 1; k >= 0; k-- {
+			s := c.Series[k]
+			x -= float64(legendWidth(s.Name))
+			rect(img, x, 14, x+10, 24, s.Color)
+			text(img, x+14, 23, s.Name, black, anchorStart)
+		}
+	}
+
+	return png.Encode(w, img)
+}
+
+func rect(img *image.RGBA, x0, y0, x1, y1 float64, c color.RGBA) {
+	r := image.Rect(int(math.Round(x0)), int(math.Round(y0)),
+		int(math.Round(x1)), int(math.Round(y1)))
+	draw.Draw(img, r, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// line draws a line from (x0, y0) to (x1, y1), width pixels wide.
+func line(img *image.RGBA, x0, y0, x1, y1 float64, width int,
+	c color.RGBA) {
+
+	steps := int(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))) + 1
+	for k := 0; k <= steps; k++ {
+		f := float64(k) / float64(steps)
+		x := int(math.Round(x0 + (x1-x0)*f))
+		y := int(math.Round(y0 + (y1-y0)*f))
+		for dx := 0; dx < width; dx++ {
+			for dy := 0; dy < width; dy++ {
+				img.SetRGBA(x+dx-width/2, y+dy-width/2, c)
+			}
+		}
+	}
+}
+
+// dot draws a filled circle of radius r centered on (x, y).
+func dot(img *image.RGBA, x, y float64, r int, c color.RGBA) {
+	cx, cy := int(math.Round(x)), int(math.Round(y))
+	for dx := -r; dx <= r; dx++ {
+		for dy := -r; dy <= r; dy++ {
+			if dx*dx+dy*dy <= r*r {
+				img.SetRGBA(cx+dx, cy+dy, c)
+			}
+		}
+	}
+}
+
+// text draws s with its baseline at y, anchored at x.
+func text(img *image.RGBA, x, y float64, s string, c color.RGBA,
+	anchor int) {
+
+	d := font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: basicfont.Face7x13,
+	}
+
+	width := float64(d.MeasureString(s).Ceil())
+	switch anchor {
+	case anchorMiddle:
+		x -= width / 2
+	case anchorEnd:
+		x -= width
+	}
+
+	d.Dot = fixed.P(int(math.Round(x)), int(math.Round(y)))
+	d.DrawString(s)
+}
@@ -0,0 +1,121 @@
+package chart
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"strings"
+)
+
+// SVG returns c as a standalone SVG image.
+func (c Chart) SVG() string {
+	w, h := c.size()
+	l := c.layout()
+
+	var b strings.Builder
+	label := c.Title
+	if label == "" {
+		label = "Chart"
+	}
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" role="img" aria-label="%s">`+"\n",
+		w, h, w, h, html.EscapeString(label))
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="#fff"/>`+"\n", w, h)
+
+	if c.Title != "" {
+		fmt.Fprintf(&b, `<text x="%d" y="24" font-size="14" font-weight="bold">%s</text>`+"\n",
+			marginLeft, html.EscapeString(c.Title))
+	}
+
+	for _, t := range l.ticks() {
+		y := l.y(t)
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#ddd"/>`+"\n",
+			l.left, y, l.right, y)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="end">%s</text>`+"\n",
+			l.left-6, y+4, html.EscapeString(c.format(t)))
+	}
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#444"/>`+"\n",
+		l.left, l.bottom, l.right, l.bottom)
+
+	for i, name := range c.Labels {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="middle">%s</text>`+"\n",
+			l.x(i), l.bottom+18, html.EscapeString(name))
+	}
+
+	for k, s := range c.Series {
+		if c.Kind == Line {
+			c.svgLine(&b, l, s)
+			continue
+		}
+		c.svgBars(&b, l, s, k)
+	}
+
+	if len(c.Series) > 1 {
+		x := l.right
+		for k := len(c.Series) - 1; k >= 0; k-- {
+			s := c.Series[k]
+			x -= float64(legendWidth(s.Name))
+			fmt.Fprintf(&b, `<rect x="%.1f" y="14" width="10" height="10" fill="%s"/>`+"\n",
+				x, hex(s.Color))
+			fmt.Fprintf(&b, `<text x="%.1f" y="23" font-size="11">%s</text>`+"\n",
+				x+14, html.EscapeString(s.Name))
+		}
+	}
+
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func (c Chart) svgBars(b *strings.Builder, l layout, s Series, k int) {
+	for i, v := range s.Values {
+		if i >= len(c.Labels) {
+			break
+		}
+
+		x, width := l.bar(i, k, len(c.Series))
+		y := l.y(v)
+		fmt.Fprintf(b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s">`,
+			x, y, width, l.bottom-y, hex(s.color(i)))
+		if i < len(s.Tips) {
+			fmt.Fprintf(b, `<title>%s</title>`, html.EscapeString(s.Tips[i]))
+		}
+		b.WriteString("</rect>\n")
+
+		if c.ShowValues && v > 0 {
+			fmt.Fprintf(b, `<text x="%.1f" y="%.1f" font-size="11" text-anchor="middle">%s</text>`+"\n",
+				x+width/2, y-4, html.EscapeString(c.format(v)))
+		}
+	}
+}
+
+func (c Chart) svgLine(b *strings.Builder, l layout, s Series) {
+	var points []string
+	for i, v := range s.Values {
+		if i >= len(c.Labels) {
+			break
+		}
+		points = append(points, fmt.Sprintf("%.1f,%.1f", l.x(i), l.y(v)))
+	}
+	fmt.Fprintf(b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+		strings.Join(points, " "), hex(s.Color))
+
+	for i, v := range s.Values {
+		if i >= len(c.Labels) {
+			break
+		}
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s">`,
+			l.x(i), l.y(v), hex(s.color(i)))
+		if i < len(s.Tips) {
+			fmt.Fprintf(b, `<title>%s</title>`, html.EscapeString(s.Tips[i]))
+		}
+		b.WriteString("</circle>\n")
+	}
+}
+
+// legendWidth is the width taken in the legend by a series named name.
+func legendWidth(name string) int {
+	return 14 + 7*len([]rune(name)) + 12
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+  Use:   "add",
+  Short: "Record an interval done away from the computer",
+  Long: `Record a session done away from the computer, such as a whiteboard
+session or reading printouts. It's stored as a done interval flagged as
+manual, so summaries can include or exclude it with --exclude-manual.`,
+  Example: "  pomo add --at 2026-10-16T14:00 --duration 25m --category Pomodoro",
+  Args:    cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    at, _ := flags.GetString("at")
+    start, err := parseTime(at, config.Location)
+    if err != nil {
+      return err
+    }
+
+    i := pomodoro.Interval{StartTime: start}
+    i.ActualDuration, _ = flags.GetDuration("duration")
+    i.Category, _ = flags.GetString("category")
+    i.Task, _ = flags.GetString("task")
+    i.Note, _ = flags.GetString("note")
+
+    project, _ := flags.GetString("project")
+    tags, _ := flags.GetStringSlice("tag")
+    if i.Project, i.Tags, err = parseLabels(project, tags); err != nil {
+      return err
+    }
+
+    return addAction(cmd.Context(), os.Stdout, config, i)
+  },
+}
+
+func addAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, i pomodoro.Interval) error {
+
+  // Sessions on a planned task count toward it
+  if i.Task != "" {
+    t, err := pomodoro.FindTask(ctx, config, i.Task)
+    switch {
+    case err == nil:
+      i.Task, i.TaskID = t.Name, t.ID
+    case !errors.Is(err, pomodoro.ErrInvalidTask):
+      return err
+    }
+  }
+
+  i, err := pomodoro.AddManual(ctx, config, i)
+  if err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Added %s %d at %s for %s.\n", i.Category, i.ID,
+    i.StartTime.In(config.Location).Format("2006-01-02 15:04"),
+    i.ActualDuration)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(addCmd)
+
+  addCmd.Flags().String("at", "", "Start time (YYYY-MM-DDTHH:MM)")
+  addCmd.Flags().Duration("duration", 0, "Duration of the session")
+  addCmd.Flags().String("category", pomodoro.CategoryPomodoro,
+    "Category (Pomodoro, ShortBreak, LongBreak)")
+  addCmd.Flags().String("task", "", "Task worked on")
+  addCmd.Flags().String("note", "", "Note about the session")
+  addCmd.Flags().String("project", "", "Project, such as work/api")
+  addCmd.Flags().StringSlice("tag", nil, "Tags of the session")
+
+  addCmd.MarkFlagRequired("at")
+  addCmd.MarkFlagRequired("duration")
+}
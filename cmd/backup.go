@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+// backupCmd represents the backup command
+var backupCmd = &cobra.Command{
+  Use:   "backup",
+  Short: "Back up the history database",
+  Long: `Write a timestamped copy of the history database to the backup
+directory, keeping only the latest --backup-keep backups.
+
+Backups are only supported by the sqlite backend.`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    return backupAction(cmd.Context(), os.Stdout, repo, backupDir(),
+      viper.GetInt("backupkeep"))
+  },
+}
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+  Use:   "restore [backup]",
+  Short: "Restore the history database from a backup",
+  Long: `Replace the history database with a backup, after checking the
+backup's integrity. The backup is a file, or "latest" for the most recent
+one in the backup directory. Without arguments, the available backups are
+listed.
+
+The current database is backed up first, so a restore can be undone.`,
+  Args: cobra.MaximumNArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    if len(args) == 0 {
+      return listBackups(os.Stdout, backupDir())
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    yes, _ := cmd.Flags().GetBool("yes")
+
+    return restoreAction(cmd.Context(), os.Stdin, os.Stdout, repo,
+      viper.GetString("db"), args[0], viper.GetInt("backupkeep"), yes)
+  },
+}
+
+// backupDir returns the configured backup directory, by default a
+// "backups" directory next to the database file.
+func backupDir() string {
+  if dir := viper.GetString("backupdir"); dir != "" {
+    return dir
+  }
+
+  return filepath.Join(filepath.Dir(repository.Path(viper.GetString("db"))),
+    "backups")
+}
+
+func listBackups(out io.Writer, dir string) error {
+  list, err := repository.Backups(dir)
+  if err != nil {
+    return err
+  }
+
+  if len(list) == 0 {
+    _, err := fmt.Fprintln(out, "No backups in", dir)
+    return err
+  }
+
+  for _, path := range list {
+    fmt.Fprintln(out, path)
+  }
+
+  return nil
+}
+
+func backupAction(ctx context.Context, out io.Writer,
+  repo pomodoro.Repository, dir string, keep int) error {
+
+  path, err := repository.Backup(ctx, repo, dir, keep)
+  if err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintln(out, "Backup written to", path)
+  return err
+}
+
+func restoreAction(ctx context.Context, in io.Reader, out io.Writer,
+  repo pomodoro.Repository, db, backup string, keep int, yes bool) error {
+
+  dir := backupDir()
+
+  if backup == "latest" {
+    list, err := repository.Backups(dir)
+    if err != nil {
+      return err
+    }
+    if len(list) == 0 {
+      return fmt.Errorf("no backups in %s", dir)
+    }
+    backup = list[len(list)-1]
+  }
+
+  if !yes && !confirm(in, out,
+    fmt.Sprintf("Replace %s with %s?", db, backup)) {
+    _, err := fmt.Fprintln(out, "Nothing restored.")
+    return err
+  }
+
+  // Keep the current database; rotating waits until the restore is done
+  saved, err := repository.Backup(ctx, repo, dir, 0)
+  if err != nil {
+    return err
+  }
+
+  if err := repository.Restore(ctx, repo, backup); err != nil {
+    return err
+  }
+
+  // The backup restored may go now that its data is back in the database
+  if err := repository.Rotate(dir, keep); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Restored %s. The previous database was saved to %s.\n",
+    backup, saved)
+  return err
+}
+
+// autoBackup backs up the database if there's no backup of the current
+// day yet. Backends without backups are skipped.
+func autoBackup(ctx context.Context, repo pomodoro.Repository,
+  config *pomodoro.IntervalConfig, dir string, keep int) error {
+
+  list, err := repository.Backups(dir)
+  if err != nil {
+    return err
+  }
+
+  dayStart, _ := config.DayBounds(time.Now())
+  if len(list) > 0 {
+    last, err := repository.BackupTime(list[len(list)-1])
+    if err == nil && !last.Before(dayStart) {
+      return nil
+    }
+  }
+
+  _, err = repository.Backup(ctx, repo, dir, keep)
+  if errors.Is(err, repository.ErrNoBackup) {
+    return nil
+  }
+
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(backupCmd)
+  rootCmd.AddCommand(restoreCmd)
+
+  restoreCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+}
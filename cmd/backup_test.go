@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestAutoBackup(t *testing.T) {
+  ctx := context.Background()
+  dir := t.TempDir()
+  backups := filepath.Join(dir, "backups")
+
+  repo, err := repository.Open(filepath.Join(dir, "pomo.db"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  for k := 0; k < 2; k++ {
+    if err := autoBackup(ctx, repo, config, backups, 7); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  list, err := repository.Backups(backups)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 1 {
+    t.Errorf("Expected a single backup per day, got %v instead\n", list)
+  }
+
+  memory, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if err := autoBackup(ctx, memory, config, backups, 7); err != nil {
+    t.Errorf("Expected backends without backups to be skipped, got %q", err)
+  }
+}
+
+func TestBackupAction(t *testing.T) {
+  ctx := context.Background()
+  dir := t.TempDir()
+  backups := filepath.Join(dir, "backups")
+
+  repo, err := repository.Open(filepath.Join(dir, "pomo.db"))
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  var out bytes.Buffer
+  if err := backupAction(ctx, &out, repo, backups, 7); err != nil {
+    t.Fatal(err)
+  }
+
+  list, err := repository.Backups(backups)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 1 {
+    t.Fatalf("Expected 1 backup, got %v instead\n", list)
+  }
+
+  exp := "Backup written to " + list[0] + "\n"
+  if out.String() != exp {
+    t.Errorf("Expected %q, got %q instead\n", exp, out.String())
+  }
+
+  memory, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  out.Reset()
+  if err := backupAction(ctx, &out, memory, backups,
+    7); !errors.Is(err, repository.ErrNoBackup) {
+    t.Errorf("Expected error %q, got %v", repository.ErrNoBackup, err)
+  }
+  if out.Len() != 0 {
+    t.Errorf("Expected no output, got %q", out.String())
+  }
+}
+
+func TestRestoreAction(t *testing.T) {
+  ctx := context.Background()
+  dir := t.TempDir()
+  db := filepath.Join(dir, "pomo.db")
+
+  viper.Set("db", db)
+  defer viper.Set("db", nil)
+
+  repo, err := repository.Open(db)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  create := func() {
+    if _, err := repo.Create(ctx, pomodoro.Interval{
+      StartTime:       time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+      PlannedDuration: 25 * time.Minute,
+      Category:        pomodoro.CategoryPomodoro,
+    }); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  create()
+  if _, err := repository.Backup(ctx, repo, backupDir(), 0); err != nil {
+    t.Fatal(err)
+  }
+  time.Sleep(2 * time.Millisecond)
+  create()
+
+  var out bytes.Buffer
+  if err := restoreAction(ctx, strings.NewReader("y\n"), &out, repo, db,
+    "latest", 0, false); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "The previous database was saved") {
+    t.Errorf("Expected restore message, got:\n%s", out.String())
+  }
+
+  list, err := repo.List(ctx, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 1 {
+    t.Errorf("Expected 1 interval after restore, got %d instead\n", len(list))
+  }
+
+  backups, err := repository.Backups(filepath.Join(dir, "backups"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(backups) != 2 {
+    t.Errorf("Expected the database to be saved before restoring, got %v\n",
+      backups)
+  }
+
+  // The backups saved before restoring are rotated like the others
+  time.Sleep(2 * time.Millisecond)
+  out.Reset()
+  if err := restoreAction(ctx, nil, &out, repo, db, backups[0], 1,
+    true); err != nil {
+    t.Fatal(err)
+  }
+
+  backups, err = repository.Backups(filepath.Join(dir, "backups"))
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(backups) != 1 {
+    t.Errorf("Expected 1 backup kept after restoring, got %v\n", backups)
+  }
+}
@@ -0,0 +1,172 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/chart"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// chartCmd represents the chart command
+var chartCmd = &cobra.Command{
+  Use:   "chart",
+  Short: "Draw the dashboard charts as SVG or PNG images",
+  Long: `Draw one of the dashboard charts as an image to attach to documents
+and chat messages: the focus and break time of each of the last days
+(--period week, the default), or of a single day (--period day). The
+numbers are the ones shown on the dashboard, in the colors of the theme.
+
+The format is taken from the extension of the output file, or set with
+--format. Use --project or --tag to chart part of the history only.`,
+  Example: `  pomo chart -o week.svg
+  pomo chart --period day --date 2026-10-12 -o day.png`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    s, err := getSettings()
+    if err != nil {
+      return err
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := newIntervalConfig(repo, s)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    output, _ := flags.GetString("output")
+    name, _ := flags.GetString("format")
+    if !flags.Changed("format") && output != "" && output != "-" {
+      name = strings.TrimPrefix(filepath.Ext(output), ".")
+    }
+    format, err := chart.ParseFormat(name)
+    if err != nil {
+      return err
+    }
+
+    day := time.Now()
+    if date, _ := flags.GetString("date"); date != "" {
+      if day, err = parseTime(date, config.Location); err != nil {
+        return err
+      }
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+    config.SetFilter(f)
+
+    colors, err := themeColors(s.Theme)
+    if err != nil {
+      return err
+    }
+
+    period, _ := flags.GetString("period")
+    days, _ := flags.GetInt("days")
+    c, err := dashboardChart(cmd.Context(), config, period, day, days, colors)
+    if err != nil {
+      return err
+    }
+    c.Width, _ = flags.GetInt("width")
+    c.Height, _ = flags.GetInt("height")
+
+    return chartAction(os.Stdout, c, format, output)
+  },
+}
+
+// themeColors returns the pomodoro and break colors of the theme.
+func themeColors(th settings.Theme) ([]color.RGBA, error) {
+  var colors []color.RGBA
+  for _, name := range []string{th.Pomodoro, th.Break} {
+    n, err := settings.Color(name)
+    if err != nil {
+      return nil, err
+    }
+    colors = append(colors, chart.TermColor(n))
+  }
+
+  return colors, nil
+}
+
+// dashboardChart returns the chart of the day, or of the days days up to
+// day, computed as on the dashboard.
+func dashboardChart(ctx context.Context, config *pomodoro.IntervalConfig,
+  period string, day time.Time, days int,
+  colors []color.RGBA) (chart.Chart, error) {
+
+  switch period {
+  case "day":
+    ds, err := pomodoro.DailySummary(ctx, day, config)
+    if err != nil {
+      return chart.Chart{}, err
+    }
+    return chart.Day(day, ds, colors), nil
+  case "week":
+    if days < 1 {
+      return chart.Chart{}, fmt.Errorf("Invalid number of days %d", days)
+    }
+    ws, err := pomodoro.RangeSummary(ctx, day, days, config)
+    if err != nil {
+      return chart.Chart{}, err
+    }
+    return chart.Week(ws, colors), nil
+  }
+
+  return chart.Chart{}, fmt.Errorf("Unknown period %q, use day or week",
+    period)
+}
+
+// chartAction writes c as an image in format to output, or to out if
+// it's empty or "-".
+func chartAction(out io.Writer, c chart.Chart, format,
+  output string) error {
+
+  if output == "" || output == "-" {
+    return c.Write(out, format)
+  }
+
+  var buf bytes.Buffer
+  if err := c.Write(&buf, format); err != nil {
+    return err
+  }
+
+  if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+    return err
+  }
+
+  _, err := fmt.Fprintf(out, "Wrote the chart to %s.\n", output)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(chartCmd)
+
+  chartCmd.Flags().String("period", "week", "day or week")
+  chartCmd.Flags().Int("days", 7, "Number of days charted by week")
+  chartCmd.Flags().String("date", "", "Last day charted (default today)")
+  chartCmd.Flags().StringP("format", "f", chart.FormatSVG,
+    "svg or png (default from the output file)")
+  chartCmd.Flags().StringP("output", "o", "", "File to write, - for stdout")
+  chartCmd.Flags().Int("width", 640, "Width of the image in pixels")
+  chartCmd.Flags().Int("height", 320, "Height of the image in pixels")
+  chartCmd.Flags().String("project", "",
+    "Only chart this project and its subprojects")
+  chartCmd.Flags().String("tag", "", "Only chart pomodoros with this tag")
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/chart"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+func TestChartAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  day := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+  var out bytes.Buffer
+  for k := 0; k < 2; k++ {
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  colors, err := themeColors(settings.Defaults().Theme)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if colors[0] != (color.RGBA{0x00, 0x00, 0xee, 0xff}) {
+    t.Errorf("Expected the default pomodoro color to be blue, got %v",
+      colors[0])
+  }
+
+  c, err := dashboardChart(ctx, config, "week", day.AddDate(0, 0, 1), 7,
+    colors)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  out.Reset()
+  if err := chartAction(&out, c, chart.FormatSVG, "-"); err != nil {
+    t.Fatal(err)
+  }
+  for _, exp := range []string{
+    "<title>Pomodoro 14/Oct: 50m0s</title>",
+    ">15/Oct</text>",
+  } {
+    if !strings.Contains(out.String(), exp) {
+      t.Errorf("Expected %q in:\n%s", exp, out.String())
+    }
+  }
+
+  c, err = dashboardChart(ctx, config, "day", day, 0, colors)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  output := filepath.Join(t.TempDir(), "day.png")
+  out.Reset()
+  if err := chartAction(&out, c, chart.FormatPNG, output); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Wrote the chart to") {
+    t.Errorf("Expected the chart written, got %q", out.String())
+  }
+
+  f, err := os.Open(output)
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer f.Close()
+  if _, err := png.Decode(f); err != nil {
+    t.Errorf("Expected a PNG image, got %q", err)
+  }
+
+  if _, err := dashboardChart(ctx, config, "year", day, 0,
+    colors); err == nil {
+    t.Error("Expected an error for an unknown period")
+  }
+}
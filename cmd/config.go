@@ -0,0 +1,273 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+  Use:   "config",
+  Short: "Show and change settings",
+  Long: `Show and change the settings stored in the config file.
+
+Settings come from flags, environment variables named after the keys in
+upper case with a POMO_ prefix, such as POMO_DAYSTART, and the config
+file, in that order of precedence. Run "pomo config list" for the keys.`,
+}
+
+// configListCmd represents the config list command
+var configListCmd = &cobra.Command{
+  Use:   "list",
+  Short: "List all settings and their values",
+  Args:  cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return configListAction(os.Stdout)
+  },
+}
+
+// configGetCmd represents the config get command
+var configGetCmd = &cobra.Command{
+  Use:   "get <key>",
+  Short: "Show the value of a setting",
+  Args:  cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return configGetAction(os.Stdout, args[0])
+  },
+}
+
+// configSetCmd represents the config set command
+var configSetCmd = &cobra.Command{
+  Use:   "set <key> <value>",
+  Short: "Change a setting in the config file",
+  Long: `Change a setting in the config file, creating the file if needed.
+The file is only written if the result is valid.`,
+  Args: cobra.ExactArgs(2),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return configSetAction(os.Stdout, configFile(), args[0], args[1])
+  },
+}
+
+// configValidateCmd represents the config validate command
+var configValidateCmd = &cobra.Command{
+  Use:   "validate",
+  Short: "Check the config file",
+  Args:  cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return configValidateAction(os.Stdout, configFile())
+  },
+}
+
+// configEditCmd represents the config edit command
+var configEditCmd = &cobra.Command{
+  Use:   "edit",
+  Short: "Open the config file in an editor",
+  Long: `Open the config file in $VISUAL or $EDITOR, creating it if needed,
+and check it once the editor exits.`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    path := configFile()
+    if err := createConfigFile(path); err != nil {
+      return err
+    }
+
+    editor := exec.Command(editorCommand(), path)
+    editor.Stdin = os.Stdin
+    editor.Stdout = os.Stdout
+    editor.Stderr = os.Stderr
+    if err := editor.Run(); err != nil {
+      return err
+    }
+
+    return configValidateAction(os.Stdout, path)
+  },
+}
+
+// configFile returns the config file in use, or the one to create.
+func configFile() string {
+  if path := viper.ConfigFileUsed(); path != "" {
+    return path
+  }
+
+  if cfgFile != "" {
+    return cfgFile
+  }
+
+  return filepath.Join(configDir(), "config.yaml")
+}
+
+func configListAction(out io.Writer) error {
+  if configErr != nil {
+    return configErr
+  }
+
+  // The values are listed even if they're invalid, to help fix them
+  s, err := settings.Load(viper.GetViper())
+  if err != nil && !errors.Is(err, settings.ErrInvalid) {
+    return err
+  }
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  for _, key := range s.Names() {
+    value, _ := s.Get(key)
+    fmt.Fprintf(w, "%s\t%s\n", key, value)
+  }
+  if err := w.Flush(); err != nil {
+    return err
+  }
+
+  return err
+}
+
+func configGetAction(out io.Writer, key string) error {
+  s, err := getSettings()
+  if err != nil {
+    return err
+  }
+
+  value, err := s.Get(key)
+  if err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintln(out, value)
+  return err
+}
+
+// readConfigFile reads only the config file at path, which may not exist
+// yet.
+func readConfigFile(path string) (*viper.Viper, error) {
+  v := viper.New()
+  v.SetConfigFile(path)
+
+  if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+    return v, nil
+  }
+
+  if err := v.ReadInConfig(); err != nil {
+    return nil, fmt.Errorf("reading config file: %w", err)
+  }
+
+  return v, nil
+}
+
+// reloadSettings loads the settings of v again after its config file
+// changed. viper keeps the previous values if the file can't be parsed, so
+// that's checked first.
+func reloadSettings(v *viper.Viper) (settings.Settings, error) {
+  if _, err := readConfigFile(v.ConfigFileUsed()); err != nil {
+    return settings.Settings{}, err
+  }
+
+  return settings.Load(v)
+}
+
+// checkConfig validates the settings in file, with defaults for the
+// missing ones.
+func checkConfig(file *viper.Viper) error {
+  v := viper.New()
+  settings.SetDefaults(v)
+  v.SetDefault("db", defaultDB())
+
+  if err := v.MergeConfigMap(file.AllSettings()); err != nil {
+    return err
+  }
+
+  _, err := settings.Load(v)
+  return err
+}
+
+func configSetAction(out io.Writer, path, key, value string) error {
+  parsed, err := settings.Parse(key, value)
+  if err != nil {
+    return err
+  }
+
+  file, err := readConfigFile(path)
+  if err != nil {
+    return err
+  }
+
+  file.Set(key, parsed)
+  if err := checkConfig(file); err != nil {
+    return err
+  }
+
+  if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+    return err
+  }
+
+  if err := file.WriteConfigAs(path); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Set %s to %v in %s\n", key, parsed, path)
+  return err
+}
+
+func configValidateAction(out io.Writer, path string) error {
+  if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+    _, err := fmt.Fprintf(out, "%s doesn't exist, using defaults\n", path)
+    return err
+  }
+
+  file, err := readConfigFile(path)
+  if err != nil {
+    return err
+  }
+
+  if err := checkConfig(file); err != nil {
+    return fmt.Errorf("%s: %w", path, err)
+  }
+
+  _, err = fmt.Fprintf(out, "%s is valid\n", path)
+  return err
+}
+
+// createConfigFile creates an empty config file at path if there's none.
+func createConfigFile(path string) error {
+  if _, err := os.Stat(path); err == nil {
+    return nil
+  }
+
+  if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+    return err
+  }
+
+  return os.WriteFile(path, nil, 0o644)
+}
+
+// editorCommand returns the user's editor.
+func editorCommand() string {
+  for _, env := range []string{"VISUAL", "EDITOR"} {
+    if editor := os.Getenv(env); editor != "" {
+      return editor
+    }
+  }
+
+  if runtime.GOOS == "windows" {
+    return "notepad"
+  }
+
+  return "vi"
+}
+
+func init() {
+  rootCmd.AddCommand(configCmd)
+
+  configCmd.AddCommand(configListCmd)
+  configCmd.AddCommand(configGetCmd)
+  configCmd.AddCommand(configSetCmd)
+  configCmd.AddCommand(configValidateCmd)
+  configCmd.AddCommand(configEditCmd)
+}
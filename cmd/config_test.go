@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+func TestConfigSetAction(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "pomanalyzer", "config.yaml")
+  var out bytes.Buffer
+
+  if err := configSetAction(&out, path, "pomo", "30m"); err != nil {
+    t.Fatal(err)
+  }
+  if err := configSetAction(&out, path, "keys.start", "g"); err != nil {
+    t.Fatal(err)
+  }
+
+  testCases := []struct {
+    name  string
+    key   string
+    value string
+  }{
+    {name: "UnknownKey", key: "bogus", value: "1"},
+    {name: "WrongType", key: "cycle", value: "four"},
+    {name: "Invalid", key: "short", value: "0s"},
+    {name: "ReservedKey", key: "keys.quit", value: "x"},
+    {name: "DuplicateKey", key: "keys.pause", value: "g"},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      err := configSetAction(&out, path, tc.key, tc.value)
+      if !errors.Is(err, settings.ErrInvalid) {
+        t.Errorf("Expected error %q, got %q instead\n", settings.ErrInvalid,
+          err)
+      }
+    })
+  }
+
+  data, err := os.ReadFile(path)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  for _, expected := range []string{"pomo: 30m", "start: g"} {
+    if !strings.Contains(string(data), expected) {
+      t.Errorf("Expected config file to contain %q, got:\n%s", expected,
+        data)
+    }
+  }
+
+  if err := configValidateAction(&out, path); err != nil {
+    t.Errorf("Expected config file to be valid, got %q", err)
+  }
+}
+
+func TestConfigValidateAction(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "config.yaml")
+  data := "pomo: 1ms\ncycle: 0\ncolour: red\n"
+  if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+    t.Fatal(err)
+  }
+
+  var out bytes.Buffer
+  err := configValidateAction(&out, path)
+  if !errors.Is(err, settings.ErrInvalid) {
+    t.Fatalf("Expected error %q, got %q instead\n", settings.ErrInvalid, err)
+  }
+
+  for _, key := range []string{"pomo", "cycle", "colour"} {
+    if !strings.Contains(err.Error(), key+":") {
+      t.Errorf("Expected error to report %q, got %q", key, err)
+    }
+  }
+}
+
+func TestReloadSettings(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "config.yaml")
+  if err := os.WriteFile(path, []byte("pomo: 40m\n"), 0o644); err != nil {
+    t.Fatal(err)
+  }
+
+  v := viper.New()
+  v.SetConfigFile(path)
+  settings.SetDefaults(v)
+  if err := v.ReadInConfig(); err != nil {
+    t.Fatal(err)
+  }
+
+  s, err := reloadSettings(v)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if s.Pomodoro != 40*time.Minute {
+    t.Errorf("Expected pomo 40m, got %s instead\n", s.Pomodoro)
+  }
+
+  // viper keeps the previous values when the file can't be parsed
+  if err := os.WriteFile(path, []byte("pomo: [40m\n"), 0o644); err != nil {
+    t.Fatal(err)
+  }
+  v.ReadInConfig()
+
+  if _, err := reloadSettings(v); err == nil {
+    t.Error("Expected an error for a config file that can't be parsed")
+  }
+}
+
+func TestReadEnv(t *testing.T) {
+  path := filepath.Join(t.TempDir(), "config.yaml")
+  if err := os.WriteFile(path, []byte("user: alice\n"), 0o644); err != nil {
+    t.Fatal(err)
+  }
+  t.Setenv("USER", "bob")
+
+  v := viper.New()
+  v.SetConfigFile(path)
+  settings.SetDefaults(v)
+  readEnv(v)
+  if err := v.ReadInConfig(); err != nil {
+    t.Fatal(err)
+  }
+
+  if user := v.GetString("user"); user != "alice" {
+    t.Errorf("Expected user alice from the config file, got %q", user)
+  }
+
+  t.Setenv("POMO_USER", "carol")
+  if user := v.GetString("user"); user != "carol" {
+    t.Errorf("Expected user carol from POMO_USER, got %q", user)
+  }
+}
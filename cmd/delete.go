@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+  Use:   "delete <id>",
+  Short: "Delete an interval from the history",
+  Args:  cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    id, err := strconv.ParseInt(args[0], 10, 64)
+    if err != nil {
+      return fmt.Errorf("%w: %s", pomodoro.ErrInvalidID, args[0])
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    yes, _ := cmd.Flags().GetBool("yes")
+
+    return deleteAction(cmd.Context(), os.Stdin, os.Stdout, config, id, yes)
+  },
+}
+
+func deleteAction(ctx context.Context, in io.Reader, out io.Writer,
+  config *pomodoro.IntervalConfig, id int64, yes bool) error {
+
+  i, err := pomodoro.Get(ctx, config, id)
+  if err != nil {
+    return err
+  }
+
+  if err := logAction(out, config, []pomodoro.Interval{i}); err != nil {
+    return err
+  }
+
+  if !yes && !confirm(in, out, "Delete this interval?") {
+    _, err := fmt.Fprintln(out, "Nothing deleted.")
+    return err
+  }
+
+  if err := pomodoro.Delete(ctx, config, id); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Interval %d deleted.\n", id)
+  return err
+}
+
+// confirm asks a yes/no question, defaulting to no.
+func confirm(in io.Reader, out io.Writer, question string) bool {
+  fmt.Fprintf(out, "%s [y/N] ", question)
+
+  answer, err := bufio.NewReader(in).ReadString('\n')
+  if err != nil && answer == "" {
+    return false
+  }
+
+  answer = strings.ToLower(strings.TrimSpace(answer))
+  return answer == "y" || answer == "yes"
+}
+
+func init() {
+  rootCmd.AddCommand(deleteCmd)
+
+  deleteCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestConfirm(t *testing.T) {
+  testCases := []struct {
+    in  string
+    exp bool
+  }{
+    {in: "y\n", exp: true},
+    {in: "YES\n", exp: true},
+    {in: "n\n", exp: false},
+    {in: "\n", exp: false},
+    {in: "", exp: false},
+  }
+
+  for _, tc := range testCases {
+    var out bytes.Buffer
+    if res := confirm(strings.NewReader(tc.in), &out, "Sure?"); res != tc.exp {
+      t.Errorf("Expected %t for answer %q, got %t instead\n", tc.exp, tc.in, res)
+    }
+    if out.String() != "Sure? [y/N] " {
+      t.Errorf("Unexpected prompt %q\n", out.String())
+    }
+  }
+}
+
+func TestDeleteAction(t *testing.T) {
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  id, err := repo.Create(ctx, pomodoro.Interval{
+    PlannedDuration: 25 * time.Minute,
+    Category:        pomodoro.CategoryPomodoro,
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  var out bytes.Buffer
+  if err := deleteAction(ctx, strings.NewReader("n\n"), &out, config, id,
+    false); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := repo.ByID(ctx, id); err != nil {
+    t.Fatalf("Expected interval to be kept, got %q", err)
+  }
+
+  out.Reset()
+  if err := deleteAction(ctx, strings.NewReader("y\n"), &out, config, id,
+    false); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Interval 1 deleted.") {
+    t.Errorf("Expected deletion message, got:\n%s", out.String())
+  }
+  if _, err := repo.ByID(ctx, id); !errors.Is(err, pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidID, err)
+  }
+}
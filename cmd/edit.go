@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+  Use:   "edit <id>",
+  Short: "Edit an interval of the history",
+  Long: `Edit the start time, durations, category, state, task, project,
+tags, note or interruptions of an interval. Every change is recorded in the
+audit trail.
+
+--tag replaces all the tags of the interval, and --tag "" removes them.
+
+Without any flags, the interval and its audit trail are shown.`,
+  Args: cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    id, err := strconv.ParseInt(args[0], 10, 64)
+    if err != nil {
+      return fmt.Errorf("%w: %s", pomodoro.ErrInvalidID, args[0])
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+    config.AllowOvertime, _ = cmd.Flags().GetBool("allow-overtime")
+
+    edit, err := editFunc(cmd, config)
+    if err != nil {
+      return err
+    }
+
+    return editAction(cmd.Context(), os.Stdout, config, id, edit)
+  },
+}
+
+// editFunc returns the edit described by the flags that were set, or nil
+// if none were.
+func editFunc(cmd *cobra.Command,
+  config *pomodoro.IntervalConfig) (func(*pomodoro.Interval), error) {
+
+  flags := cmd.Flags()
+  edits := []func(*pomodoro.Interval){}
+
+  if flags.Changed("start") {
+    value, _ := flags.GetString("start")
+    t, err := parseTime(value, config.Location)
+    if err != nil {
+      return nil, err
+    }
+    edits = append(edits, func(i *pomodoro.Interval) { i.StartTime = t })
+  }
+
+  if flags.Changed("planned") {
+    d, _ := flags.GetDuration("planned")
+    edits = append(edits, func(i *pomodoro.Interval) { i.PlannedDuration = d })
+  }
+
+  if flags.Changed("actual") {
+    d, _ := flags.GetDuration("actual")
+    edits = append(edits, func(i *pomodoro.Interval) { i.ActualDuration = d })
+  }
+
+  if flags.Changed("category") {
+    c, _ := flags.GetString("category")
+    edits = append(edits, func(i *pomodoro.Interval) { i.Category = c })
+  }
+
+  if flags.Changed("state") {
+    name, _ := flags.GetString("state")
+    s, err := pomodoro.ParseState(name)
+    if err != nil {
+      return nil, err
+    }
+    edits = append(edits, func(i *pomodoro.Interval) { i.State = s })
+  }
+
+  if flags.Changed("task") {
+    task, _ := flags.GetString("task")
+    edits = append(edits, func(i *pomodoro.Interval) { i.Task = task })
+  }
+
+  if flags.Changed("project") {
+    project, _ := flags.GetString("project")
+    p, err := pomodoro.ParseProject(project)
+    if err != nil {
+      return nil, err
+    }
+    edits = append(edits, func(i *pomodoro.Interval) { i.Project = p })
+  }
+
+  if flags.Changed("tag") {
+    list, _ := flags.GetStringSlice("tag")
+    tags, err := pomodoro.ParseTags(list)
+    if err != nil {
+      return nil, err
+    }
+    edits = append(edits, func(i *pomodoro.Interval) { i.Tags = tags })
+  }
+
+  if flags.Changed("note") {
+    note, _ := flags.GetString("note")
+    edits = append(edits, func(i *pomodoro.Interval) { i.Note = note })
+  }
+
+  if flags.Changed("internal") {
+    n, _ := flags.GetInt("internal")
+    edits = append(edits, func(i *pomodoro.Interval) {
+      i.InternalInterruptions = n
+    })
+  }
+
+  if flags.Changed("external") {
+    n, _ := flags.GetInt("external")
+    edits = append(edits, func(i *pomodoro.Interval) {
+      i.ExternalInterruptions = n
+    })
+  }
+
+  if len(edits) == 0 {
+    return nil, nil
+  }
+
+  return func(i *pomodoro.Interval) {
+    for _, e := range edits {
+      e(i)
+    }
+  }, nil
+}
+
+func editAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, id int64,
+  edit func(*pomodoro.Interval)) error {
+
+  if edit != nil {
+    if _, err := pomodoro.Edit(ctx, config, id, edit); err != nil {
+      return err
+    }
+  }
+
+  i, err := pomodoro.Get(ctx, config, id)
+  if err != nil {
+    return err
+  }
+
+  if err := logAction(out, config, []pomodoro.Interval{i}); err != nil {
+    return err
+  }
+
+  changes, err := pomodoro.Changes(ctx, config, id)
+  if err != nil {
+    return err
+  }
+
+  return printChanges(out, config, changes)
+}
+
+// printChanges writes the audit trail as a table.
+func printChanges(out io.Writer, config *pomodoro.IntervalConfig,
+  changes []pomodoro.Change) error {
+
+  if len(changes) == 0 {
+    _, err := fmt.Fprintln(out, "\nNo changes recorded.")
+    return err
+  }
+
+  fmt.Fprintln(out)
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintln(w, "CHANGED\tINTERVAL\tFIELD\tOLD\tNEW")
+
+  for _, c := range changes {
+    fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n",
+      c.Time.In(config.Location).Format("2006-01-02 15:04"),
+      c.IntervalID, c.Field, c.OldValue, c.NewValue)
+  }
+
+  return w.Flush()
+}
+
+func init() {
+  rootCmd.AddCommand(editCmd)
+
+  editCmd.Flags().String("start", "", "Start time (YYYY-MM-DDTHH:MM)")
+  editCmd.Flags().Duration("planned", 0, "Planned duration")
+  editCmd.Flags().Duration("actual", 0, "Actual duration")
+  editCmd.Flags().String("category", "",
+    "Category (Pomodoro, ShortBreak, LongBreak)")
+  editCmd.Flags().String("state", "",
+    "State (NotStarted, Paused, Done, Cancelled)")
+  editCmd.Flags().String("task", "", "Task")
+  editCmd.Flags().String("project", "", "Project")
+  editCmd.Flags().StringSlice("tag", nil, "Tags, replacing the current ones")
+  editCmd.Flags().String("note", "", "Note")
+  editCmd.Flags().Int("internal", 0, "Number of internal interruptions")
+  editCmd.Flags().Int("external", 0, "Number of external interruptions")
+  editCmd.Flags().Bool("allow-overtime", false,
+    "Allow an actual duration longer than the planned one")
+}
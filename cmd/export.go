@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/timesheet"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+  Use:   "export",
+  Short: "Export done pomodoros to Timewarrior, Toggl or Clockify",
+  Long: `Export the pomodoros done over a period, the current month by
+default, for time tracking tools: Timewarrior interval lines, or the CSV
+imported by Toggl Track or Clockify.
+
+Projects and task names are mapped to the ones the tools know with the
+export.projects and export.tasks settings, and the Toggl and Clockify
+entries belong to export.email. Use --project or --tag to export part of
+the history only.`,
+  Example: `  pomo export -f toggl -o october.csv
+  pomo export -f timewarrior --project work >> ~/.timewarrior/data/2026-10.data`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    s, err := getSettings()
+    if err != nil {
+      return err
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := newIntervalConfig(repo, s)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    name, _ := flags.GetString("format")
+    format, err := timesheet.ParseFormat(name)
+    if err != nil {
+      return err
+    }
+
+    from, _ := flags.GetString("from")
+    to, _ := flags.GetString("to")
+    start, end, err := monthPeriod(config, from, to, time.Now())
+    if err != nil {
+      return err
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+
+    output, _ := flags.GetString("output")
+    o := timesheet.Options{
+      Mapping:  s.Export.Mapping(),
+      Email:    s.Export.Email,
+      Location: config.Location,
+    }
+
+    q := pomodoro.Query{
+      Start:         start,
+      End:           end,
+      Project:       f.Project,
+      Tag:           f.Tag,
+      ExcludeManual: config.ExcludeManual,
+    }
+
+    return exportAction(cmd.Context(), os.Stdout, config, q, format, o,
+      output)
+  },
+}
+
+// exportAction writes the done pomodoros selected by q in format to
+// output, or to out if it's empty or "-".
+func exportAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, q pomodoro.Query, format string,
+  o timesheet.Options, output string) error {
+
+  q.Categories = []string{pomodoro.CategoryPomodoro}
+  q.States = []int{pomodoro.StateDone}
+
+  list, err := pomodoro.List(ctx, config, q)
+  if err != nil {
+    return err
+  }
+
+  if output == "" || output == "-" {
+    return timesheet.Write(out, format, list, o)
+  }
+
+  var buf bytes.Buffer
+  if err := timesheet.Write(&buf, format, list, o); err != nil {
+    return err
+  }
+
+  if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Exported %d pomodoros to %s.\n", len(list),
+    output)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(exportCmd)
+
+  exportCmd.Flags().StringP("format", "f", timesheet.FormatTimewarrior,
+    "timewarrior, toggl or clockify")
+  exportCmd.Flags().StringP("output", "o", "", "File to write, - for stdout")
+  exportCmd.Flags().String("from", "",
+    "Start of the period (default start of the month)")
+  exportCmd.Flags().String("to", "", "End of the period (default today)")
+  exportCmd.Flags().String("project", "",
+    "Only export this project and its subprojects")
+  exportCmd.Flags().String("tag", "", "Only export pomodoros with this tag")
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+	"github.com/xasterKies/pomanalyzer/timesheet"
+)
+
+func TestExportAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  sessions := []pomodoro.Interval{
+    {StartTime: day, Category: pomodoro.CategoryPomodoro, Task: "report",
+      Project: "work/api"},
+    {StartTime: day.Add(time.Hour), Category: pomodoro.CategoryPomodoro,
+      Project: "home"},
+    {StartTime: day.Add(2 * time.Hour), Category: pomodoro.CategoryShortBreak},
+  }
+
+  var out bytes.Buffer
+  for _, i := range sessions {
+    i.ActualDuration = 25 * time.Minute
+    if err := addAction(ctx, &out, config, i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  q := pomodoro.Query{Start: day.Add(-time.Hour), End: day.Add(24 * time.Hour),
+    Project: "work"}
+  o := timesheet.Options{
+    Mapping:  timesheet.Mapping{Projects: map[string]string{"work": "Acme"}},
+    Email:    "me@example.com",
+    Location: time.UTC,
+  }
+
+  out.Reset()
+  err = exportAction(ctx, &out, config, q, timesheet.FormatToggl, o, "")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  exp := "me@example.com,2026-10-16,09:00:00,00:25:00,Acme,report,\n"
+  if lines := strings.Split(out.String(), "\n"); len(lines) != 3 ||
+    lines[1]+"\n" != exp {
+    t.Errorf("Expected only %q exported, got:\n%s", exp, out.String())
+  }
+
+  // Breaks are left out
+  q.Project = ""
+  output := filepath.Join(t.TempDir(), "pomodoros.data")
+
+  out.Reset()
+  err = exportAction(ctx, &out, config, q, timesheet.FormatTimewarrior, o,
+    output)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Exported 2 pomodoros") {
+    t.Errorf("Expected 2 pomodoros exported, got %q", out.String())
+  }
+
+  data, err := os.ReadFile(output)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if !strings.HasPrefix(string(data),
+    "inc 20261016T090000Z - 20261016T092500Z # Acme report\n") {
+    t.Errorf("Unexpected Timewarrior export:\n%s", data)
+  }
+}
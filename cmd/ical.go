@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/ical"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// icalCmd represents the ical command
+var icalCmd = &cobra.Command{
+  Use:   "ical",
+  Short: "Export done pomodoros to an iCalendar file",
+  Long: `Write the pomodoros done over a period, the current month by default,
+as events of an iCalendar (.ics) file to import or subscribe to in a
+calendar app. Each event is titled with the task and categorized with the
+project and tags of its pomodoro, and exporting again updates the events
+already imported.
+
+Use --project or --tag to export part of the history only.`,
+  Example: `  pomo ical -o ~/focus.ics
+  pomo ical --from 2026-10-01 --project work -o work.ics`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    from, _ := flags.GetString("from")
+    to, _ := flags.GetString("to")
+    start, end, err := monthPeriod(config, from, to, time.Now())
+    if err != nil {
+      return err
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+
+    q := pomodoro.Query{
+      Start:         start,
+      End:           end,
+      Project:       f.Project,
+      Tag:           f.Tag,
+      ExcludeManual: config.ExcludeManual,
+    }
+    output, _ := flags.GetString("output")
+
+    return icalAction(cmd.Context(), os.Stdout, config, q, output,
+      time.Now())
+  },
+}
+
+// icalAction writes the done pomodoros selected by q as an iCalendar file
+// to output, or to out if it's empty or "-".
+func icalAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, q pomodoro.Query, output string,
+  now time.Time) error {
+
+  q.Categories = []string{pomodoro.CategoryPomodoro}
+  q.States = []int{pomodoro.StateDone}
+
+  list, err := pomodoro.List(ctx, config, q)
+  if err != nil {
+    return err
+  }
+
+  if output == "" || output == "-" {
+    return ical.Write(out, list, now)
+  }
+
+  var buf bytes.Buffer
+  if err := ical.Write(&buf, list, now); err != nil {
+    return err
+  }
+
+  if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Wrote %d pomodoros to %s.\n", len(list), output)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(icalCmd)
+
+  icalCmd.Flags().StringP("output", "o", "", "File to write, - for stdout")
+  icalCmd.Flags().String("from", "",
+    "Start of the period (default start of the month)")
+  icalCmd.Flags().String("to", "", "End of the period (default today)")
+  icalCmd.Flags().String("project", "",
+    "Only export this project and its subprojects")
+  icalCmd.Flags().String("tag", "", "Only export pomodoros with this tag")
+}
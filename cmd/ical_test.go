@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/ical"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestIcalAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  var out bytes.Buffer
+  for k, task := range []string{"report", "review"} {
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           task,
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  // The interval waiting to start isn't exported
+  if _, err := pomodoro.GetInterval(ctx, config); err != nil {
+    t.Fatal(err)
+  }
+
+  output := filepath.Join(t.TempDir(), "focus.ics")
+  q := pomodoro.Query{Start: day, End: day.Add(24 * time.Hour)}
+
+  out.Reset()
+  if err := icalAction(ctx, &out, config, q, output, day); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Wrote 2 pomodoros") {
+    t.Errorf("Expected 2 pomodoros written, got %q", out.String())
+  }
+
+  f, err := os.Open(output)
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer f.Close()
+
+  c, err := ical.Read(f, time.UTC)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  meetings := c.Meetings(day, day.Add(24*time.Hour))
+  if len(meetings) != 2 || meetings[1].Summary != "Pomodoro: review" ||
+    !meetings[1].Start.Equal(day.Add(time.Hour)) {
+    t.Errorf("Expected the 2 pomodoros as events, got %v", meetings)
+  }
+}
@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+  Use:   "log",
+  Short: "Show the history of intervals",
+  Long: `Show the history of intervals as a table.
+
+By default the intervals of the current day are shown. Use --day, or
+--from and --to, to select another period, or --all for the whole history.`,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    q, err := logQuery(cmd, config)
+    if err != nil {
+      return err
+    }
+
+    return logQueryAction(cmd.Context(), os.Stdout, config, q)
+  },
+}
+
+// logQueryAction shows the intervals selected by q, limited to the
+// configured user in shared databases.
+func logQueryAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, q pomodoro.Query) error {
+
+  intervals, err := pomodoro.List(ctx, config, q)
+  if err != nil {
+    return err
+  }
+
+  return logAction(out, config, intervals)
+}
+
+// logQuery builds the repository query from the log command flags.
+func logQuery(cmd *cobra.Command,
+  config *pomodoro.IntervalConfig) (pomodoro.Query, error) {
+
+  q := pomodoro.Query{}
+  flags := cmd.Flags()
+
+  all, _ := flags.GetBool("all")
+  day, _ := flags.GetString("day")
+  from, _ := flags.GetString("from")
+  to, _ := flags.GetString("to")
+
+  switch {
+  case all:
+  case day != "":
+    t, err := parseTime(day, config.Location)
+    if err != nil {
+      return q, err
+    }
+    q.Start, q.End = config.DayBounds(t)
+  case from != "" || to != "":
+    var err error
+    if from != "" {
+      if q.Start, err = parseTime(from, config.Location); err != nil {
+        return q, err
+      }
+    }
+    if to != "" {
+      if q.End, err = parseTime(to, config.Location); err != nil {
+        return q, err
+      }
+    }
+  default:
+    q.Start, q.End = config.DayBounds(time.Now())
+  }
+
+  q.Categories, _ = flags.GetStringSlice("category")
+  q.Task, _ = flags.GetString("task")
+  project, _ := flags.GetString("project")
+  tag, _ := flags.GetString("tag")
+  f, err := parseFilter(project, tag)
+  if err != nil {
+    return q, err
+  }
+  q.Project, q.Tag = f.Project, f.Tag
+  q.Limit, _ = flags.GetInt("limit")
+  q.Offset, _ = flags.GetInt("offset")
+  q.Descending, _ = flags.GetBool("reverse")
+  q.ExcludeManual = config.ExcludeManual
+
+  states, _ := flags.GetStringSlice("state")
+  for _, name := range states {
+    s, err := pomodoro.ParseState(name)
+    if err != nil {
+      return q, err
+    }
+    q.States = append(q.States, s)
+  }
+
+  return q, nil
+}
+
+func logAction(out io.Writer, config *pomodoro.IntervalConfig,
+  intervals []pomodoro.Interval) error {
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+
+  fmt.Fprintln(w,
+    "ID\tSTART\tCATEGORY\tTASK\tPROJECT\tTAGS\tPLANNED\tACTUAL\tSTATE\t"+
+      "INTERRUPTIONS\tNOTE")
+
+  var focus time.Duration
+  var pomodoros, internal, external int
+  byProfile := map[string]time.Duration{}
+  for _, i := range intervals {
+    start := "-"
+    if !i.StartTime.IsZero() {
+      start = i.StartTime.In(config.Location).Format("2006-01-02 15:04")
+    }
+
+    task := i.Task
+    if task == "" {
+      task = "-"
+    }
+
+    project := i.Project
+    if project == "" {
+      project = "-"
+    }
+
+    tags := "-"
+    if len(i.Tags) > 0 {
+      tags = "#" + strings.Join(i.Tags, " #")
+    }
+
+    state := pomodoro.StateName(i.State)
+    if i.Manual {
+      state += " (manual)"
+    }
+
+    fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", i.ID,
+      start, i.Category, task, project, tags, i.PlannedDuration,
+      i.ActualDuration.Round(time.Second), state, i.Marks(), i.Note)
+
+    if i.Category == pomodoro.CategoryPomodoro {
+      focus += i.ActualDuration
+      byProfile[i.Profile] += i.ActualDuration
+      pomodoros++
+    }
+    internal += i.InternalInterruptions
+    external += i.ExternalInterruptions
+  }
+
+  if err := w.Flush(); err != nil {
+    return err
+  }
+
+  _, err := fmt.Fprintf(out, "\n%d intervals, %s of focus\n",
+    len(intervals), focus.Round(time.Second))
+  if err == nil && internal+external > 0 {
+    _, err = fmt.Fprintf(out,
+      "%d interruptions (%d internal ', %d external -), %.1f per pomodoro\n",
+      internal+external, internal, external,
+      float64(internal+external)/float64(max(pomodoros, 1)))
+  }
+  // The breakdown is only useful once profiles are used
+  _, none := byProfile[""]
+  if err != nil || len(byProfile) == 0 || (len(byProfile) == 1 && none) {
+    return err
+  }
+
+  totals := []string{}
+  for _, name := range sortedProfiles(byProfile) {
+    totals = append(totals, fmt.Sprintf("%s %s", profileName(name),
+      byProfile[name].Round(time.Second)))
+  }
+
+  _, err = fmt.Fprintf(out, "Focus by profile: %s\n",
+    strings.Join(totals, ", "))
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(logCmd)
+
+  logCmd.Flags().String("day", "", "Show the given day (YYYY-MM-DD)")
+  logCmd.Flags().String("from", "", "Show intervals from this date or time")
+  logCmd.Flags().String("to", "", "Show intervals until this date or time")
+  logCmd.Flags().Bool("all", false, "Show the whole history")
+  logCmd.Flags().StringSlice("category", nil,
+    "Only show these categories (Pomodoro, ShortBreak, LongBreak)")
+  logCmd.Flags().StringSlice("state", nil,
+    "Only show these states (NotStarted, Running, Paused, Done, Cancelled)")
+  logCmd.Flags().String("task", "", "Only show intervals of this task")
+  logCmd.Flags().String("project", "",
+    "Only show intervals of this project and its subprojects")
+  logCmd.Flags().String("tag", "", "Only show intervals with this tag")
+  logCmd.Flags().Int("limit", 0, "Show at most this many intervals")
+  logCmd.Flags().Int("offset", 0, "Skip this many intervals")
+  logCmd.Flags().BoolP("reverse", "r", false, "Show the latest intervals first")
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestLogAction(t *testing.T) {
+  config := pomodoro.NewConfig(nil, 0, 0, 0)
+  config.Location = time.UTC
+
+  intervals := []pomodoro.Interval{
+    {ID: 1, StartTime: time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+      PlannedDuration: 25 * time.Minute, ActualDuration: 25 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+      Task: "report", Project: "work/api", Tags: []string{"backend", "review"}},
+    {ID: 3, StartTime: time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC),
+      PlannedDuration: 25 * time.Minute, ActualDuration: 25 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+      Manual: true},
+    {ID: 2, PlannedDuration: 5 * time.Minute,
+      Category: pomodoro.CategoryShortBreak},
+  }
+
+  var out bytes.Buffer
+  if err := logAction(&out, config, intervals); err != nil {
+    t.Fatal(err)
+  }
+
+  lines := strings.Split(out.String(), "\n")
+
+  expLines := []string{
+    "1   2026-10-16 09:00  Pomodoro    report  work/api  #backend #review  25m0s    25m0s   Done",
+    "2   -                 ShortBreak  -       -         -                 5m0s     0s      NotStarted",
+    "3   2026-10-16 14:00  Pomodoro    -       -         -                 25m0s    25m0s   Done (manual)",
+    "3 intervals, 50m0s of focus",
+  }
+
+  for _, exp := range expLines {
+    found := false
+    for _, l := range lines {
+      if strings.TrimSpace(l) == exp {
+        found = true
+      }
+    }
+
+    if !found {
+      t.Errorf("Expected line %q in output:\n%s", exp, out.String())
+    }
+  }
+}
+
+func TestLogActionProfiles(t *testing.T) {
+  config := pomodoro.NewConfig(nil, 0, 0, 0)
+  config.Location = time.UTC
+
+  start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  intervals := []pomodoro.Interval{
+    {ID: 1, StartTime: start, ActualDuration: 50 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, Profile: "deep-work"},
+    {ID: 2, StartTime: start.Add(time.Hour), ActualDuration: 10 * time.Minute,
+      Category: pomodoro.CategoryShortBreak, Profile: "deep-work"},
+    {ID: 3, StartTime: start.Add(2 * time.Hour),
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro},
+  }
+
+  var out bytes.Buffer
+  if err := logAction(&out, config, intervals); err != nil {
+    t.Fatal(err)
+  }
+
+  exp := "Focus by profile: (none) 25m0s, deep-work 50m0s\n"
+  if !strings.HasSuffix(out.String(), exp) {
+    t.Errorf("Expected output to end with %q, got:\n%s", exp, out.String())
+  }
+
+  out.Reset()
+  if err := logAction(&out, config, intervals[2:]); err != nil {
+    t.Fatal(err)
+  }
+  if strings.Contains(out.String(), "by profile") {
+    t.Errorf("Expected no breakdown without profiles, got:\n%s", out.String())
+  }
+}
+
+func TestLogActionInterruptions(t *testing.T) {
+  config := pomodoro.NewConfig(nil, 0, 0, 0)
+  config.Location = time.UTC
+
+  start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  intervals := []pomodoro.Interval{
+    {ID: 1, StartTime: start, PlannedDuration: 25 * time.Minute,
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone, InternalInterruptions: 2,
+      ExternalInterruptions: 1, Note: "outline done"},
+    {ID: 2, StartTime: start.Add(time.Hour), PlannedDuration: 25 * time.Minute,
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone, ExternalInterruptions: 1},
+  }
+
+  var out bytes.Buffer
+  if err := logAction(&out, config, intervals); err != nil {
+    t.Fatal(err)
+  }
+
+  lines := strings.Split(out.String(), "\n")
+  for k, exp := range []string{
+    "Done   ''-            outline done",
+    "Done   -",
+    "4 interruptions (2 internal ', 2 external -), 2.0 per pomodoro",
+  } {
+    found := false
+    for _, l := range lines {
+      if strings.HasSuffix(strings.TrimSpace(l), exp) {
+        found = true
+      }
+    }
+
+    if !found {
+      t.Errorf("Expected line %d ending with %q in output:\n%s", k, exp,
+        out.String())
+    }
+  }
+}
+
+func TestLogQueryActionUsers(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+  var out bytes.Buffer
+  for k, user := range []string{"bob", "carol"} {
+    config := pomodoro.NewConfig(repo, 0, 0, 0)
+    config.User = user
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(9+k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           user + "'s task",
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+  config.User = "bob"
+
+  out.Reset()
+  if err := logQueryAction(ctx, &out, config, pomodoro.Query{}); err != nil {
+    t.Fatal(err)
+  }
+
+  if !strings.Contains(out.String(), "bob's task") ||
+    strings.Contains(out.String(), "carol's task") {
+    t.Errorf("Expected only the intervals of bob, got:\n%s", out.String())
+  }
+  if !strings.Contains(out.String(), "1 intervals") {
+    t.Errorf("Expected 1 interval, got:\n%s", out.String())
+  }
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+  Use:   "note <text>",
+  Short: "Attach a note to the latest pomodoro",
+  Long: `Attach a note to the latest pomodoro, or to the interval given with
+--id, replacing any previous note. An empty text removes the note.`,
+  Example: `  pomo note "Drafted the intro, stuck on the figures"`,
+  Args:    cobra.MinimumNArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    id, _ := cmd.Flags().GetInt64("id")
+
+    return noteAction(cmd.Context(), os.Stdout, config, id,
+      strings.Join(args, " "))
+  },
+}
+
+func noteAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, id int64, note string) error {
+
+  if id == 0 {
+    i, err := pomodoro.LastPomodoro(ctx, config)
+    if err != nil {
+      return err
+    }
+    id = i.ID
+  }
+
+  i, err := pomodoro.Edit(ctx, config, id, func(i *pomodoro.Interval) {
+    i.Note = note
+  })
+  if err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Noted %s %d.\n", i.Category, i.ID)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(noteCmd)
+
+  noteCmd.Flags().Int64("id", 0, "Interval to note instead of the latest pomodoro")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestNoteAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  var out bytes.Buffer
+  err = noteAction(ctx, &out, config, 0, "nothing to note")
+  if !errors.Is(err, pomodoro.ErrNoIntervals) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrNoIntervals, err)
+  }
+
+  start := time.Now().Add(-time.Hour)
+  for _, i := range []pomodoro.Interval{
+    {StartTime: start, Category: pomodoro.CategoryPomodoro},
+    {StartTime: start.Add(25 * time.Minute),
+      Category: pomodoro.CategoryShortBreak},
+  } {
+    i.PlannedDuration = 5 * time.Minute
+    i.ActualDuration = i.PlannedDuration
+    i.State = pomodoro.StateDone
+    if _, err := repo.Create(ctx, i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  if err := noteAction(ctx, &out, config, 0, "drafted intro"); err != nil {
+    t.Fatal(err)
+  }
+
+  i, err := repo.ByID(ctx, 1)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.Note != "drafted intro" {
+    t.Errorf("Expected the latest pomodoro to be noted, got %q", i.Note)
+  }
+}
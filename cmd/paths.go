@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	homedir "github.com/mitchellh/go-homedir"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// appName is the directory name used under the XDG base directories.
+const appName = "pomanalyzer"
+
+// Files used before the XDG base directories, moved on first run.
+const (
+  legacyDB     = "pomo.db"
+  legacyConfig = ".pomo"
+)
+
+// sqliteHeader starts every SQLite database file.
+const sqliteHeader = "SQLite format 3\x00"
+
+// configExts are the config file extensions looked for when migrating.
+var configExts = []string{".yaml", ".yml", ".json", ".toml"}
+
+// sideExts are the files SQLite keeps next to a database while it's in
+// use or after a crash.
+var sideExts = []string{"-journal", "-wal", "-shm"}
+
+// pathsCmd represents the paths command
+var pathsCmd = &cobra.Command{
+  Use:   "paths",
+  Short: "Show the files and directories in use",
+  Args:  cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    return pathsAction(os.Stdout)
+  },
+}
+
+func pathsAction(out io.Writer) error {
+  config := viper.ConfigFileUsed()
+  if config == "" {
+    config = filepath.Join(configDir(), "config.yaml") + " (not found)"
+  }
+
+  home, err := homedir.Dir()
+  if err != nil {
+    home = "~"
+  }
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintf(w, "Config file:\t%s\n", config)
+  fmt.Fprintf(w, "Database:\t%s\n", viper.GetString("db"))
+  fmt.Fprintf(w, "Backups:\t%s\n", backupDir())
+  fmt.Fprintf(w, "Config directory:\t%s\n", configDir())
+  fmt.Fprintf(w, "Data directory:\t%s\n", dataDir())
+  fmt.Fprintf(w, "Older database:\t%s, moved to the data directory when "+
+    "pomo runs from the home directory and the data directory has none\n",
+    filepath.Join(home, legacyDB))
+
+  return w.Flush()
+}
+
+// xdgDir returns the directory of the app under the XDG base directory
+// set in env, or under fallback in the home directory.
+func xdgDir(env, fallback string) string {
+  base := os.Getenv(env)
+  if !filepath.IsAbs(base) {
+    home, err := homedir.Dir()
+    if err != nil {
+      return appName
+    }
+    base = filepath.Join(home, fallback)
+  }
+
+  return filepath.Join(base, appName)
+}
+
+// dataDir is $XDG_DATA_HOME/pomanalyzer.
+func dataDir() string {
+  return xdgDir("XDG_DATA_HOME", filepath.Join(".local", "share"))
+}
+
+// configDir is $XDG_CONFIG_HOME/pomanalyzer.
+func configDir() string {
+  return xdgDir("XDG_CONFIG_HOME", ".config")
+}
+
+func defaultDB() string {
+  return filepath.Join(dataDir(), "pomo.db")
+}
+
+// prepareDefaultDB creates the data directory and moves the pomo.db of
+// older versions into it, unless it already has a database. A database
+// with side files is left alone, as it may be in use. A pomo.db that
+// isn't moved is reported, so its history isn't silently left behind.
+func prepareDefaultDB(stderr io.Writer) error {
+  db := defaultDB()
+  if err := os.MkdirAll(filepath.Dir(db), 0o755); err != nil {
+    return err
+  }
+
+  from, fromHome, ok := legacyDBFile()
+  if !ok {
+    return nil
+  }
+
+  if !fromHome {
+    fmt.Fprintf(stderr, "Not moving %s to %s: only the %s in the home "+
+      "directory is, when pomo runs from there\n", from, db, legacyDB)
+    return nil
+  }
+
+  for _, ext := range sideExts {
+    if _, err := os.Stat(from + ext); err == nil {
+      fmt.Fprintf(stderr, "Not moving %s to %s while %s exists\n", from, db,
+        from+ext)
+      return nil
+    }
+  }
+
+  moved, err := migrateFile(from, db)
+  switch {
+  case moved:
+    fmt.Fprintf(stderr, "Moved %s to %s\n", from, db)
+  case err == nil:
+    fmt.Fprintf(stderr, "Not moving %s to %s, which already exists\n", from,
+      db)
+  }
+
+  return err
+}
+
+// legacyDBFile returns the pomo.db in the working directory when it's a
+// SQLite database, and whether the working directory is the home
+// directory. Older versions kept it in the working directory, the home
+// directory by default, so only that one is moved; a pomo.db anywhere
+// else may belong to whoever put it there.
+func legacyDBFile() (string, bool, bool) {
+  wd, err := os.Getwd()
+  if err != nil {
+    return "", false, false
+  }
+
+  from := filepath.Join(wd, legacyDB)
+  f, err := os.Open(from)
+  if err != nil {
+    return "", false, false
+  }
+  defer f.Close()
+
+  header := make([]byte, len(sqliteHeader))
+  if _, err := io.ReadFull(f, header); err != nil ||
+    string(header) != sqliteHeader {
+    return "", false, false
+  }
+
+  home, err := homedir.Dir()
+  return from, err == nil && sameDir(wd, home), true
+}
+
+// sameDir reports whether a and b are the same directory once symbolic
+// links are resolved.
+func sameDir(a, b string) bool {
+  a, errA := filepath.EvalSymlinks(a)
+  b, errB := filepath.EvalSymlinks(b)
+
+  return errA == nil && errB == nil && a == b
+}
+
+// migrateConfig moves a $HOME/.pomo config file into the config
+// directory, unless it already has one.
+func migrateConfig(stderr io.Writer) error {
+  home, err := homedir.Dir()
+  if err != nil {
+    return err
+  }
+
+  for _, ext := range configExts {
+    if _, err := os.Stat(filepath.Join(configDir(), "config"+ext)); err == nil {
+      return nil
+    }
+  }
+
+  for _, ext := range configExts {
+    from := filepath.Join(home, legacyConfig+ext)
+    to := filepath.Join(configDir(), "config"+ext)
+
+    if _, err := os.Stat(from); err != nil {
+      continue
+    }
+
+    if err := os.MkdirAll(configDir(), 0o755); err != nil {
+      return err
+    }
+
+    moved, err := migrateFile(from, to)
+    if moved {
+      fmt.Fprintf(stderr, "Moved %s to %s\n", from, to)
+    }
+
+    return err
+  }
+
+  return nil
+}
+
+// migrateFile moves from to to if from exists and to doesn't, copying
+// when they're on different file systems.
+func migrateFile(from, to string) (bool, error) {
+  if _, err := os.Stat(to); err == nil {
+    return false, nil
+  }
+
+  if _, err := os.Stat(from); errors.Is(err, os.ErrNotExist) {
+    return false, nil
+  }
+
+  if err := os.Rename(from, to); err == nil {
+    return true, nil
+  }
+
+  data, err := os.ReadFile(from)
+  if err != nil {
+    return false, err
+  }
+
+  if err := os.WriteFile(to, data, 0o644); err != nil {
+    return false, err
+  }
+
+  return true, os.Remove(from)
+}
+
+func init() {
+  rootCmd.AddCommand(pathsCmd)
+}
@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	homedir "github.com/mitchellh/go-homedir"
+)
+
+func init() {
+  // Tests change HOME
+  homedir.DisableCache = true
+}
+
+func TestXDGDirs(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  t.Setenv("XDG_DATA_HOME", "")
+  t.Setenv("XDG_CONFIG_HOME", "relative/ignored")
+
+  if exp := filepath.Join(home, ".local", "share", appName); dataDir() != exp {
+    t.Errorf("Expected data dir %q, got %q instead\n", exp, dataDir())
+  }
+  if exp := filepath.Join(home, ".config", appName); configDir() != exp {
+    t.Errorf("Expected config dir %q, got %q instead\n", exp, configDir())
+  }
+
+  data := t.TempDir()
+  t.Setenv("XDG_DATA_HOME", data)
+  if exp := filepath.Join(data, appName, "pomo.db"); defaultDB() != exp {
+    t.Errorf("Expected database %q, got %q instead\n", exp, defaultDB())
+  }
+}
+
+func TestMigrate(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, "config"))
+  t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+  wd, err := os.Getwd()
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer os.Chdir(wd)
+
+  if err := os.Chdir(home); err != nil {
+    t.Fatal(err)
+  }
+
+  db := sqliteHeader + "history"
+  files := map[string]string{legacyDB: db, ".pomo.yaml": "pomo: 30m\n"}
+  for name, content := range files {
+    if err := os.WriteFile(filepath.Join(home, name), []byte(content),
+      0o644); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  var stderr bytes.Buffer
+
+  // A database in use is left alone
+  wal := filepath.Join(home, legacyDB+"-wal")
+  if err := os.WriteFile(wal, nil, 0o644); err != nil {
+    t.Fatal(err)
+  }
+  if err := prepareDefaultDB(&stderr); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := os.Stat(defaultDB()); !os.IsNotExist(err) {
+    t.Errorf("Expected database in use not to be moved, got %v\n", err)
+  }
+  if !strings.Contains(stderr.String(), "Not moving") {
+    t.Errorf("Expected database in use to be reported, got:\n%s",
+      stderr.String())
+  }
+  if err := os.Remove(wal); err != nil {
+    t.Fatal(err)
+  }
+
+  // Only from the home directory, other ones are reported
+  other := t.TempDir()
+  if err := os.WriteFile(filepath.Join(other, legacyDB), []byte(db),
+    0o644); err != nil {
+    t.Fatal(err)
+  }
+  if err := os.Chdir(other); err != nil {
+    t.Fatal(err)
+  }
+  stderr.Reset()
+  if err := prepareDefaultDB(&stderr); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := os.Stat(defaultDB()); !os.IsNotExist(err) {
+    t.Errorf("Expected database outside of home not to be moved, got %v\n",
+      err)
+  }
+  if !strings.Contains(stderr.String(), "only the pomo.db in the home") {
+    t.Errorf("Expected database outside of home to be reported, got:\n%s",
+      stderr.String())
+  }
+  if err := os.Chdir(home); err != nil {
+    t.Fatal(err)
+  }
+
+  stderr.Reset()
+  if err := prepareDefaultDB(&stderr); err != nil {
+    t.Fatal(err)
+  }
+  if err := migrateConfig(&stderr); err != nil {
+    t.Fatal(err)
+  }
+
+  moved := map[string]string{
+    defaultDB():                               db,
+    filepath.Join(configDir(), "config.yaml"): "pomo: 30m\n",
+  }
+  for path, exp := range moved {
+    data, err := os.ReadFile(path)
+    if err != nil {
+      t.Fatal(err)
+    }
+    if string(data) != exp {
+      t.Errorf("Expected %q in %s, got %q instead\n", exp, path, data)
+    }
+  }
+
+  for name := range files {
+    if _, err := os.Stat(filepath.Join(home, name)); !os.IsNotExist(err) {
+      t.Errorf("Expected %s to be moved, got %v\n", name, err)
+    }
+  }
+
+  if n := strings.Count(stderr.String(), "Moved"); n != 2 {
+    t.Errorf("Expected 2 moves reported, got:\n%s", stderr.String())
+  }
+
+  // A second run leaves existing files alone
+  if err := os.WriteFile(filepath.Join(home, legacyDB),
+    []byte(sqliteHeader+"other"), 0o644); err != nil {
+    t.Fatal(err)
+  }
+  stderr.Reset()
+  if err := prepareDefaultDB(&stderr); err != nil {
+    t.Fatal(err)
+  }
+  if data, _ := os.ReadFile(defaultDB()); string(data) != db {
+    t.Errorf("Expected existing database to be kept, got %q\n", data)
+  }
+  if !strings.Contains(stderr.String(), "which already exists") {
+    t.Errorf("Expected database left behind to be reported, got:\n%s",
+      stderr.String())
+  }
+}
+
+func TestPathsAction(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+
+  var out bytes.Buffer
+  if err := pathsAction(&out); err != nil {
+    t.Fatal(err)
+  }
+
+  exp := filepath.Join(home, legacyDB) + ", moved to the data directory " +
+    "when pomo runs from the home directory"
+  if !strings.Contains(out.String(), exp) {
+    t.Errorf("Expected %q in output, got:\n%s", exp, out.String())
+  }
+}
+
+func TestMigrateNotSQLite(t *testing.T) {
+  home := t.TempDir()
+  t.Setenv("HOME", home)
+  t.Setenv("XDG_DATA_HOME", filepath.Join(home, "data"))
+
+  wd, err := os.Getwd()
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer os.Chdir(wd)
+
+  if err := os.Chdir(home); err != nil {
+    t.Fatal(err)
+  }
+
+  from := filepath.Join(home, legacyDB)
+  if err := os.WriteFile(from, []byte("fixture"), 0o644); err != nil {
+    t.Fatal(err)
+  }
+
+  var stderr bytes.Buffer
+  if err := prepareDefaultDB(&stderr); err != nil {
+    t.Fatal(err)
+  }
+
+  if data, _ := os.ReadFile(from); string(data) != "fixture" {
+    t.Errorf("Expected %s to be kept, got %q\n", from, data)
+  }
+  if _, err := os.Stat(defaultDB()); !os.IsNotExist(err) {
+    t.Errorf("Expected no database to be moved, got %v\n", err)
+  }
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// profilesCmd represents the profiles command
+var profilesCmd = &cobra.Command{
+  Use:   "profiles",
+  Short: "List the profiles and the focus time spent in each",
+  Long: `List the profiles with their durations and cycle length, marking the
+one in use, followed by the focus time spent in each profile over the last
+7 days.
+
+Select a profile with --profile or the "profile" setting, or switch from
+the dashboard between intervals.`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    s, err := getSettings()
+    if err != nil {
+      return err
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := newIntervalConfig(repo, s)
+    if err != nil {
+      return err
+    }
+
+    return profilesAction(cmd.Context(), os.Stdout, config, s)
+  },
+}
+
+func profilesAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, s settings.Settings) error {
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintln(w, "\tPROFILE\tPOMODORO\tSHORT\tLONG\tCYCLE\tNOTIFY")
+
+  for _, name := range s.ProfileNames() {
+    p, err := s.WithProfile(name)
+    if err != nil {
+      return err
+    }
+
+    current := ""
+    if name == config.Profile {
+      current = "*"
+    }
+
+    notify := "off"
+    if p.Notifications.Enabled {
+      notify = p.Notifications.Severity
+    }
+
+    fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", current, name,
+      p.Pomodoro, p.ShortBreak, p.LongBreak, p.Cycle, notify)
+  }
+  if err := w.Flush(); err != nil {
+    return err
+  }
+
+  start, _ := config.DayBounds(time.Now().AddDate(0, 0, -6))
+  _, end := config.DayBounds(time.Now())
+  totals, err := pomodoro.ProfileSummary(ctx, start, end, config)
+  if err != nil {
+    return err
+  }
+
+  if len(totals) == 0 {
+    _, err := fmt.Fprintln(out, "\nNo focus time in the last 7 days.")
+    return err
+  }
+
+  fmt.Fprintln(out, "\nFocus in the last 7 days:")
+  w = tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  for _, t := range totals {
+    fmt.Fprintf(w, "  %s\t%s\n", profileName(t.Profile),
+      t.Focus.Round(time.Second))
+  }
+
+  return w.Flush()
+}
+
+// profileName names the intervals timed without a profile.
+func profileName(name string) string {
+  if name == "" {
+    return "(none)"
+  }
+
+  return name
+}
+
+func sortedProfiles(m map[string]time.Duration) []string {
+  names := make([]string, 0, len(m))
+  for name := range m {
+    names = append(names, name)
+  }
+  sort.Strings(names)
+
+  return names
+}
+
+func init() {
+  rootCmd.AddCommand(profilesCmd)
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// projectsCmd represents the projects command
+var projectsCmd = &cobra.Command{
+  Use:   "projects",
+  Short: "Show the focus time spent in each project or with each tag",
+  Long: `Show the focus time spent in each project over a period, the current
+month by default. A project includes the time of its subprojects, so
+"work" adds up "work/api" and "work/api/auth".
+
+Use --tags to group by tag instead, and --project or --tag to look at part
+of the history only.`,
+  Example: `  pomo projects --project work/api/auth
+  pomo projects --from 2026-10-01 --to 2026-11-01 --tags`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    from, _ := flags.GetString("from")
+    to, _ := flags.GetString("to")
+    start, end, err := monthPeriod(config, from, to, time.Now())
+    if err != nil {
+      return err
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+    config.SetFilter(f)
+
+    byTag, _ := flags.GetBool("tags")
+
+    return projectsAction(cmd.Context(), os.Stdout, config, start, end, byTag)
+  },
+}
+
+// parseLabels returns the project and tags given by the user in their
+// stored form.
+func parseLabels(project string, tags []string) (string, []string, error) {
+  p, err := pomodoro.ParseProject(project)
+  if err != nil {
+    return "", nil, err
+  }
+
+  t, err := pomodoro.ParseTags(tags)
+  if err != nil {
+    return "", nil, err
+  }
+
+  return p, t, nil
+}
+
+// parseFilter returns the filter for a project and a tag given by the
+// user.
+func parseFilter(project, tag string) (pomodoro.Filter, error) {
+  f := pomodoro.Filter{}
+
+  p, err := pomodoro.ParseProject(project)
+  if err != nil {
+    return f, err
+  }
+
+  tags, err := pomodoro.ParseTags([]string{tag})
+  if err != nil {
+    return f, err
+  }
+  if len(tags) > 1 {
+    return f, fmt.Errorf("%w: filter on a single tag, got %q",
+      pomodoro.ErrInvalidInterval, tag)
+  }
+
+  f.Project = p
+  if len(tags) == 1 {
+    f.Tag = tags[0]
+  }
+
+  return f, nil
+}
+
+// monthPeriod returns the period between from and to, by default from
+// the start of the month of now to the end of today.
+func monthPeriod(config *pomodoro.IntervalConfig, from, to string,
+  now time.Time) (time.Time, time.Time, error) {
+
+  today, end := config.DayBounds(now)
+  start, _ := config.DayBounds(today.AddDate(0, 0, 1-today.Day()))
+
+  var err error
+  if from != "" {
+    if start, err = parseTime(from, config.Location); err != nil {
+      return start, end, err
+    }
+  }
+  if to != "" {
+    if end, err = parseTime(to, config.Location); err != nil {
+      return start, end, err
+    }
+  }
+
+  return start, end, nil
+}
+
+func projectsAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, start, end time.Time, byTag bool) error {
+
+  summary, label := pomodoro.ProjectSummary, "PROJECT"
+  if byTag {
+    summary, label = pomodoro.TagSummary, "TAG"
+  }
+
+  totals, err := summary(ctx, start, end, config)
+  if err != nil {
+    return err
+  }
+
+  layout := "2006-01-02 15:04"
+  fmt.Fprintf(out, "Focus from %s to %s", start.In(config.Location).Format(layout),
+    end.In(config.Location).Format(layout))
+  if f := config.Filter.String(); f != "" {
+    fmt.Fprintf(out, " in %s", f)
+  }
+  fmt.Fprint(out, "\n\n")
+
+  if len(totals) == 0 {
+    _, err := fmt.Fprintln(out, "No pomodoros.")
+    return err
+  }
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintf(w, "%s\tFOCUS\n", label)
+  for _, t := range totals {
+    name := t.Name
+    switch {
+    case name == "":
+      name = "(none)"
+    case byTag:
+      name = "#" + name
+    default:
+      // Subprojects are indented under their parent
+      name = strings.Repeat("  ", t.Depth()) + name[strings.LastIndex(name,
+        "/")+1:]
+    }
+
+    fmt.Fprintf(w, "%s\t%s\n", name, t.Focus.Round(time.Second))
+  }
+
+  return w.Flush()
+}
+
+func init() {
+  rootCmd.AddCommand(projectsCmd)
+
+  projectsCmd.Flags().String("from", "",
+    "Start of the period (default start of the month)")
+  projectsCmd.Flags().String("to", "", "End of the period (default today)")
+  projectsCmd.Flags().String("project", "",
+    "Only count this project and its subprojects")
+  projectsCmd.Flags().String("tag", "", "Only count pomodoros with this tag")
+  projectsCmd.Flags().Bool("tags", false, "Group by tag instead of project")
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestProjectsAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  sessions := []struct {
+    project string
+    tags    []string
+  }{
+    {"Work/API", []string{"backend"}},
+    {"work/api/auth", []string{"#backend,review"}},
+    {"work/docs", nil},
+    {"", []string{"reading"}},
+  }
+
+  var out bytes.Buffer
+  for k, s := range sessions {
+    i := pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+    }
+    if i.Project, i.Tags, err = parseLabels(s.project, s.tags); err != nil {
+      t.Fatal(err)
+    }
+    if err := addAction(ctx, &out, config, i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  start, end, err := monthPeriod(config, "", "", day)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if exp := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC); !start.Equal(exp) {
+    t.Errorf("Expected period to start at %s, got %s", exp, start)
+  }
+
+  testCases := []struct {
+    name     string
+    byTag    bool
+    filter   pomodoro.Filter
+    expLines []string
+  }{
+    {"Projects", false, pomodoro.Filter{}, []string{
+      "(none)    25m0s",
+      "work      1h15m0s",
+      "  api     50m0s",
+      "    auth  25m0s",
+      "  docs    25m0s",
+    }},
+    {"Tags", true, pomodoro.Filter{}, []string{
+      "(none)    25m0s",
+      "#backend  50m0s",
+      "#reading  25m0s",
+      "#review   25m0s",
+    }},
+    {"Filtered", false, pomodoro.Filter{Tag: "review"}, []string{
+      "Focus from 2026-10-01 00:00 to 2026-10-17 00:00 in #review",
+      "work      25m0s",
+      "    auth  25m0s",
+    }},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      config.SetFilter(tc.filter)
+
+      out.Reset()
+      err := projectsAction(ctx, &out, config, start, end, tc.byTag)
+      if err != nil {
+        t.Fatal(err)
+      }
+
+      for _, exp := range tc.expLines {
+        if !strings.Contains(out.String(), exp+"\n") {
+          t.Errorf("Expected line %q in output:\n%s", exp, out.String())
+        }
+      }
+    })
+  }
+}
+
+func TestParseFilter(t *testing.T) {
+  f, err := parseFilter("Work/API/", "#Backend")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if exp := (pomodoro.Filter{Project: "work/api", Tag: "backend"}); f != exp {
+    t.Errorf("Expected filter %+v, got %+v", exp, f)
+  }
+
+  for _, tag := range []string{"a,b", "bad!tag"} {
+    if _, err := parseFilter("", tag); !errors.Is(err,
+      pomodoro.ErrInvalidInterval) {
+      t.Errorf("Expected error %q for tag %q, got %v",
+        pomodoro.ErrInvalidInterval, tag, err)
+    }
+  }
+}
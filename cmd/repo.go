@@ -1,18 +1,20 @@
-// +build !inmemory
-
 package cmd
 
 import (
+	"os"
+
 	"github.com/spf13/viper"
 	"github.com/xasterKies/pomanalyzer/pomodoro"
 	"github.com/xasterKies/pomanalyzer/repository"
 )
 
 func getRepo() (pomodoro.Repository, error) {
-  repo, err := repository.NewSQLite3Repo(viper.GetString("db"))
-  if err != nil {
-    return nil, err
+  dsn := viper.GetString("db")
+  if dsn == defaultDB() {
+    if err := prepareDefaultDB(os.Stderr); err != nil {
+      return nil, err
+    }
   }
 
-  return repo, nil
-}
\ No newline at end of file
+  return repository.Open(dsn)
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/report"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+  Use:   "report",
+  Short: "Write a weekly or monthly focus report",
+  Long: `Write a summary of the week or month, the current one by default, to
+paste into status updates: total focus and break time, pomodoros done each
+day with a chart, how often the daily and weekly goals were met, the
+share of pomodoros finished, and the tasks and tags you spent most time on.
+
+Reports are plain text, Markdown, or a standalone HTML page with an SVG
+chart. Use --project or --tag to report on part of the history only.`,
+  Example: `  pomo report --period month --format md
+  pomo report --date 2026-10-12 --format html -o week.html`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    s, err := getSettings()
+    if err != nil {
+      return err
+    }
+
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := newIntervalConfig(repo, s)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    name, _ := flags.GetString("format")
+    format, err := report.ParseFormat(name)
+    if err != nil {
+      return err
+    }
+
+    now := time.Now()
+    day := now
+    if date, _ := flags.GetString("date"); date != "" {
+      if day, err = parseTime(date, config.Location); err != nil {
+        return err
+      }
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+    config.SetFilter(f)
+
+    o := report.Options{Goals: s.Goals, Now: now}
+    o.Period, _ = flags.GetString("period")
+    o.Top, _ = flags.GetInt("top")
+    output, _ := flags.GetString("output")
+
+    return reportAction(cmd.Context(), os.Stdout, config, day, format, o,
+      output)
+  },
+}
+
+// reportAction writes the report of the period around day to output, or
+// to out if it's empty or "-".
+func reportAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, day time.Time, format string,
+  o report.Options, output string) error {
+
+  first, _ := config.DayBounds(day)
+  start, n, err := report.Period(o.Period, first)
+  if err != nil {
+    return err
+  }
+
+  r, err := pomodoro.PeriodSummary(ctx, start, n, config)
+  if err != nil {
+    return err
+  }
+
+  if output == "" || output == "-" {
+    return report.Write(out, format, r, o)
+  }
+
+  var buf bytes.Buffer
+  if err := report.Write(&buf, format, r, o); err != nil {
+    return err
+  }
+
+  if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Wrote the %s report to %s.\n", o.Period, output)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(reportCmd)
+
+  reportCmd.Flags().String("period", report.PeriodWeek, "week or month")
+  reportCmd.Flags().StringP("format", "f", report.FormatText,
+    "txt, md or html")
+  reportCmd.Flags().String("date", "",
+    "Day in the period to report on (default today)")
+  reportCmd.Flags().Int("top", 5, "Number of tasks and tags listed")
+  reportCmd.Flags().StringP("output", "o", "", "File to write, - for stdout")
+  reportCmd.Flags().String("project", "",
+    "Only report on this project and its subprojects")
+  reportCmd.Flags().String("tag", "", "Only report on pomodoros with this tag")
+}
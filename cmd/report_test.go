@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/report"
+	"github.com/xasterKies/pomanalyzer/repository"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+func TestReportAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  day := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+  var out bytes.Buffer
+  for k := 0; k < 3; k++ {
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           "report",
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  o := report.Options{
+    Period: report.PeriodWeek,
+    Goals:  settings.Goals{Daily: 3},
+    Now:    day.AddDate(0, 0, 7),
+  }
+
+  out.Reset()
+  err = reportAction(ctx, &out, config, day, report.FormatMarkdown, o, "")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  for _, exp := range []string{
+    "Mon 12 Oct 2026 to Sun 18 Oct 2026",
+    "- **Focus:** 1h15m0s in 3 pomodoros\n",
+    "- **Daily goal:** 1 of 7 days at 3 pomodoros\n",
+    "| Wed 14 Oct | 3 | 1h15m0s |\n",
+    "| report | 1h15m0s |\n",
+  } {
+    if !strings.Contains(out.String(), exp) {
+      t.Errorf("Expected %q in:\n%s", exp, out.String())
+    }
+  }
+
+  o.Period = "fortnight"
+  err = reportAction(ctx, &out, config, day, report.FormatText, o, "")
+  if !errors.Is(err, report.ErrUnknownPeriod) {
+    t.Errorf("Expected error %q, got %v", report.ErrUnknownPeriod, err)
+  }
+}
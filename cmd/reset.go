@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+// resetCmd represents the reset command
+var resetCmd = &cobra.Command{
+  Use:   "reset",
+  Short: "Delete the history, optionally archiving it first",
+  Long: `Delete all intervals, or those started before --before, from the
+database selected with --db. A running interval is never deleted.
+
+With --archive, the intervals are first copied to another database, given
+as a file or DSN like --db, e.g. --archive json:2025.jsonl. Nothing is
+deleted if the archive fails.`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    flags := cmd.Flags()
+    q := pomodoro.Query{}
+    if before, _ := flags.GetString("before"); before != "" {
+      if q.End, err = parseTime(before, config.Location); err != nil {
+        return err
+      }
+    }
+
+    var archive pomodoro.Repository
+    if dsn, _ := flags.GetString("archive"); dsn != "" {
+      if archive, err = repository.Open(dsn); err != nil {
+        return err
+      }
+    }
+
+    dryRun, _ := flags.GetBool("dry-run")
+    yes, _ := flags.GetBool("yes")
+
+    return resetAction(cmd.Context(), os.Stdin, os.Stdout, config,
+      viper.GetString("db"), q, archive, dryRun, yes)
+  },
+}
+
+func resetAction(ctx context.Context, in io.Reader, out io.Writer,
+  config *pomodoro.IntervalConfig, db string, q pomodoro.Query,
+  archive pomodoro.Repository, dryRun, yes bool) error {
+
+  list, err := pomodoro.ResetList(ctx, config, q)
+  if err != nil {
+    return err
+  }
+
+  if len(list) == 0 {
+    _, err := fmt.Fprintln(out, "Nothing to delete.")
+    return err
+  }
+
+  if dryRun {
+    if err := logAction(out, config, list); err != nil {
+      return err
+    }
+
+    _, err := fmt.Fprintf(out, "Would delete %d intervals from %s.\n",
+      len(list), db)
+    return err
+  }
+
+  if !yes && !confirm(in, out,
+    fmt.Sprintf("Delete %d intervals from %s?", len(list), db)) {
+    _, err := fmt.Fprintln(out, "Nothing deleted.")
+    return err
+  }
+
+  deleted, err := pomodoro.Reset(ctx, config, q, archive)
+  if err != nil {
+    return fmt.Errorf("deleted %d intervals: %w", len(deleted), err)
+  }
+
+  _, err = fmt.Fprintf(out, "Deleted %d intervals from %s.\n",
+    len(deleted), db)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(resetCmd)
+
+  resetCmd.Flags().String("before", "",
+    "Only delete intervals started before this date or time")
+  resetCmd.Flags().String("archive", "",
+    "Copy the intervals to this file or DSN before deleting them")
+  resetCmd.Flags().Bool("dry-run", false,
+    "Show what would be deleted without deleting anything")
+  resetCmd.Flags().BoolP("yes", "y", false, "Don't ask for confirmation")
+}
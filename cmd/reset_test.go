@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestResetAction(t *testing.T) {
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+  day := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+  for k, state := range []int{pomodoro.StateDone, pomodoro.StateDone,
+    pomodoro.StateRunning, pomodoro.StateDone} {
+    if _, err := repo.Create(ctx, pomodoro.Interval{
+      StartTime:       day.AddDate(0, 0, k),
+      PlannedDuration: 25 * time.Minute,
+      ActualDuration:  25 * time.Minute,
+      Category:        pomodoro.CategoryPomodoro,
+      State:           state,
+    }); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  remaining := func() int {
+    list, err := repo.List(ctx, pomodoro.Query{})
+    if err != nil {
+      t.Fatal(err)
+    }
+    return len(list)
+  }
+
+  q := pomodoro.Query{End: day.AddDate(0, 0, 3)}
+  var out bytes.Buffer
+
+  if err := resetAction(ctx, strings.NewReader(""), &out, config, "test.db",
+    q, nil, true, false); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Would delete 2 intervals from test.db.") {
+    t.Errorf("Expected dry run summary, got:\n%s", out.String())
+  }
+
+  out.Reset()
+  if err := resetAction(ctx, strings.NewReader("n\n"), &out, config,
+    "test.db", q, nil, false, false); err != nil {
+    t.Fatal(err)
+  }
+  if n := remaining(); n != 4 {
+    t.Fatalf("Expected 4 intervals after refusing, got %d", n)
+  }
+
+  archivePath := filepath.Join(t.TempDir(), "archive.jsonl")
+  archive, err := repository.Open("json:" + archivePath)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  out.Reset()
+  if err := resetAction(ctx, strings.NewReader("y\n"), &out, config,
+    "test.db", q, archive, false, false); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "Deleted 2 intervals from test.db.") {
+    t.Errorf("Expected deletion summary, got:\n%s", out.String())
+  }
+
+  // The running interval and the one after --before are kept
+  if n := remaining(); n != 2 {
+    t.Errorf("Expected 2 intervals left, got %d", n)
+  }
+
+  archived, err := archive.List(ctx, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(archived) != 2 || !archived[1].StartTime.Equal(day.AddDate(0, 0, 1)) {
+    t.Errorf("Expected 2 archived intervals, got %v instead\n", archived)
+  }
+}
@@ -4,21 +4,28 @@ Copyright © 2024 xasterKies
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/cobra"
 	"github.com/xasterKies/pomanalyzer/app"
+	"github.com/xasterKies/pomanalyzer/metrics"
 	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
 
-	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
 
 var cfgFile string
 
+// configErr keeps the error reading the config file, reported when the
+// settings are used so `pomo config` can still fix the file.
+var configErr error
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
   Use:   "pomo",
@@ -27,30 +34,130 @@ var rootCmd = &cobra.Command{
   // has an action associated with it:
   //  Run: func(cmd *cobra.Command, args []string) { },
   RunE: func(cmd *cobra.Command, args []string) error {
+    s, err := getSettings()
+    if err != nil {
+      return err
+    }
+
     repo, err := getRepo()
     if err != nil {
       return err
     }
 
-    config := pomodoro.NewConfig(
-      repo,
-      viper.GetDuration("pomo"),
-      viper.GetDuration("short"),
-      viper.GetDuration("long"),
-    )
-    return rootAction(os.Stdout, config)
+    config, err := newIntervalConfig(repo, s)
+    if err != nil {
+      return err
+    }
+    if s.Task != "" {
+      if err := useTask(cmd.Context(), config, s.Task); err != nil {
+        return err
+      }
+    }
+
+    flags := cmd.Flags()
+    project, _ := flags.GetString("project")
+    tags, _ := flags.GetStringSlice("tag")
+    if config.Project, config.Tags, err = parseLabels(project,
+      tags); err != nil {
+      return err
+    }
+
+    if s.AutoBackup {
+      err := autoBackup(cmd.Context(), repo, config, backupDir(),
+        s.BackupKeep)
+      if err != nil {
+        fmt.Fprintln(os.Stderr, "Automatic backup failed:", err)
+      }
+    }
+
+    return rootAction(os.Stdout, config, s)
   },
 }
 
-func rootAction(out io.Writer, config *pomodoro.IntervalConfig) error {
-  a, err := app.New(config)
+// getSettings returns the validated settings from flags, environment and
+// config file.
+func getSettings() (settings.Settings, error) {
+  if configErr != nil {
+    return settings.Settings{}, configErr
+  }
+
+  return settings.Load(viper.GetViper())
+}
+
+// getConfig builds the interval configuration from flags and config file.
+func getConfig(repo pomodoro.Repository) (*pomodoro.IntervalConfig, error) {
+  s, err := getSettings()
+  if err != nil {
+    return nil, err
+  }
+
+  return newIntervalConfig(repo, s)
+}
+
+func newIntervalConfig(repo pomodoro.Repository,
+  s settings.Settings) (*pomodoro.IntervalConfig, error) {
+
+  s, err := s.WithProfile(s.Profile)
+  if err != nil {
+    return nil, err
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  s.Configure(config)
+
+  if s.Timezone != "" {
+    loc, err := time.LoadLocation(s.Timezone)
+    if err != nil {
+      return nil, err
+    }
+    config.Location = loc
+  }
+
+  config.DayStart = time.Duration(s.DayStart) * time.Hour
+  config.Timeout = s.DBTimeout
+  config.ExcludeManual = s.ExcludeManual
+
+  config.User = s.User
+  if config.User == "" {
+    config.User = currentUser()
+  }
+
+  return config, nil
+}
+
+func rootAction(out io.Writer, config *pomodoro.IntervalConfig,
+  s settings.Settings) error {
+
+  a, err := app.New(config, s)
   if err != nil {
     return err
   }
 
+  if s.Metrics != "" {
+    srv, err := metrics.Listen(s.Metrics, config)
+    if err != nil {
+      return err
+    }
+    defer srv.Close()
+  }
+
+  watchConfig(a)
+
   return a.Run()
 }
 
+// watchConfig reloads the settings of a when the config file changes.
+func watchConfig(a *app.App) {
+  if viper.ConfigFileUsed() == "" {
+    return
+  }
+
+  viper.OnConfigChange(func(fsnotify.Event) {
+    a.Reload(reloadSettings(viper.GetViper()))
+  })
+  viper.WatchConfig()
+}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
@@ -63,22 +170,86 @@ func Execute() {
 func init() {
   cobra.OnInitialize(initConfig)
 
+  d := settings.Defaults()
+
   rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "",
-    "config file (default is $HOME/.pomo.yaml)")
+    "config file (default is $XDG_CONFIG_HOME/pomanalyzer/config.yaml)")
 
-  rootCmd.Flags().StringP("db", "d", "pomo.db", "Database file")
+  rootCmd.PersistentFlags().StringP("db", "d", defaultDB(),
+    "Database file or DSN, e.g. memory:, sqlite:pomo.db, postgres://host/db")
 
-  rootCmd.Flags().DurationP("pomo", "p", 25*time.Minute, 
+  rootCmd.Flags().DurationP("pomo", "p", d.Pomodoro, 
                             "Pomodoro duration")
-  rootCmd.Flags().DurationP("short", "s", 5*time.Minute, 
+  rootCmd.Flags().DurationP("short", "s", d.ShortBreak, 
                             "Short break duration")
-  rootCmd.Flags().DurationP("long", "l", 15*time.Minute, 
+  rootCmd.Flags().DurationP("long", "l", d.LongBreak, 
                             "Long break duration")
+  rootCmd.Flags().Int("cycle", d.Cycle,
+                            "Number of pomodoros before a long break")
+  rootCmd.PersistentFlags().String("profile", "",
+                            "Profile to use, see \"pomo profiles\"")
+  rootCmd.Flags().StringP("task", "t", "",
+                            "Task to record on new pomodoros")
+  rootCmd.Flags().String("project", "",
+                            "Project to record on new pomodoros, such as work/api")
+  rootCmd.Flags().StringSlice("tag", nil,
+                            "Tags to record on new pomodoros")
+  rootCmd.PersistentFlags().String("user", "",
+                            "User whose history is used in a shared database (default the user logged in)")
+  rootCmd.PersistentFlags().String("timezone", "",
+                            "Time zone for daily summaries (default local)")
+  rootCmd.PersistentFlags().Int("day-start", 0,
+                            "Hour of the day at which a new day starts")
+  rootCmd.PersistentFlags().Duration("db-timeout", d.DBTimeout,
+                            "Timeout for each database operation")
+
+  rootCmd.PersistentFlags().Bool("exclude-manual", false,
+                            "Leave manually added intervals out of summaries")
+  rootCmd.PersistentFlags().String("backup-dir", "",
+                            "Backup directory (default \"backups\" next to the database)")
+  rootCmd.PersistentFlags().Int("backup-keep", d.BackupKeep,
+                            "Number of backups to keep, 0 to keep all")
+  rootCmd.Flags().String("calendar", "",
+                            "Calendar .ics file of meetings to plan pomodoros around")
+  rootCmd.Flags().String("metrics", "",
+                            "Address to serve Prometheus metrics on, such as localhost:9191")
+  rootCmd.Flags().Bool("auto-backup", false,
+                            "Back up the database on the first launch of each day")
 
-  viper.BindPFlag("db", rootCmd.Flags().Lookup("db"))
+  // Defaults for the settings without a flag
+  settings.SetDefaults(viper.GetViper())
+  viper.SetDefault("db", defaultDB())
+
+  viper.BindPFlag("db", rootCmd.PersistentFlags().Lookup("db"))
   viper.BindPFlag("pomo", rootCmd.Flags().Lookup("pomo"))
   viper.BindPFlag("short", rootCmd.Flags().Lookup("short"))
   viper.BindPFlag("long", rootCmd.Flags().Lookup("long"))
+  viper.BindPFlag("cycle", rootCmd.Flags().Lookup("cycle"))
+  viper.BindPFlag("task", rootCmd.Flags().Lookup("task"))
+  viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+  viper.BindPFlag("user", rootCmd.PersistentFlags().Lookup("user"))
+  viper.BindPFlag("timezone", rootCmd.PersistentFlags().Lookup("timezone"))
+  viper.BindPFlag("daystart", rootCmd.PersistentFlags().Lookup("day-start"))
+  viper.BindPFlag("dbtimeout", rootCmd.PersistentFlags().Lookup("db-timeout"))
+  viper.BindPFlag("excludemanual",
+    rootCmd.PersistentFlags().Lookup("exclude-manual"))
+  viper.BindPFlag("backupdir", rootCmd.PersistentFlags().Lookup("backup-dir"))
+  viper.BindPFlag("backupkeep", rootCmd.PersistentFlags().Lookup("backup-keep"))
+  viper.BindPFlag("autobackup", rootCmd.Flags().Lookup("auto-backup"))
+  viper.BindPFlag("calendar", rootCmd.Flags().Lookup("calendar"))
+  viper.BindPFlag("metrics", rootCmd.Flags().Lookup("metrics"))
+}
+
+
+// envPrefix starts the environment variables read, so common ones such as
+// USER don't override the config file.
+const envPrefix = "POMO"
+
+// readEnv makes v read settings from environment variables named after
+// their keys, such as POMO_DAYSTART.
+func readEnv(v *viper.Viper) {
+  v.SetEnvPrefix(envPrefix)
+  v.AutomaticEnv()
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -87,22 +258,24 @@ func initConfig() {
     // Use config file from the flag.
     viper.SetConfigFile(cfgFile)
   } else {
-    // Find home directory.
-    home, err := homedir.Dir()
-    if err != nil {
-      fmt.Println(err)
-      os.Exit(1)
+    // Move a config file from the home directory used by older versions
+    if err := migrateConfig(os.Stderr); err != nil {
+      fmt.Fprintln(os.Stderr, "Moving config file failed:", err)
     }
 
-    // Search config in home directory with name ".pomo" (without extension).
-    viper.AddConfigPath(home)
-    viper.SetConfigName(".pomo")
+    // Search config in the config directory with name "config" (without
+    // extension).
+    viper.AddConfigPath(configDir())
+    viper.SetConfigName("config")
   }
 
-  viper.AutomaticEnv() // read in environment variables that match
+  readEnv(viper.GetViper())
 
-  // If a config file is found, read it in.
-  if err := viper.ReadInConfig(); err == nil {
-    fmt.Println("Using config file:", viper.ConfigFileUsed())
+  // If a config file is found, read it in. `pomo paths` shows which one.
+  configErr = nil
+  if err := viper.ReadInConfig(); err != nil {
+    if !errors.As(err, &viper.ConfigFileNotFoundError{}) {
+      configErr = fmt.Errorf("reading config file: %w", err)
+    }
   }
 }
\ No newline at end of file
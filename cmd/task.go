@@ -0,0 +1,444 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/tasksync"
+)
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+  Use:   "task",
+  Short: "Plan tasks and compare their pomodoros with the estimate",
+  Long: `Keep the "To Do Today" list of the technique: add tasks with the
+number of pomodoros you expect them to take, pick one with --task or from
+the dashboard, and compare the pomodoros done with the estimate.
+
+Pomodoros count toward a task when they're timed or added while it's the
+current task.`,
+}
+
+// taskAddCmd represents the task add command
+var taskAddCmd = &cobra.Command{
+  Use:     "add <name>",
+  Short:   "Add a task to the list",
+  Example: `  pomo task add "write report" --estimate 3`,
+  Args:    cobra.MinimumNArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    estimate, _ := cmd.Flags().GetInt("estimate")
+
+    return taskAddAction(cmd.Context(), os.Stdout, config,
+      strings.Join(args, " "), estimate)
+  },
+}
+
+// taskListCmd represents the task list command
+var taskListCmd = &cobra.Command{
+  Use:   "list",
+  Short: "List the open tasks with their pomodoros",
+  Args:  cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    all, _ := cmd.Flags().GetBool("all")
+
+    return taskListAction(cmd.Context(), os.Stdout, config, all)
+  },
+}
+
+// taskDoneCmd represents the task done command
+var taskDoneCmd = &cobra.Command{
+  Use:   "done <id>",
+  Short: "Mark a task done",
+  Args:  cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    id, err := parseTaskID(args[0])
+    if err != nil {
+      return err
+    }
+
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    undo, _ := cmd.Flags().GetBool("undo")
+
+    return taskDoneAction(cmd.Context(), os.Stdout, config, id, !undo)
+  },
+}
+
+// taskDeleteCmd represents the task delete command
+var taskDeleteCmd = &cobra.Command{
+  Use:   "delete <id>",
+  Short: "Remove a task from the list",
+  Long: `Remove a task from the list. Its pomodoros stay in the history with
+the task name.`,
+  Args: cobra.ExactArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    id, err := parseTaskID(args[0])
+    if err != nil {
+      return err
+    }
+
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    if err := pomodoro.DeleteTask(cmd.Context(), config, id); err != nil {
+      return err
+    }
+
+    _, err = fmt.Fprintf(os.Stdout, "Task %d deleted.\n", id)
+    return err
+  },
+}
+
+// taskImportCmd represents the task import command
+var taskImportCmd = &cobra.Command{
+  Use:   "import [file]",
+  Short: "Import tasks from todo.txt or Taskwarrior",
+  Long: `Import the tasks of a todo.txt file or of the JSON written by
+"task export", from file or from stdin if it's "-" or missing. Importing
+again updates the tasks imported before instead of adding them twice.
+
+In todo.txt, the estimate is read from an est:N pair. In Taskwarrior, it's
+read from an "estimate" attribute if you defined one.`,
+  Example: `  pomo task import ~/todo.txt
+  task status:pending export | pomo task import`,
+  Args: cobra.MaximumNArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    format, _ := cmd.Flags().GetString("format")
+
+    return taskImportAction(cmd.Context(), os.Stdin, os.Stdout, config,
+      fileArg(args), format)
+  },
+}
+
+// taskWritebackCmd represents the task writeback command
+var taskWritebackCmd = &cobra.Command{
+  Use:   "writeback [file]",
+  Short: "Write the pomodoros done back to todo.txt or Taskwarrior",
+  Long: `Write the pomodoros done for imported tasks back to the file they
+came from.
+
+A todo.txt file gets a pomo:N pair on each task, and is changed in place
+unless --output is set or it's read from stdin. A Taskwarrior export gets
+an annotation on each task, and the changed tasks are written as JSON for
+"task import".`,
+  Example: `  pomo task writeback ~/todo.txt
+  task export | pomo task writeback | task import`,
+  Args: cobra.MaximumNArgs(1),
+  RunE: func(cmd *cobra.Command, args []string) error {
+    config, err := taskConfig()
+    if err != nil {
+      return err
+    }
+
+    format, _ := cmd.Flags().GetString("format")
+    output, _ := cmd.Flags().GetString("output")
+
+    return taskWritebackAction(cmd.Context(), os.Stdin, os.Stdout, config,
+      fileArg(args), format, output)
+  },
+}
+
+func taskConfig() (*pomodoro.IntervalConfig, error) {
+  repo, err := getRepo()
+  if err != nil {
+    return nil, err
+  }
+
+  return getConfig(repo)
+}
+
+func parseTaskID(arg string) (int64, error) {
+  id, err := strconv.ParseInt(arg, 10, 64)
+  if err != nil {
+    return 0, fmt.Errorf("%w: %s", pomodoro.ErrInvalidID, arg)
+  }
+
+  return id, nil
+}
+
+func taskAddAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, name string, estimate int) error {
+
+  t, err := pomodoro.AddTask(ctx, config, name, estimate)
+  if err != nil {
+    return err
+  }
+
+  switch t.Estimate {
+  case 0:
+    _, err = fmt.Fprintf(out, "Added task %d %q.\n", t.ID, t.Name)
+  case 1:
+    _, err = fmt.Fprintf(out, "Added task %d %q, estimated at 1 pomodoro.\n",
+      t.ID, t.Name)
+  default:
+    _, err = fmt.Fprintf(out, "Added task %d %q, estimated at %d pomodoros.\n",
+      t.ID, t.Name, t.Estimate)
+  }
+  return err
+}
+
+func taskListAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, all bool) error {
+
+  list, err := pomodoro.Tasks(ctx, config, all)
+  if err != nil {
+    return err
+  }
+
+  if len(list) == 0 {
+    _, err := fmt.Fprintln(out, `No tasks, add one with "pomo task add".`)
+    return err
+  }
+
+  done, estimated := 0, 0
+
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintln(w, "ID\tTASK\tDONE\tESTIMATE\tSTATUS")
+  for _, t := range list {
+    estimate := "-"
+    if t.Estimate > 0 {
+      estimate = strconv.Itoa(t.Estimate)
+      done += t.Pomodoros
+      estimated += t.Estimate
+    }
+
+    status := "open"
+    switch {
+    case t.Done():
+      status = "done " + t.Completed.In(config.Location).Format("2006-01-02")
+    case t.Over():
+      status = fmt.Sprintf("over by %d", t.Pomodoros-t.Estimate)
+    }
+
+    fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\n", t.ID, t.Name, t.Pomodoros,
+      estimate, status)
+  }
+  if err := w.Flush(); err != nil {
+    return err
+  }
+
+  if estimated > 0 {
+    _, err = fmt.Fprintf(out, "\n%d of %d estimated pomodoros done.\n", done,
+      estimated)
+  }
+  return err
+}
+
+func taskDoneAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, id int64, done bool) error {
+
+  t, err := pomodoro.CompleteTask(ctx, config, id, done)
+  if err != nil {
+    return err
+  }
+
+  state := "open again"
+  if done {
+    state = "done"
+  }
+
+  _, err = fmt.Fprintf(out, "Task %d %q is %s.\n", t.ID, t.Name, state)
+  return err
+}
+
+// fileArg returns the file given in args, or "-" for stdin.
+func fileArg(args []string) string {
+  if len(args) == 0 {
+    return "-"
+  }
+
+  return args[0]
+}
+
+// readTasksFile reads path, or in if path is "-", and returns its content
+// with the source of its format.
+func readTasksFile(in io.Reader, path,
+  format string) ([]byte, string, error) {
+
+  var (
+    data []byte
+    err  error
+  )
+  if path == "-" {
+    data, err = io.ReadAll(in)
+  } else {
+    data, err = os.ReadFile(path)
+  }
+  if err != nil {
+    return nil, "", err
+  }
+
+  if format == "" {
+    return data, tasksync.Detect(data), nil
+  }
+
+  source, err := tasksync.ParseSource(format)
+  return data, source, err
+}
+
+func taskImportAction(ctx context.Context, in io.Reader, out io.Writer,
+  config *pomodoro.IntervalConfig, path, format string) error {
+
+  data, source, err := readTasksFile(in, path, format)
+  if err != nil {
+    return err
+  }
+
+  tasks, err := tasksync.Read(source, data)
+  if err != nil {
+    return err
+  }
+
+  added := 0
+  for _, t := range tasks {
+    _, isNew, err := pomodoro.ImportTask(ctx, config, t)
+    if err != nil {
+      return fmt.Errorf("importing %q: %w", t.Name, err)
+    }
+    if isNew {
+      added++
+    }
+  }
+
+  _, err = fmt.Fprintf(out, "Imported %d tasks from %s, %d new.\n",
+    len(tasks), source, added)
+  return err
+}
+
+func taskWritebackAction(ctx context.Context, in io.Reader, out io.Writer,
+  config *pomodoro.IntervalConfig, path, format, output string) error {
+
+  data, source, err := readTasksFile(in, path, format)
+  if err != nil {
+    return err
+  }
+
+  list, err := pomodoro.Tasks(ctx, config, true)
+  if err != nil {
+    return err
+  }
+  pomodoros := tasksync.Pomodoros(list, source)
+
+  // todo.txt files are updated in place
+  if output == "" && path != "-" && source == tasksync.SourceTodoTxt {
+    output = path
+  }
+
+  if output == "" || output == "-" {
+    return tasksync.Write(source, data, out, pomodoros)
+  }
+
+  var buf bytes.Buffer
+  if err := tasksync.Write(source, data, &buf, pomodoros); err != nil {
+    return err
+  }
+
+  if err := writeFileAtomic(output, buf.Bytes()); err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "Wrote the pomodoros of %d tasks to %s.\n",
+    len(pomodoros), output)
+  return err
+}
+
+// writeFileAtomic replaces path with data, writing it next to path first
+// so a failure leaves the old file in place.
+func writeFileAtomic(path string, data []byte) error {
+  mode := os.FileMode(0o644)
+  if fi, err := os.Stat(path); err == nil {
+    mode = fi.Mode().Perm()
+  }
+
+  tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+  if err != nil {
+    return err
+  }
+  defer os.Remove(tmp.Name())
+
+  if _, err := tmp.Write(data); err != nil {
+    tmp.Close()
+    return err
+  }
+
+  if err := tmp.Close(); err != nil {
+    return err
+  }
+
+  if err := os.Chmod(tmp.Name(), mode); err != nil {
+    return err
+  }
+
+  return os.Rename(tmp.Name(), path)
+}
+
+// useTask makes the open task called name the current one, so new
+// pomodoros count toward it. A name that isn't on the list is only
+// recorded as the task of new pomodoros.
+func useTask(ctx context.Context, config *pomodoro.IntervalConfig,
+  name string) error {
+
+  t, err := pomodoro.FindTask(ctx, config, name)
+  if errors.Is(err, pomodoro.ErrInvalidTask) {
+    config.SetTask(pomodoro.Task{Name: name})
+    return nil
+  }
+  if err != nil {
+    return err
+  }
+
+  config.SetTask(t)
+  return nil
+}
+
+func init() {
+  rootCmd.AddCommand(taskCmd)
+
+  taskCmd.AddCommand(taskAddCmd)
+  taskCmd.AddCommand(taskListCmd)
+  taskCmd.AddCommand(taskDoneCmd)
+  taskCmd.AddCommand(taskDeleteCmd)
+  taskCmd.AddCommand(taskImportCmd)
+  taskCmd.AddCommand(taskWritebackCmd)
+
+  taskAddCmd.Flags().IntP("estimate", "e", 0, "Pomodoros the task should take")
+  taskListCmd.Flags().BoolP("all", "a", false, "Include the done tasks")
+  taskDoneCmd.Flags().Bool("undo", false, "Open the task again")
+
+  for _, c := range []*cobra.Command{taskImportCmd, taskWritebackCmd} {
+    c.Flags().StringP("format", "f", "",
+      "todo.txt or taskwarrior, detected if not set")
+  }
+  taskWritebackCmd.Flags().StringP("output", "o", "",
+    "File to write, - for stdout")
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestTaskActions(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  var out bytes.Buffer
+  if err := taskListAction(ctx, &out, config, false); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "No tasks") {
+    t.Errorf("Expected no tasks, got %q", out.String())
+  }
+
+  for _, name := range []string{"write report", "review PR"} {
+    if err := taskAddAction(ctx, &out, config, name, 2); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  // Manual sessions on a planned task count toward it
+  for k := 0; k < 3; k++ {
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      time.Now().Add(time.Duration(-k-1) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           "Write Report",
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  if err := taskDoneAction(ctx, &out, config, 2, true); err != nil {
+    t.Fatal(err)
+  }
+
+  out.Reset()
+  if err := taskListAction(ctx, &out, config, false); err != nil {
+    t.Fatal(err)
+  }
+
+  exp := []string{
+    "ID  TASK          DONE  ESTIMATE  STATUS",
+    "1   write report  3     2         over by 1",
+    "",
+    "3 of 2 estimated pomodoros done.",
+  }
+  if got := strings.TrimSpace(out.String()); got != strings.Join(exp, "\n") {
+    t.Errorf("Expected:\n%s\ngot:\n%s", strings.Join(exp, "\n"), got)
+  }
+
+  out.Reset()
+  if err := taskListAction(ctx, &out, config, true); err != nil {
+    t.Fatal(err)
+  }
+  if !strings.Contains(out.String(), "review PR     0     2         done") {
+    t.Errorf("Expected the done task to be listed, got:\n%s", out.String())
+  }
+
+  err = taskDoneAction(ctx, &out, config, 9, true)
+  if !errors.Is(err, pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidID, err)
+  }
+}
+
+func TestTaskImportWriteback(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  path := filepath.Join(t.TempDir(), "todo.txt")
+  todo := "(A) Write report +thesis est:2\nx Review PR\n"
+  if err := os.WriteFile(path, []byte(todo), 0o600); err != nil {
+    t.Fatal(err)
+  }
+
+  var out bytes.Buffer
+  // Importing twice doesn't add the tasks again
+  for _, exp := range []string{"2 new", "0 new"} {
+    out.Reset()
+    if err := taskImportAction(ctx, nil, &out, config, path, ""); err != nil {
+      t.Fatal(err)
+    }
+    if !strings.Contains(out.String(), exp) {
+      t.Errorf("Expected %q, got %q", exp, out.String())
+    }
+  }
+
+  report, err := pomodoro.FindTask(ctx, config, "write report +thesis")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if report.Estimate != 2 {
+    t.Errorf("Expected estimate 2, got %d", report.Estimate)
+  }
+
+  err = addAction(ctx, &out, config, pomodoro.Interval{
+    StartTime:      time.Now().Add(-time.Hour),
+    ActualDuration: 25 * time.Minute,
+    Category:       pomodoro.CategoryPomodoro,
+    Task:           report.Name,
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  err = taskWritebackAction(ctx, nil, &out, config, path, "", "")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  data, err := os.ReadFile(path)
+  if err != nil {
+    t.Fatal(err)
+  }
+  exp := "(A) Write report +thesis est:2 pomo:1\nx Review PR\n"
+  if string(data) != exp {
+    t.Errorf("Expected %q, got %q instead", exp, string(data))
+  }
+
+  // Taskwarrior exports read from stdin are written to stdout
+  in := strings.NewReader(`[{"uuid":"u1","description":"Plan","status":"pending"}]`)
+  out.Reset()
+  err = taskWritebackAction(ctx, in, &out, config, "-", "", "")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if strings.TrimSpace(out.String()) != "[]" {
+    t.Errorf("Expected no tasks to update, got %q", out.String())
+  }
+}
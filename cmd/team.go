@@ -0,0 +1,173 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// teamCmd represents the team command
+var teamCmd = &cobra.Command{
+  Use:   "team",
+  Short: "Show what each user of a shared database is doing",
+  Long: `Show each user of a shared database, such as a PostgreSQL database or
+a SQLite file on a network drive, with what they're doing now, the time
+left in their interval, and the pomodoros done and focus time over a
+period, today by default, followed by the team total.
+
+Each user only sees their own history everywhere else. Users are named
+with --user or the user setting, or after the user logged in. Intervals
+recorded before that aren't anyone's, so they're left out of the team,
+and are shown to everyone until adopted with "pomo team adopt".`,
+  Example: `  pomo team --db postgres://host/pomanalyzer
+  pomo team --from 2026-10-12 --to 2026-10-19 --project work`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    now := time.Now()
+    start, end := config.DayBounds(now)
+
+    flags := cmd.Flags()
+    if from, _ := flags.GetString("from"); from != "" {
+      if start, err = parseTime(from, config.Location); err != nil {
+        return err
+      }
+    }
+    if to, _ := flags.GetString("to"); to != "" {
+      if end, err = parseTime(to, config.Location); err != nil {
+        return err
+      }
+    }
+
+    project, _ := flags.GetString("project")
+    tag, _ := flags.GetString("tag")
+    f, err := parseFilter(project, tag)
+    if err != nil {
+      return err
+    }
+    config.SetFilter(f)
+
+    return teamAction(cmd.Context(), os.Stdout, config, start, end)
+  },
+}
+
+// teamAdoptCmd represents the team adopt command
+var teamAdoptCmd = &cobra.Command{
+  Use:   "adopt",
+  Short: "Make the intervals recorded before users yours",
+  Long: `Make the intervals and tasks recorded before histories were kept per
+user yours, so you can edit and delete them. Until then everyone sees
+them, but nobody can change them.`,
+  Args: cobra.NoArgs,
+  RunE: func(cmd *cobra.Command, args []string) error {
+    repo, err := getRepo()
+    if err != nil {
+      return err
+    }
+
+    config, err := getConfig(repo)
+    if err != nil {
+      return err
+    }
+
+    return adoptAction(cmd.Context(), os.Stdout, config)
+  },
+}
+
+// currentUser returns the name of the user logged in, which names their
+// history in shared databases unless the user setting is set.
+func currentUser() string {
+  u, err := user.Current()
+  if err != nil || u.Username == "" {
+    return os.Getenv("USER")
+  }
+
+  // Windows user names start with the domain
+  name := u.Username
+  return name[strings.LastIndex(name, `\`)+1:]
+}
+
+func teamAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig, start, end time.Time) error {
+
+  team, err := pomodoro.TeamSummary(ctx, start, end, config)
+  if err != nil {
+    return err
+  }
+
+  layout := "2006-01-02 15:04"
+  fmt.Fprintf(out, "Team from %s to %s", start.In(config.Location).Format(layout),
+    end.In(config.Location).Format(layout))
+  if f := config.Filter.String(); f != "" {
+    fmt.Fprintf(out, " in %s", f)
+  }
+  fmt.Fprint(out, "\n\n")
+
+  if len(team) == 0 {
+    _, err := fmt.Fprintln(out, "No users.")
+    return err
+  }
+
+  var total pomodoro.Member
+  w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+  fmt.Fprintln(w, "USER\tNOW\tLEFT\tPOMODOROS\tFOCUS\tBREAKS")
+  for _, m := range team {
+    left := "-"
+    if m.Busy() {
+      left = m.Current.Remaining().Round(time.Second).String()
+    }
+
+    fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\t%s\n", m.User,
+      m.Activity(), left, m.Pomodoros, m.Focus.Round(time.Second),
+      m.Breaks.Round(time.Second))
+
+    total.Pomodoros += m.Pomodoros
+    total.Focus += m.Focus
+    total.Breaks += m.Breaks
+  }
+  fmt.Fprintf(w, "TOTAL\t\t\t%d\t%s\t%s\n", total.Pomodoros,
+    total.Focus.Round(time.Second), total.Breaks.Round(time.Second))
+
+  return w.Flush()
+}
+
+func adoptAction(ctx context.Context, out io.Writer,
+  config *pomodoro.IntervalConfig) error {
+
+  n, err := pomodoro.Adopt(ctx, config)
+  if err != nil {
+    return err
+  }
+
+  _, err = fmt.Fprintf(out, "%d intervals and tasks now belong to %s\n", n,
+    config.User)
+  return err
+}
+
+func init() {
+  rootCmd.AddCommand(teamCmd)
+  teamCmd.AddCommand(teamAdoptCmd)
+
+  teamCmd.Flags().String("from", "", "Start of the period (default today)")
+  teamCmd.Flags().String("to", "", "End of the period (default end of today)")
+  teamCmd.Flags().String("project", "",
+    "Only count this project and its subprojects")
+  teamCmd.Flags().String("tag", "", "Only count pomodoros with this tag")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestTeamAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  day := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+  var out bytes.Buffer
+  for k, user := range []string{"alice", "bob", "alice"} {
+    config := pomodoro.NewConfig(repo, 0, 0, 0)
+    config.User = user
+    err := addAction(ctx, &out, config, pomodoro.Interval{
+      StartTime:      day.Add(time.Duration(9+k) * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  out.Reset()
+  if err := teamAction(ctx, &out, config, day, day.AddDate(0, 0, 1)); err != nil {
+    t.Fatal(err)
+  }
+
+  for _, exp := range []string{
+    "Team from 2026-10-14 00:00 to 2026-10-15 00:00\n",
+    "USER   NOW   LEFT  POMODOROS  FOCUS    BREAKS\n",
+    "alice  idle  -     2          50m0s    0s\n",
+    "bob    idle  -     1          25m0s    0s\n",
+    "TOTAL              3          1h15m0s  0s\n",
+  } {
+    if !strings.Contains(out.String(), exp) {
+      t.Errorf("Expected %q in:\n%s", exp, out.String())
+    }
+  }
+}
+
+func TestAdoptAction(t *testing.T) {
+  ctx := context.Background()
+  repo, err := repository.Open("memory:")
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  var out bytes.Buffer
+  legacy := pomodoro.NewConfig(repo, 0, 0, 0)
+  if err := addAction(ctx, &out, legacy, pomodoro.Interval{
+    StartTime:      time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC),
+    ActualDuration: 25 * time.Minute,
+    Category:       pomodoro.CategoryPomodoro,
+  }); err != nil {
+    t.Fatal(err)
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.User = "alice"
+
+  out.Reset()
+  if err := adoptAction(ctx, &out, config); err != nil {
+    t.Fatal(err)
+  }
+
+  exp := "1 intervals and tasks now belong to alice\n"
+  if out.String() != exp {
+    t.Errorf("Expected %q, got %q", exp, out.String())
+  }
+
+  if err := pomodoro.Delete(ctx, config, 1); err != nil {
+    t.Errorf("Expected alice to delete the adopted interval, got %v", err)
+  }
+}
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+)
+
+// timeLayouts are the accepted formats for date and time flags, from the
+// most to the least precise.
+var timeLayouts = []string{
+  time.RFC3339,
+  "2006-01-02T15:04:05",
+  "2006-01-02T15:04",
+  "2006-01-02 15:04",
+  "2006-01-02",
+}
+
+// parseTime parses a date or time flag value. Values without a zone
+// offset are read in loc.
+func parseTime(value string, loc *time.Location) (time.Time, error) {
+  for _, layout := range timeLayouts {
+    if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+      return t, nil
+    }
+  }
+
+  return time.Time{}, fmt.Errorf("invalid time %q: use YYYY-MM-DD or YYYY-MM-DDTHH:MM",
+    value)
+}
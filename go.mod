@@ -3,14 +3,16 @@ module github.com/xasterKies/pomanalyzer
 go 1.21.4
 
 require (
+	github.com/fsnotify/fsnotify v1.7.0
+	github.com/lib/pq v1.10.9
 	github.com/mattn/go-sqlite3 v1.14.23
 	github.com/mitchellh/go-homedir v1.1.0
 	github.com/mum4k/termdash v0.13.0
 	github.com/spf13/viper v1.19.0
+	golang.org/x/image v0.18.0
 )
 
 require (
-	github.com/fsnotify/fsnotify v1.7.0 // indirect
 	github.com/hashicorp/hcl v1.0.0 // indirect
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/magiconair/properties v1.8.7 // indirect
@@ -36,6 +38,6 @@ require (
 	github.com/lucasb-eyer/go-colorful v1.0.3 // indirect
 	github.com/mattn/go-runewidth v0.0.9 // indirect
 	github.com/spf13/cobra v1.8.1
-	golang.org/x/sys v0.18.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
+	golang.org/x/sys v0.18.0
+	golang.org/x/text v0.16.0 // indirect
 )
@@ -0,0 +1,99 @@
+// Package ical writes done pomodoros as iCalendar events, and reads the
+// meetings of an .ics file so pomodoros can be planned around them.
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// utcLayout is the iCalendar format of UTC date-times.
+const utcLayout = "20060102T150405Z"
+
+// Write writes the pomodoros in list to w as an iCalendar file, one
+// VEVENT each, stamped with now.
+func Write(w io.Writer, list []pomodoro.Interval, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeLine(bw, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//pomanalyzer//pomo//EN")
+	line("CALSCALE", "GREGORIAN")
+
+	for _, i := range list {
+		line("BEGIN", "VEVENT")
+		line("UID", fmt.Sprintf("pomodoro-%d-%s@pomanalyzer", i.ID,
+			i.StartTime.UTC().Format(utcLayout)))
+		line("DTSTAMP", now.UTC().Format(utcLayout))
+		line("DTSTART", i.StartTime.UTC().Format(utcLayout))
+		line("DTEND", i.StartTime.Add(i.ActualDuration).UTC().Format(utcLayout))
+		line("SUMMARY", escape(summary(i)))
+
+		categories := []string{}
+		if i.Project != "" {
+			categories = append(categories, escape(i.Project))
+		}
+		for _, t := range i.Tags {
+			categories = append(categories, escape(t))
+		}
+		if len(categories) > 0 {
+			line("CATEGORIES", strings.Join(categories, ","))
+		}
+
+		if i.Note != "" {
+			line("DESCRIPTION", escape(i.Note))
+		}
+		line("TRANSP", "OPAQUE")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return bw.Flush()
+}
+
+// summary is the title of the event of pomodoro i.
+func summary(i pomodoro.Interval) string {
+	if i.Task == "" {
+		return i.Category
+	}
+
+	return i.Category + ": " + i.Task
+}
+
+// escape escapes the characters with a meaning in iCalendar text values.
+func escape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`,
+		"\r", "").Replace(s)
+}
+
+// writeLine writes l with a CRLF ending, folded every 75 octets without
+// splitting UTF-8 characters.
+func writeLine(w *bufio.Writer, l string) {
+	limit := 75
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(l[cut]) {
+			cut--
+		}
+
+		w.WriteString(l[:cut] + "\r\n ")
+		l = l[cut:]
+		// The leading space of the continuation counts toward its length
+		limit = 74
+	}
+
+	w.WriteString(l + "\r\n")
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
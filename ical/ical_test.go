@@ -0,0 +1,165 @@
+package ical_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/ical"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+const meetings = `BEGIN:VCALENDAR
+VERSION:2.0
+BEGIN:VTIMEZONE
+TZID:Europe/Paris
+END:VTIMEZONE
+BEGIN:VEVENT
+UID:standup
+SUMMARY:Stand
+  up
+DTSTART;TZID=Europe/Paris:20261012T100000
+DTEND;TZID=Europe/Paris:20261012T101500
+RRULE:FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR;UNTIL=20261231T000000Z
+EXDATE;TZID=Europe/Paris:20261014T100000
+BEGIN:VALARM
+TRIGGER:-PT5M
+DESCRIPTION:Reminder
+END:VALARM
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID;TZID=Europe/Paris:20261016T100000
+SUMMARY:Late standup
+DTSTART;TZID=Europe/Paris:20261016T113000
+DURATION:PT15M
+END:VEVENT
+BEGIN:VEVENT
+UID:review
+SUMMARY:Design review\, round 2
+DTSTART:20261015T130000Z
+DURATION:PT1H
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+SUMMARY:Cancelled
+DTSTART:20261015T150000Z
+DTEND:20261015T160000Z
+STATUS:CANCELLED
+END:VEVENT
+BEGIN:VEVENT
+UID:holiday
+SUMMARY:Holiday
+DTSTART;VALUE=DATE:20261015
+DTEND;VALUE=DATE:20261016
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestRead(t *testing.T) {
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	c, err := ical.Read(strings.NewReader(meetings), time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2026, 10, day, hour, min, 0, 0, paris)
+	}
+
+	got := c.Meetings(at(13, 0, 0), at(17, 0, 0))
+	exp := []ical.Meeting{
+		{"Stand up", at(13, 10, 0), at(13, 10, 15)},
+		{"Stand up", at(15, 10, 0), at(15, 10, 15)},
+		{"Design review, round 2", at(15, 15, 0), at(15, 16, 0)},
+		{"Late standup", at(16, 11, 30), at(16, 11, 45)},
+	}
+
+	if len(got) != len(exp) {
+		t.Fatalf("Expected %d meetings, got %v", len(exp), got)
+	}
+	for k := range exp {
+		if got[k].Summary != exp[k].Summary || !got[k].Start.Equal(exp[k].Start) ||
+			!got[k].End.Equal(exp[k].End) {
+			t.Errorf("Expected %v, got %v", exp[k], got[k])
+		}
+	}
+
+	// The standup keeps its wall clock time after daylight saving ends
+	m, ok := c.Next(at(26, 9, 0), at(26, 12, 0))
+	if !ok || !m.Start.Equal(at(26, 10, 0)) {
+		t.Errorf("Expected the standup at 10:00 after DST, got %v, %t", m, ok)
+	}
+
+	// A meeting under way counts
+	if m, ok := c.Next(at(13, 10, 5), at(13, 10, 30)); !ok ||
+		m.Summary != "Stand up" {
+		t.Errorf("Expected the standup under way, got %v, %t", m, ok)
+	}
+
+	if _, ok := c.Next(at(17, 0, 0), at(19, 0, 0)); ok {
+		t.Error("Expected no meetings on the weekend")
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	data := "BEGIN:VEVENT\nDTSTART:yesterday\nEND:VEVENT\n"
+	if _, err := ical.Read(strings.NewReader(data), time.UTC); !errors.Is(err,
+		ical.ErrInvalidCalendar) {
+		t.Errorf("Expected error %q, got %v", ical.ErrInvalidCalendar, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	list := []pomodoro.Interval{
+		{ID: 1, StartTime: start, ActualDuration: 25 * time.Minute,
+			Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+			Task: "write report; part 2", Project: "work/api",
+			Tags: []string{"backend"},
+			Note: strings.Repeat("a long note, ", 10)},
+	}
+
+	var out bytes.Buffer
+	if err := ical.Write(&out, list, start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	data := out.String()
+	for _, exp := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:pomodoro-1-20261016T090000Z@pomanalyzer\r\n",
+		"DTSTAMP:20261016T100000Z\r\n",
+		"DTSTART:20261016T090000Z\r\nDTEND:20261016T092500Z\r\n",
+		`SUMMARY:Pomodoro: write report\; part 2` + "\r\n",
+		"CATEGORIES:work/api,backend\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(data, exp) {
+			t.Errorf("Expected %q in:\n%s", exp, data)
+		}
+	}
+
+	for _, l := range strings.Split(data, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("Expected lines folded at 75 octets, got %q", l)
+		}
+	}
+
+	// The events read back as meetings
+	c, err := ical.Read(&out, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, ok := c.Next(start, start.Add(time.Hour))
+	if !ok || m.Summary != "Pomodoro: write report; part 2" ||
+		!m.End.Equal(start.Add(25*time.Minute)) {
+		t.Errorf("Expected the pomodoro read back, got %v, %t", m, ok)
+	}
+}
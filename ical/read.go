@@ -0,0 +1,308 @@
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidCalendar is returned for .ics data that can't be read.
+var ErrInvalidCalendar = errors.New("Invalid calendar")
+
+// Meeting is one occurrence of a calendar event.
+type Meeting struct {
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// event is a VEVENT, repeated by its rule if it has one.
+type event struct {
+	uid          string
+	summary      string
+	start        time.Time
+	duration     time.Duration
+	rule         *rule
+	exdates      []time.Time
+	recurrenceID time.Time
+}
+
+// Calendar holds the events of an .ics file that take up time. All-day,
+// cancelled and free events are left out.
+type Calendar struct {
+	events []event
+}
+
+// Read returns the calendar in r. Floating times, without a time zone, are
+// read in loc.
+func Read(r io.Reader, loc *time.Location) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Calendar{}
+	var (
+		e       *event
+		end     time.Time
+		skip    bool
+		nesting int
+	)
+
+	for n, l := range lines {
+		name, params, value := splitLine(l)
+		fail := func(err error) error {
+			return fmt.Errorf("%w: line %d: %s: %w", ErrInvalidCalendar, n+1,
+				name, err)
+		}
+
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			e, end, skip = &event{}, time.Time{}, false
+			continue
+		case e == nil:
+			continue
+		case name == "BEGIN":
+			// Alarms inside events have properties of their own
+			nesting++
+			continue
+		case name == "END" && nesting > 0:
+			nesting--
+			continue
+		case nesting > 0:
+			continue
+		case name == "END" && value == "VEVENT":
+			if e.duration == 0 && !end.IsZero() {
+				e.duration = end.Sub(e.start)
+			}
+			if !skip && !e.start.IsZero() && e.duration > 0 {
+				c.events = append(c.events, *e)
+			}
+			e = nil
+			continue
+		}
+
+		switch name {
+		case "UID":
+			e.uid = value
+		case "SUMMARY":
+			e.summary = unescape(value)
+		case "DTSTART":
+			var allDay bool
+			e.start, allDay, err = parseTime(params, value, loc)
+			skip = skip || allDay
+		case "DTEND":
+			end, _, err = parseTime(params, value, loc)
+		case "DURATION":
+			e.duration, err = parseDuration(value)
+		case "RRULE":
+			e.rule, err = parseRule(value, loc)
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var t time.Time
+				if t, _, err = parseTime(params, v, loc); err != nil {
+					break
+				}
+				e.exdates = append(e.exdates, t)
+			}
+		case "RECURRENCE-ID":
+			e.recurrenceID, _, err = parseTime(params, value, loc)
+		case "STATUS":
+			skip = skip || value == "CANCELLED"
+		case "TRANSP":
+			skip = skip || value == "TRANSPARENT"
+		}
+
+		if err != nil {
+			return nil, fail(err)
+		}
+	}
+
+	c.moveOccurrences()
+
+	return c, nil
+}
+
+// moveOccurrences drops the occurrences of repeated events that were
+// moved or changed, as those are events of their own with the same UID.
+func (c *Calendar) moveOccurrences() {
+	moved := map[string][]time.Time{}
+	for _, e := range c.events {
+		if !e.recurrenceID.IsZero() {
+			moved[e.uid] = append(moved[e.uid], e.recurrenceID)
+		}
+	}
+
+	for k, e := range c.events {
+		if e.rule != nil && e.recurrenceID.IsZero() {
+			c.events[k].exdates = append(e.exdates, moved[e.uid]...)
+		}
+	}
+}
+
+// Meetings returns the meetings taking place at some point between start
+// and end, sorted by start time.
+func (c *Calendar) Meetings(start, end time.Time) []Meeting {
+	meetings := []Meeting{}
+	for _, e := range c.events {
+		e.occurrences(end, func(t time.Time) {
+			if t.Add(e.duration).After(start) {
+				meetings = append(meetings, Meeting{Summary: e.summary, Start: t,
+					End: t.Add(e.duration)})
+			}
+		})
+	}
+
+	sort.Slice(meetings, func(a, b int) bool {
+		return meetings[a].Start.Before(meetings[b].Start)
+	})
+
+	return meetings
+}
+
+// Next returns the first meeting taking place at some point between start
+// and end, if there's one.
+func (c *Calendar) Next(start, end time.Time) (Meeting, bool) {
+	meetings := c.Meetings(start, end)
+	if len(meetings) == 0 {
+		return Meeting{}, false
+	}
+
+	return meetings[0], true
+}
+
+// occurrences calls fn with the start of each occurrence of e before end.
+func (e event) occurrences(end time.Time, fn func(time.Time)) {
+	if e.rule == nil {
+		if e.start.Before(end) {
+			fn(e.start)
+		}
+		return
+	}
+
+	e.rule.each(e.start, end, func(t time.Time) {
+		for _, ex := range e.exdates {
+			if ex.Equal(t) {
+				return
+			}
+		}
+		fn(t)
+	})
+}
+
+// unfold returns the content lines of r, joining the folded ones.
+func unfold(r io.Reader) ([]string, error) {
+	lines := []string{}
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for s.Scan() {
+		l := strings.TrimRight(s.Text(), "\r")
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) &&
+			len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	return lines, s.Err()
+}
+
+// splitLine splits a content line like DTSTART;TZID=Europe/Paris:2026...
+// into its name, parameters and value.
+func splitLine(l string) (string, map[string]string, string) {
+	head, value, _ := strings.Cut(l, ":")
+	// Quoted parameter values may hold colons
+	for strings.Count(head, `"`)%2 == 1 {
+		rest, more, ok := strings.Cut(value, ":")
+		if !ok {
+			break
+		}
+		head, value = head+":"+rest, more
+	}
+
+	parts := strings.Split(head, ";")
+	params := map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = strings.Trim(v, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseTime reads a DATE or DATE-TIME value, and reports whether it's a
+// date only.
+func parseTime(params map[string]string, value string,
+	loc *time.Location) (time.Time, bool, error) {
+
+	if params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		t, err := time.ParseInLocation("20060102", value, loc)
+		return t, true, err
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(utcLayout, value)
+		return t, false, err
+	}
+
+	if tzid := params["TZID"]; tzid != "" {
+		// Zones with names Go doesn't know, such as Windows ones, are read
+		// as floating times
+		if l, err := time.LoadLocation(tzid); err == nil {
+			loc = l
+		}
+	}
+
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	return t, false, err
+}
+
+// parseDuration reads a DURATION value like PT30M or P1DT2H.
+func parseDuration(value string) (time.Duration, error) {
+	s, neg := strings.CutPrefix(strings.TrimPrefix(value, "+"), "-")
+	s, ok := strings.CutPrefix(s, "P")
+	if !ok || s == "" {
+		return 0, fmt.Errorf("invalid duration %q", value)
+	}
+
+	units := map[byte]time.Duration{
+		'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour,
+		'M': time.Minute, 'S': time.Second,
+	}
+
+	var d time.Duration
+	for s != "" {
+		s = strings.TrimPrefix(s, "T")
+		k := strings.IndexAny(s, "WDHMS")
+		if k <= 0 {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+
+		n, err := strconv.Atoi(s[:k])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		d += time.Duration(n) * units[s[k]]
+		s = s[k+1:]
+	}
+
+	if neg {
+		d = -d
+	}
+
+	return d, nil
+}
+
+func unescape(s string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n",
+		`\N`, "\n").Replace(s)
+}
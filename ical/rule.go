@@ -0,0 +1,131 @@
+package ical
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rule is the subset of RRULE used by meetings: daily and weekly repeats,
+// on some days of the week, a number of times or until a date.
+type rule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    map[time.Weekday]bool
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday,
+	"WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// maxOccurrences bounds the expansion of rules without an end.
+const maxOccurrences = 100000
+
+func parseRule(value string, loc *time.Location) (*rule, error) {
+	r := &rule{interval: 1}
+
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+
+		switch strings.ToUpper(k) {
+		case "FREQ":
+			r.freq = strings.ToUpper(v)
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+			if r.interval < 1 {
+				err = fmt.Errorf("invalid interval %q", v)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			r.until, _, err = parseTime(nil, v, loc)
+			if err == nil && len(v) == len("20060102") {
+				r.until = r.until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			r.byDay = map[time.Weekday]bool{}
+			for _, d := range strings.Split(v, ",") {
+				// Positions like 1MO only apply to monthly rules
+				wd, ok := weekdays[strings.ToUpper(strings.TrimLeft(d,
+					"+-0123456789"))]
+				if !ok {
+					return nil, fmt.Errorf("invalid day %q", d)
+				}
+				r.byDay[wd] = true
+			}
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch r.freq {
+	case "DAILY", "WEEKLY":
+	case "MONTHLY", "YEARLY":
+		// Monthly and yearly meetings aren't repeated, only their first
+		// occurrence is kept
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown frequency %q", r.freq)
+	}
+
+	return r, nil
+}
+
+// each calls fn with each occurrence of the rule that starts at start,
+// up to end. The wall clock time is kept across daylight saving changes.
+func (r *rule) each(start, end time.Time, fn func(time.Time)) {
+	n := 0
+	emit := func(t time.Time) bool {
+		if t.Before(start) {
+			return true
+		}
+		if !t.Before(end) || (!r.until.IsZero() && t.After(r.until)) ||
+			(r.count > 0 && n >= r.count) || n >= maxOccurrences {
+			return false
+		}
+
+		n++
+		fn(t)
+		return true
+	}
+
+	if r.freq == "DAILY" {
+		for k := 0; ; k += r.interval {
+			t := start.AddDate(0, 0, k)
+			if len(r.byDay) > 0 && !r.byDay[t.Weekday()] {
+				if t.After(end) {
+					return
+				}
+				continue
+			}
+			if !emit(t) {
+				return
+			}
+		}
+	}
+
+	days := r.byDay
+	if len(days) == 0 {
+		days = map[time.Weekday]bool{start.Weekday(): true}
+	}
+
+	// Weeks start on Monday
+	monday := start.AddDate(0, 0, -(int(start.Weekday())+6)%7)
+	for w := 0; ; w += r.interval {
+		week := monday.AddDate(0, 0, 7*w)
+		for d := 0; d < 7; d++ {
+			t := week.AddDate(0, 0, d)
+			if days[t.Weekday()] && !emit(t) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,172 @@
+// Package metrics serves the state of the timer and the totals of the
+// history in the Prometheus text format, so focus time can be graphed
+// next to other dashboards.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/board"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// ContentType is the media type of the Prometheus text format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// states are the interval states reported, in order.
+var states = []int{
+	pomodoro.StateNotStarted,
+	pomodoro.StateRunning,
+	pomodoro.StatePaused,
+	pomodoro.StateDone,
+	pomodoro.StateCancelled,
+}
+
+// categories are the interval categories reported, in order.
+var categories = []string{
+	pomodoro.CategoryPomodoro,
+	pomodoro.CategoryShortBreak,
+	pomodoro.CategoryLongBreak,
+}
+
+// Write writes the metrics of the intervals of config to w. The state
+// metrics are left out until there's an interval.
+func Write(ctx context.Context, w io.Writer,
+	config *pomodoro.IntervalConfig) error {
+
+	i, err := pomodoro.Current(ctx, config)
+	if err != nil && !errors.Is(err, pomodoro.ErrNoIntervals) {
+		return err
+	}
+	hasCurrent := err == nil
+
+	t, err := pomodoro.AllTotals(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	m := &writer{w: w}
+
+	if hasCurrent {
+		var samples []sample
+		for _, s := range states {
+			v := 0.0
+			if s == i.State {
+				v = 1
+			}
+			samples = append(samples, sample{[]string{"category", i.Category,
+				"state", pomodoro.StateName(s)}, v})
+		}
+		m.metric("pomanalyzer_interval_state", "gauge",
+			"State of the current interval, 1 for the state it's in.",
+			samples...)
+
+		m.metric("pomanalyzer_interval_remaining_seconds", "gauge",
+			"Time left in the current interval.",
+			sample{[]string{"category", i.Category},
+				i.Remaining().Seconds()})
+	}
+
+	// The totals are gauges, as editing or deleting intervals lowers them
+	m.metric("pomanalyzer_pomodoros_completed", "gauge",
+		"Pomodoros finished in the history.", sample{nil, float64(t.Done)})
+	m.metric("pomanalyzer_pomodoros_cancelled", "gauge",
+		"Pomodoros given up before the end in the history.",
+		sample{nil, float64(t.Cancelled)})
+
+	var samples []sample
+	for _, c := range categories {
+		samples = append(samples, sample{[]string{"category", c},
+			t.Time[c].Seconds()})
+	}
+	m.metric("pomanalyzer_focus_seconds", "gauge",
+		"Time spent in intervals of each category in the history.",
+		samples...)
+
+	return m.err
+}
+
+// Handler returns a handler serving the metrics of config.
+func Handler(config *pomodoro.IntervalConfig) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var buf bytes.Buffer
+		if err := Write(r.Context(), &buf, config); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", ContentType)
+		w.Write(buf.Bytes())
+	})
+}
+
+// Listen serves the metrics of config at /metrics on addr, such as
+// "localhost:9191", and the status of the user for team boards at
+// /status, until the returned server is closed.
+func Listen(addr string, config *pomodoro.IntervalConfig) (*http.Server,
+	error) {
+
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("Serving metrics: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler(config))
+	mux.Handle(board.Path, board.Handler(config))
+
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go srv.Serve(l)
+
+	return srv, nil
+}
+
+// sample is a value of a metric, with its labels as name and value
+// pairs.
+type sample struct {
+	labels []string
+	value  float64
+}
+
+// writer writes metrics in the text format, keeping the first error.
+type writer struct {
+	w   io.Writer
+	err error
+}
+
+func (m *writer) metric(name, kind, help string, samples ...sample) {
+	if m.err != nil {
+		return
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	for _, s := range samples {
+		b.WriteString(name)
+		if len(s.labels) > 0 {
+			b.WriteString("{")
+			for k := 0; k+1 < len(s.labels); k += 2 {
+				if k > 0 {
+					b.WriteString(",")
+				}
+				fmt.Fprintf(&b, "%s=\"%s\"", s.labels[k],
+					labelEscaper.Replace(s.labels[k+1]))
+			}
+			b.WriteString("}")
+		}
+		fmt.Fprintf(&b, " %s\n", strconv.FormatFloat(s.value, 'f', -1, 64))
+	}
+
+	_, m.err = io.WriteString(m.w, b.String())
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
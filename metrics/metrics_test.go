@@ -0,0 +1,128 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/metrics"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+// scrape returns the metrics served by ts.
+func scrape(t *testing.T, ts *httptest.Server) string {
+	t.Helper()
+
+	res, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, res.StatusCode)
+	}
+	if ct := res.Header.Get("Content-Type"); ct != metrics.ContentType {
+		t.Errorf("Expected content type %q, got %q", metrics.ContentType, ct)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	repo, err := repository.Open("memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+	ts := httptest.NewServer(metrics.Handler(config))
+	defer ts.Close()
+
+	// Without intervals there's no current state
+	body := scrape(t, ts)
+	if strings.Contains(body, "pomanalyzer_interval_state") {
+		t.Errorf("Expected no interval state, got:\n%s", body)
+	}
+	if !strings.Contains(body, "pomanalyzer_pomodoros_completed 0\n") {
+		t.Errorf("Expected no pomodoros completed, got:\n%s", body)
+	}
+
+	start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+	intervals := []pomodoro.Interval{
+		{Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+			ActualDuration: 25 * time.Minute},
+		{Category: pomodoro.CategoryShortBreak, State: pomodoro.StateDone,
+			ActualDuration: 5 * time.Minute},
+		{Category: pomodoro.CategoryPomodoro, State: pomodoro.StateCancelled,
+			ActualDuration: 10 * time.Minute},
+		{Category: pomodoro.CategoryPomodoro, State: pomodoro.StateRunning,
+			ActualDuration: 10 * time.Minute},
+	}
+	for k, i := range intervals {
+		i.StartTime = start.Add(time.Duration(k) * 30 * time.Minute)
+		i.PlannedDuration = 25 * time.Minute
+		if i.Category == pomodoro.CategoryShortBreak {
+			i.PlannedDuration = 5 * time.Minute
+		}
+		if _, err := repo.Create(ctx, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	body = scrape(t, ts)
+	for _, exp := range []string{
+		"# TYPE pomanalyzer_interval_state gauge\n",
+		`pomanalyzer_interval_state{category="Pomodoro",state="Running"} 1` + "\n",
+		`pomanalyzer_interval_state{category="Pomodoro",state="Paused"} 0` + "\n",
+		`pomanalyzer_interval_remaining_seconds{category="Pomodoro"} 900` + "\n",
+		"# TYPE pomanalyzer_pomodoros_completed gauge\n",
+		"pomanalyzer_pomodoros_completed 1\n",
+		"pomanalyzer_pomodoros_cancelled 1\n",
+		`pomanalyzer_focus_seconds{category="Pomodoro"} 2700` + "\n",
+		`pomanalyzer_focus_seconds{category="ShortBreak"} 300` + "\n",
+		`pomanalyzer_focus_seconds{category="LongBreak"} 0` + "\n",
+	} {
+		if !strings.Contains(body, exp) {
+			t.Errorf("Expected %q in:\n%s", exp, body)
+		}
+	}
+
+	// Totals follow the history, so deleting an interval lowers them
+	if err := repo.Delete(ctx, 3); err != nil {
+		t.Fatal(err)
+	}
+	body = scrape(t, ts)
+	if !strings.Contains(body, "pomanalyzer_pomodoros_cancelled 0\n") {
+		t.Errorf("Expected no pomodoros cancelled, got:\n%s", body)
+	}
+}
+
+func TestListen(t *testing.T) {
+	repo, err := repository.Open("memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+	srv, err := metrics.Listen("127.0.0.1:0", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	if _, err := metrics.Listen("127.0.0.1:-1", config); err == nil {
+		t.Error("Expected an error for an invalid address")
+	}
+}
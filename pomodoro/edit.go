@@ -0,0 +1,215 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidInterval = errors.New("Invalid interval")
+	ErrIntervalRunning = errors.New("Interval is running")
+)
+
+// Change records an edit to a stored interval. Together they form the
+// audit trail of the history.
+type Change struct {
+	ID         int64
+	IntervalID int64
+	Time       time.Time
+	Field      string
+	OldValue   string
+	NewValue   string
+	// User is who made the change.
+	User string
+}
+
+// FieldDeleted is the Change field recorded when an interval is deleted.
+const FieldDeleted = "deleted"
+
+// Validate checks that the interval holds values that make sense. Unless
+// overtime is allowed, the actual duration can't exceed the planned one.
+// Only the timer runs intervals, so they can't be set running.
+func (i Interval) Validate(allowOvertime bool) error {
+	switch i.Category {
+	case CategoryPomodoro, CategoryShortBreak, CategoryLongBreak:
+	default:
+		return fmt.Errorf("%w: unknown category %q", ErrInvalidInterval,
+			i.Category)
+	}
+
+	if i.State < StateNotStarted || i.State > StateCancelled {
+		return fmt.Errorf("%w: %d", ErrInvalidState, i.State)
+	}
+
+	if i.State == StateRunning {
+		return fmt.Errorf("%w: only the timer runs intervals, start it instead",
+			ErrInvalidState)
+	}
+
+	if i.PlannedDuration <= 0 {
+		return fmt.Errorf("%w: planned duration must be positive",
+			ErrInvalidInterval)
+	}
+
+	if i.ActualDuration < 0 {
+		return fmt.Errorf("%w: actual duration can't be negative",
+			ErrInvalidInterval)
+	}
+
+	if !allowOvertime && i.ActualDuration > i.PlannedDuration {
+		return fmt.Errorf("%w: actual duration %s exceeds planned %s",
+			ErrInvalidInterval, i.ActualDuration, i.PlannedDuration)
+	}
+
+	if i.InternalInterruptions < 0 || i.ExternalInterruptions < 0 {
+		return fmt.Errorf("%w: interruptions can't be negative",
+			ErrInvalidInterval)
+	}
+
+	if p, err := ParseProject(i.Project); err != nil || p != i.Project {
+		return fmt.Errorf("%w: invalid project %q", ErrInvalidInterval,
+			i.Project)
+	}
+
+	if tags, err := ParseTags(i.Tags); err != nil ||
+		strings.Join(tags, " ") != strings.Join(i.Tags, " ") {
+		return fmt.Errorf("%w: invalid tags %q", ErrInvalidInterval,
+			strings.Join(i.Tags, " "))
+	}
+
+	if i.ActualDuration > 0 && i.StartTime.IsZero() {
+		return fmt.Errorf("%w: an interval with actual duration needs a start time",
+			ErrInvalidInterval)
+	}
+
+	return nil
+}
+
+// changes lists the fields that differ between two versions of an
+// interval.
+func changes(before, after Interval, now time.Time) []Change {
+	list := []Change{}
+
+	add := func(field, o, n string) {
+		if o != n {
+			list = append(list, Change{
+				IntervalID: before.ID,
+				Time:       now,
+				Field:      field,
+				OldValue:   o,
+				NewValue:   n,
+			})
+		}
+	}
+
+	add("start_time", formatTime(before.StartTime), formatTime(after.StartTime))
+	add("planned_duration", before.PlannedDuration.String(),
+		after.PlannedDuration.String())
+	add("actual_duration", before.ActualDuration.String(),
+		after.ActualDuration.String())
+	add("category", before.Category, after.Category)
+	add("state", StateName(before.State), StateName(after.State))
+	add("task", before.Task, after.Task)
+	add("task_id", fmt.Sprint(before.TaskID), fmt.Sprint(after.TaskID))
+	add("project", before.Project, after.Project)
+	add("tags", strings.Join(before.Tags, " "), strings.Join(after.Tags, " "))
+	add("manual", fmt.Sprint(before.Manual), fmt.Sprint(after.Manual))
+	add("profile", before.Profile, after.Profile)
+	add("note", before.Note, after.Note)
+	add("internal_interruptions", fmt.Sprint(before.InternalInterruptions),
+		fmt.Sprint(after.InternalInterruptions))
+	add("external_interruptions", fmt.Sprint(before.ExternalInterruptions),
+		fmt.Sprint(after.ExternalInterruptions))
+
+	return list
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(time.RFC3339)
+}
+
+// Edit applies fn to the stored interval id and saves the result after
+// validating it. Every changed field is recorded in the audit trail.
+// Running intervals can't be edited.
+func Edit(ctx context.Context, config *IntervalConfig, id int64,
+	fn func(*Interval)) (Interval, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	old, err := config.store().ByID(ctx, id)
+	if err != nil {
+		return old, err
+	}
+
+	if old.State == StateRunning {
+		return old, fmt.Errorf("%w: pause it before editing", ErrIntervalRunning)
+	}
+
+	i := old
+	fn(&i)
+	i.ID = old.ID
+
+	if err := i.Validate(config.AllowOvertime); err != nil {
+		return old, err
+	}
+
+	list := changes(old, i, time.Now())
+	if len(list) == 0 {
+		return i, nil
+	}
+
+	if err := config.store().Update(ctx, i, list...); err != nil {
+		return old, err
+	}
+
+	return i, nil
+}
+
+// Delete removes the stored interval id, recording it in the audit trail.
+// Running intervals can't be deleted.
+func Delete(ctx context.Context, config *IntervalConfig, id int64) error {
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	i, err := config.store().ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if i.State == StateRunning {
+		return fmt.Errorf("%w: pause it before deleting", ErrIntervalRunning)
+	}
+
+	return config.store().Delete(ctx, id, Change{
+		IntervalID: id,
+		Time:       time.Now(),
+		Field:      FieldDeleted,
+		OldValue: fmt.Sprintf("%s %s %s/%s %s", i.Category,
+			formatTime(i.StartTime), i.ActualDuration, i.PlannedDuration,
+			StateName(i.State)),
+	})
+}
+
+// Changes returns the audit trail of interval id, or of every interval
+// if id is 0, oldest first.
+func Changes(ctx context.Context, config *IntervalConfig,
+	id int64) ([]Change, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return config.store().Changes(ctx, id, config.User)
+}
+
+// Get returns the stored interval id.
+func Get(ctx context.Context, config *IntervalConfig, id int64) (Interval, error) {
+	return config.byID(ctx, id)
+}
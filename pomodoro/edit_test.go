@@ -0,0 +1,185 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestValidate(t *testing.T) {
+  start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  valid := pomodoro.Interval{
+    StartTime:       start,
+    PlannedDuration: 25 * time.Minute,
+    ActualDuration:  20 * time.Minute,
+    Category:        pomodoro.CategoryPomodoro,
+    State:           pomodoro.StateDone,
+  }
+
+  testCases := []struct {
+    name     string
+    edit     func(*pomodoro.Interval)
+    overtime bool
+    expErr   error
+  }{
+    {name: "Valid", edit: func(*pomodoro.Interval) {}},
+    {name: "Category", expErr: pomodoro.ErrInvalidInterval,
+      edit: func(i *pomodoro.Interval) { i.Category = "Nap" }},
+    {name: "State", expErr: pomodoro.ErrInvalidState,
+      edit: func(i *pomodoro.Interval) { i.State = 9 }},
+    {name: "Running", expErr: pomodoro.ErrInvalidState,
+      edit: func(i *pomodoro.Interval) { i.State = pomodoro.StateRunning }},
+    {name: "NegativeActual", expErr: pomodoro.ErrInvalidInterval,
+      edit: func(i *pomodoro.Interval) { i.ActualDuration = -time.Minute }},
+    {name: "ZeroPlanned", expErr: pomodoro.ErrInvalidInterval,
+      edit: func(i *pomodoro.Interval) { i.PlannedDuration = 0 }},
+    {name: "Overtime", expErr: pomodoro.ErrInvalidInterval,
+      edit: func(i *pomodoro.Interval) { i.ActualDuration = time.Hour }},
+    {name: "OvertimeAllowed", overtime: true,
+      edit: func(i *pomodoro.Interval) { i.ActualDuration = time.Hour }},
+    {name: "NoStartTime", expErr: pomodoro.ErrInvalidInterval,
+      edit: func(i *pomodoro.Interval) { i.StartTime = time.Time{} }},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      i := valid
+      tc.edit(&i)
+
+      err := i.Validate(tc.overtime)
+      if tc.expErr == nil && err != nil {
+        t.Fatalf("Expected no error, got %q", err)
+      }
+      if !errors.Is(err, tc.expErr) {
+        t.Errorf("Expected error %q, got %q", tc.expErr, err)
+      }
+    })
+  }
+}
+
+func TestEdit(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  id, err := repo.Create(ctx, pomodoro.Interval{
+    StartTime:       time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC),
+    PlannedDuration: 5 * time.Minute,
+    ActualDuration:  5 * time.Minute,
+    Category:        pomodoro.CategoryShortBreak,
+    State:           pomodoro.StateRunning,
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  _, err = pomodoro.Edit(ctx, config, id, func(i *pomodoro.Interval) {})
+  if !errors.Is(err, pomodoro.ErrIntervalRunning) {
+    t.Fatalf("Expected error %q, got %q", pomodoro.ErrIntervalRunning, err)
+  }
+
+  i, err := repo.ByID(ctx, id)
+  if err != nil {
+    t.Fatal(err)
+  }
+  i.State = pomodoro.StatePaused
+  if err := repo.Update(ctx, i); err != nil {
+    t.Fatal(err)
+  }
+
+  // A break left running over lunch becomes a cancelled one
+  _, err = pomodoro.Edit(ctx, config, id, func(i *pomodoro.Interval) {
+    i.ActualDuration = 50 * time.Minute
+  })
+  if !errors.Is(err, pomodoro.ErrInvalidInterval) {
+    t.Fatalf("Expected error %q, got %q", pomodoro.ErrInvalidInterval, err)
+  }
+
+  i, err = pomodoro.Edit(ctx, config, id, func(i *pomodoro.Interval) {
+    i.ActualDuration = 3 * time.Minute
+    i.State = pomodoro.StateCancelled
+    i.Task = "lunch"
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  stored, err := repo.ByID(ctx, id)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if !reflect.DeepEqual(stored, i) {
+    t.Errorf("Expected stored interval %v, got %v instead\n", i, stored)
+  }
+
+  changes, err := pomodoro.Changes(ctx, config, id)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expFields := []string{"actual_duration", "state", "task"}
+  if len(changes) != len(expFields) {
+    t.Fatalf("Expected %d changes, got %v instead\n", len(expFields), changes)
+  }
+  for k, c := range changes {
+    if c.Field != expFields[k] || c.IntervalID != id {
+      t.Errorf("Expected change of %q, got %v instead\n", expFields[k], c)
+    }
+  }
+  if changes[0].OldValue != "5m0s" || changes[0].NewValue != "3m0s" {
+    t.Errorf("Expected change from 5m0s to 3m0s, got %v instead\n",
+      changes[0])
+  }
+}
+
+func TestDelete(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  for k := 0; k < 2; k++ {
+    if _, err := repo.Create(ctx, pomodoro.Interval{
+      PlannedDuration: 25 * time.Minute,
+      Category:        pomodoro.CategoryPomodoro,
+    }); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  if err := pomodoro.Delete(ctx, config, 1); err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := repo.ByID(ctx, 1); !errors.Is(err, pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidID, err)
+  }
+
+  if err := pomodoro.Delete(ctx, config, 1); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidID, err)
+  }
+
+  list, err := repo.List(ctx, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 1 || list[0].ID != 2 {
+    t.Errorf("Expected only interval 2, got %v instead\n", list)
+  }
+
+  changes, err := pomodoro.Changes(ctx, config, 0)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(changes) != 1 || changes[0].Field != pomodoro.FieldDeleted {
+    t.Errorf("Expected deletion in audit trail, got %v instead\n", changes)
+  }
+}
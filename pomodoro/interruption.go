@@ -0,0 +1,55 @@
+package pomodoro
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Interruption kinds. The Pomodoro technique marks internal interruptions
+// with an apostrophe and external ones with a dash.
+const (
+	InterruptionInternal = "internal"
+	InterruptionExternal = "external"
+)
+
+// Interrupt records an interruption of kind in the running or paused
+// pomodoro and returns it.
+func Interrupt(ctx context.Context, config *IntervalConfig,
+	kind string) (Interval, error) {
+
+	if kind != InterruptionInternal && kind != InterruptionExternal {
+		return Interval{}, fmt.Errorf("%w: unknown interruption %q",
+			ErrInvalidInterval, kind)
+	}
+
+	rctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	i, err := config.store().Last(rctx, config.User)
+	if err != nil && err != ErrNoIntervals {
+		return i, err
+	}
+
+	if err == ErrNoIntervals || i.Category != CategoryPomodoro ||
+		(i.State != StateRunning && i.State != StatePaused) {
+		return i, fmt.Errorf("%w: interruptions are logged during a pomodoro",
+			ErrIntervalNotRunning)
+	}
+
+	return config.modify(ctx, i.ID, func(i *Interval) bool {
+		if kind == InterruptionInternal {
+			i.InternalInterruptions++
+		} else {
+			i.ExternalInterruptions++
+		}
+		return true
+	})
+}
+
+// Marks returns the interruptions of i as on a tracking sheet, such as
+// "''-" for two internal and one external interruption.
+func (i Interval) Marks() string {
+	return strings.Repeat("'", i.InternalInterruptions) +
+		strings.Repeat("-", i.ExternalInterruptions)
+}
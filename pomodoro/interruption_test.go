@@ -0,0 +1,74 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestInterrupt(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 2*time.Second, time.Second, time.Second)
+  config.Notify = false
+
+  _, err := pomodoro.Interrupt(ctx, config, pomodoro.InterruptionInternal)
+  if !errors.Is(err, pomodoro.ErrIntervalNotRunning) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrIntervalNotRunning, err)
+  }
+
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  kinds := []string{
+    pomodoro.InterruptionInternal,
+    pomodoro.InterruptionExternal,
+    pomodoro.InterruptionInternal,
+  }
+
+  noop := func(pomodoro.Interval) {}
+  periodic := func(i pomodoro.Interval) {
+    // Interruptions logged while the timer ticks must not be lost
+    for _, kind := range kinds {
+      if _, err := pomodoro.Interrupt(ctx, config, kind); err != nil {
+        t.Error(err)
+      }
+    }
+
+    if err := i.Pause(ctx, config); err != nil {
+      t.Error(err)
+    }
+  }
+
+  if err := i.Start(ctx, config, noop, periodic, noop); err != nil {
+    t.Fatal(err)
+  }
+
+  i, err = repo.ByID(ctx, i.ID)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if i.State != pomodoro.StatePaused {
+    t.Errorf("Expected state %d, got %d.\n", pomodoro.StatePaused, i.State)
+  }
+  if i.InternalInterruptions != 2 || i.ExternalInterruptions != 1 {
+    t.Errorf("Expected 2 internal and 1 external interruptions, got %d and %d",
+      i.InternalInterruptions, i.ExternalInterruptions)
+  }
+  if i.Marks() != "''-" {
+    t.Errorf("Expected marks %q, got %q instead\n", "''-", i.Marks())
+  }
+
+  if _, err := pomodoro.Interrupt(ctx, config, "phone"); !errors.Is(err,
+    pomodoro.ErrInvalidInterval) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidInterval, err)
+  }
+}
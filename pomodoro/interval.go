@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/xasterKies/pomanalyzer/notif"
@@ -32,6 +33,25 @@ type Interval struct {
 	ActualDuration  time.Duration
 	Category        string
 	State           int
+	Task            string
+	// TaskID is the planned task the interval counts toward, 0 for none.
+	TaskID int64
+	// Project is a path such as "work/api/auth", and Tags are sorted
+	// labels such as "backend", both in lower case.
+	Project string
+	Tags    []string
+	// Manual marks intervals entered after the fact rather than timed.
+	Manual bool
+	// Profile is the name of the work mode the interval was timed with.
+	Profile string
+	Note    string
+	// InternalInterruptions and ExternalInterruptions count the times the
+	// user was distracted by their own thoughts or by someone else.
+	InternalInterruptions int
+	ExternalInterruptions int
+	// User is who timed the interval, empty for intervals recorded before
+	// histories were kept per user.
+	User string
 }
 
 var (
@@ -42,20 +62,86 @@ var (
 	ErrInvalidID          = errors.New("Invalid ID")
 )
 
+// Repository stores intervals. Every method takes a context so a slow or
+// locked backend can't block callers past their deadline.
 type Repository interface {
-	Create(i Interval) (int64, error)
-	Update(i Interval) error
-	ByID(id int64) (Interval, error)
-	Last() (Interval, error)
-	Breaks(n int) ([]Interval, error)
-	CategorySummary(day time.Time, filter string) (time.Duration, error)
+	Create(ctx context.Context, i Interval) (int64, error)
+	// Update and Delete record changes, if any, in the audit trail in the
+	// same transaction, so neither is saved without the other.
+	Update(ctx context.Context, i Interval, changes ...Change) error
+	ByID(ctx context.Context, id int64) (Interval, error)
+	// Last and Breaks return the latest interval and the latest n breaks
+	// timed for user, or for every user if user is empty. Intervals
+	// without a user aren't anyone's, and manual ones weren't timed, so
+	// the timer never picks them up again.
+	Last(ctx context.Context, user string) (Interval, error)
+	Breaks(ctx context.Context, user string, n int) ([]Interval, error)
+	List(ctx context.Context, q Query) ([]Interval, error)
+	Delete(ctx context.Context, id int64, changes ...Change) error
+	// Changes returns the audit trail of an interval, or of all intervals
+	// if id is 0, oldest first. Like Tasks, it's limited to the changes of
+	// user and those without a user, unless user is empty.
+	Changes(ctx context.Context, id int64, user string) ([]Change, error)
+	// CategorySummary returns the time spent in intervals selected by q
+	// within its period, ignoring paging.
+	CategorySummary(ctx context.Context, q Query) (time.Duration, error)
+	// Totals sums up every interval of user, and those without a user,
+	// or of every user if user is empty.
+	Totals(ctx context.Context, user string) (Totals, error)
+	// Users returns the users with intervals or tasks, sorted, with the
+	// empty name first if some were recorded without a user.
+	Users(ctx context.Context) ([]string, error)
+
+	CreateTask(ctx context.Context, t Task) (int64, error)
+	UpdateTask(ctx context.Context, t Task) error
+	TaskByID(ctx context.Context, id int64) (Task, error)
+	DeleteTask(ctx context.Context, id int64) error
+	// Tasks returns the tasks of user and those without a user, or every
+	// task if user is empty, ordered by ID.
+	Tasks(ctx context.Context, user string) ([]Task, error)
 }
 
 type IntervalConfig struct {
-	repo               Repository
+	repo Repository
+	// mu guards the fields changed by Set while intervals run.
+	mu *sync.RWMutex
+	// intervalMu serializes the changes to running intervals.
+	intervalMu         *sync.Mutex
 	PomodoroDuration   time.Duration
 	ShortBreakDuration time.Duration
 	LongBreakDuration  time.Duration
+	// CycleLength is the number of pomodoros before a long break.
+	CycleLength int
+	// Notify sends a desktop notification with NotifySeverity when an
+	// interval is done.
+	Notify         bool
+	NotifySeverity notif.Severity
+	// Location is the time zone used to find day boundaries in summaries.
+	Location *time.Location
+	// DayStart shifts the start of each day, so that late sessions still
+	// count toward the previous day.
+	DayStart time.Duration
+	// Timeout bounds each repository call.
+	Timeout time.Duration
+	// Task is recorded on new pomodoros.
+	Task string
+	// TaskID is the planned task new pomodoros count toward.
+	TaskID int64
+	// Project and Tags are recorded on new pomodoros.
+	Project string
+	Tags    []string
+	// Filter narrows the summaries to a project or a tag.
+	Filter Filter
+	// Profile is recorded on new intervals.
+	Profile string
+	// AllowOvertime lets edits set an actual duration longer than the
+	// planned one.
+	AllowOvertime bool
+	// ExcludeManual leaves manually entered intervals out of summaries.
+	ExcludeManual bool
+	// User is recorded on new intervals and tasks, and limits every query
+	// to their history. If empty, the history of every user is used.
+	User string
 }
 
 func NewConfig(repo Repository, pomodoro, shortBreak,
@@ -63,9 +149,16 @@ func NewConfig(repo Repository, pomodoro, shortBreak,
 
 	c := &IntervalConfig{
 		repo:               repo,
+		mu:                 &sync.RWMutex{},
+		intervalMu:         &sync.Mutex{},
 		PomodoroDuration:   25 * time.Minute,
 		ShortBreakDuration: 5 * time.Minute,
 		LongBreakDuration:  15 * time.Minute,
+		CycleLength:        4,
+		Notify:             true,
+		NotifySeverity:     notif.SeverityNormal,
+		Location:           time.Local,
+		Timeout:            5 * time.Second,
 	}
 
 	if pomodoro > 0 {
@@ -83,11 +176,115 @@ func NewConfig(repo Repository, pomodoro, shortBreak,
 	return c
 }
 
-func GetInterval(config *IntervalConfig) (Interval, error) {
+// Set applies fn to the config while holding its lock, so durations,
+// cycle length, notifications and profile can change while intervals run.
+// Running intervals keep their planned duration.
+func (c *IntervalConfig) Set(fn func(*IntervalConfig)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fn(c)
+}
+
+// Duration returns the planned duration of new intervals of category.
+func (c *IntervalConfig) Duration(category string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	switch category {
+	case CategoryPomodoro:
+		return c.PomodoroDuration
+	case CategoryShortBreak:
+		return c.ShortBreakDuration
+	case CategoryLongBreak:
+		return c.LongBreakDuration
+	}
+
+	return 0
+}
+
+// DayBounds returns the start and end of the day t belongs to, using the
+// configured location and day start.
+func (c *IntervalConfig) DayBounds(t time.Time) (time.Time, time.Time) {
+	loc := c.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	// The day starts at the same wall clock time on days when daylight
+	// saving time begins or ends
+	hour := int(c.DayStart / time.Hour)
+	minute := int(c.DayStart % time.Hour / time.Minute)
+
+	t = t.In(loc)
+	y, m, d := t.Date()
+	if t.Before(time.Date(y, m, d, hour, minute, 0, 0, loc)) {
+		d--
+	}
+
+	start := time.Date(y, m, d, hour, minute, 0, 0, loc)
+	end := time.Date(y, m, d+1, hour, minute, 0, 0, loc)
+
+	return start, end
+}
+
+// Overlap returns the part of the interval's actual duration that falls
+// between start and end. Intervals are assumed to run without pauses from
+// their StartTime, so one crossing a boundary is split proportionally.
+func (i Interval) Overlap(start, end time.Time) time.Duration {
+	if i.ActualDuration <= 0 {
+		return 0
+	}
+
+	from := i.StartTime
+	to := i.StartTime.Add(i.ActualDuration)
+
+	if from.Before(start) {
+		from = start
+	}
+	if to.After(end) {
+		to = end
+	}
+
+	if !to.After(from) {
+		return 0
+	}
+
+	return to.Sub(from)
+}
+
+// Remaining returns the time left in the interval, 0 once it has ended.
+func (i Interval) Remaining() time.Duration {
+	if i.State == StateDone || i.State == StateCancelled {
+		return 0
+	}
+
+	if d := i.PlannedDuration - i.ActualDuration; d > 0 {
+		return d
+	}
+
+	return 0
+}
+
+// withTimeout returns a context for a single repository call.
+func (c *IntervalConfig) withTimeout(
+	ctx context.Context) (context.Context, context.CancelFunc) {
+
+	if c.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, c.Timeout)
+}
+
+func GetInterval(ctx context.Context, config *IntervalConfig) (Interval, error) {
 	i := Interval{}
 	var err error
 
-	i, err = config.repo.Last()
+	rctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	i, err = config.store().Last(rctx, config.User)
 
 	if err != nil && err != ErrNoIntervals {
 		return i, err
@@ -97,34 +294,91 @@ func GetInterval(config *IntervalConfig) (Interval, error) {
 		return i, nil
 	}
 
-	return newInterval(config)
+	return newInterval(rctx, config)
 }
 
-func newInterval(config *IntervalConfig) (Interval, error) {
+func newInterval(ctx context.Context, config *IntervalConfig) (Interval, error) {
 	i := Interval{}
-	category, err := nextCategory(config.repo)
+
+	config.mu.RLock()
+	cycle, profile, task := config.CycleLength, config.Profile, config.Task
+	taskID, project, tags := config.TaskID, config.Project, config.Tags
+	config.mu.RUnlock()
+
+	category, err := nextCategory(ctx, config.store(), config.User, cycle)
 	if err != nil {
 		return i, err
 	}
 
 	i.Category = category
-
-	switch category {
-	case CategoryPomodoro:
-		i.PlannedDuration = config.PomodoroDuration
-	case CategoryShortBreak:
-		i.PlannedDuration = config.ShortBreakDuration
-	case CategoryLongBreak:
-		i.PlannedDuration = config.LongBreakDuration
+	i.Profile = profile
+	i.User = config.User
+	i.PlannedDuration = config.Duration(category)
+	if category == CategoryPomodoro {
+		i.Task = task
+		i.TaskID = taskID
+		i.Project = project
+		i.Tags = tags
 	}
 
-	if i.ID, err = config.repo.Create(i); err != nil {
+	if i.ID, err = config.store().Create(ctx, i); err != nil {
 		return i, err
 	}
 
 	return i, nil
 }
 
+// Reconfigure makes the next interval follow changes to config, such as
+// a new profile, by dropping the interval waiting to start. It fails with
+// ErrIntervalRunning while an interval is running or paused.
+func Reconfigure(ctx context.Context, config *IntervalConfig) error {
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	i, err := config.store().Last(ctx, config.User)
+	if errors.Is(err, ErrNoIntervals) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch i.State {
+	case StateRunning, StatePaused:
+		return fmt.Errorf("%w: finish or cancel it first", ErrIntervalRunning)
+	case StateNotStarted:
+		return config.store().Delete(ctx, i.ID)
+	}
+
+	return nil
+}
+
+// Shorten plans i, which hasn't started yet, to last d instead, such as
+// to end before a meeting.
+func (i Interval) Shorten(ctx context.Context, config *IntervalConfig,
+	d time.Duration) (Interval, error) {
+
+	if d <= 0 {
+		return i, fmt.Errorf("%w: planned duration %s", ErrInvalidInterval, d)
+	}
+
+	var err error
+	i, mErr := config.modify(ctx, i.ID, func(i *Interval) bool {
+		if i.State != StateNotStarted {
+			err = fmt.Errorf("%w: Cannot shorten", ErrIntervalRunning)
+			return false
+		}
+
+		i.PlannedDuration = d
+		return true
+	})
+	if mErr != nil {
+		return i, mErr
+	}
+
+	return i, err
+}
+
 type Callback func(Interval)
 
 func (i Interval) Start(ctx context.Context, config *IntervalConfig,
@@ -138,7 +392,7 @@ func (i Interval) Start(ctx context.Context, config *IntervalConfig,
 		fallthrough
 	case StatePaused:
 		i.State = StateRunning
-		if err := config.repo.Update(i); err != nil {
+		if err := config.update(ctx, i); err != nil {
 			return err
 		}
 		return tick(ctx, i.ID, config, start, periodic, end)
@@ -149,14 +403,58 @@ func (i Interval) Start(ctx context.Context, config *IntervalConfig,
 	}
 }
 
-func (i Interval) Pause(config *IntervalConfig) error {
+func (i Interval) Pause(ctx context.Context, config *IntervalConfig) error {
 	if i.State != StateRunning {
 		return ErrIntervalNotRunning
 	}
 
-	i.State = StatePaused
+	_, err := config.modify(ctx, i.ID, func(i *Interval) bool {
+		if i.State != StateRunning {
+			return false
+		}
+
+		i.State = StatePaused
+		return true
+	})
+
+	return err
+}
+
+// byID and update wrap the repository calls made while an interval runs
+// with the configured timeout.
+func (c *IntervalConfig) byID(ctx context.Context, id int64) (Interval, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
 
-	return config.repo.Update(i)
+	return c.store().ByID(ctx, id)
+}
+
+func (c *IntervalConfig) update(ctx context.Context, i Interval) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	return c.store().Update(ctx, i)
+}
+
+// modify applies fn to the stored interval id and saves it if fn reports
+// a change. Running intervals are changed by the timer and the user at the
+// same time, so the read and the write happen under a lock.
+func (c *IntervalConfig) modify(ctx context.Context, id int64,
+	fn func(*Interval) bool) (Interval, error) {
+
+	c.intervalMu.Lock()
+	defer c.intervalMu.Unlock()
+
+	i, err := c.byID(ctx, id)
+	if err != nil {
+		return i, err
+	}
+
+	if !fn(&i) {
+		return i, nil
+	}
+
+	return i, c.update(ctx, i)
 }
 
 func tick(ctx context.Context, id int64, config *IntervalConfig,
@@ -164,8 +462,12 @@ func tick(ctx context.Context, id int64, config *IntervalConfig,
 
 	ticker := time.NewTicker(time.Second)
 
+	// Once ctx is cancelled the interval still has to be saved, so those
+	// last calls only keep the configured timeout.
+	final := context.WithoutCancel(ctx)
+
 	defer func() error {
-		i, err := config.repo.ByID(id)
+		i, err := config.byID(final, id)
 
 		if err != nil {
 			return err
@@ -173,14 +475,18 @@ func tick(ctx context.Context, id int64, config *IntervalConfig,
 
 		var message string
 
-		if i.State == StateDone {
+		config.mu.RLock()
+		notify, severity := config.Notify, config.NotifySeverity
+		config.mu.RUnlock()
+
+		if i.State == StateDone && notify {
 			if i.Category == CategoryPomodoro {
 				message = "Time to take a break. Start break timer."
 			} else {
 				message = "Break is over. Restart pomodoro timer"
 			}
 
-			notification := notif.New("Pomanalyzer", message, notif.SeverityNormal)
+			notification := notif.New("Pomanalyzer", message, severity)
 			notification.Send()
 		}
 
@@ -188,7 +494,7 @@ func tick(ctx context.Context, id int64, config *IntervalConfig,
 		return nil
 	}()
 
-	i, err := config.repo.ByID(id)
+	i, err := config.byID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -200,7 +506,16 @@ func tick(ctx context.Context, id int64, config *IntervalConfig,
 	for {
 		select {
 		case <-ticker.C:
-			i, err := config.repo.ByID(id)
+			// A tick racing with ctx being cancelled mustn't fail before
+			// the interval is saved as cancelled below
+			i, err := config.modify(final, id, func(i *Interval) bool {
+				if i.State == StatePaused {
+					return false
+				}
+
+				i.ActualDuration += time.Second
+				return true
+			})
 			if err != nil {
 				return err
 			}
@@ -208,33 +523,32 @@ func tick(ctx context.Context, id int64, config *IntervalConfig,
 			if i.State == StatePaused {
 				return nil
 			}
-
-			i.ActualDuration += time.Second
-			if err := config.repo.Update(i); err != nil {
-				return err
-			}
 			periodic(i)
 		case <-expire:
-			i, err := config.repo.ByID(id)
+			i, err := config.modify(final, id, func(i *Interval) bool {
+				i.State = StateDone
+				return true
+			})
 			if err != nil {
 				return err
 			}
-			i.State = StateDone
 			end(i)
-			return config.repo.Update(i)
+			return nil
 		case <-ctx.Done():
-			i, err := config.repo.ByID(id)
-			if err != nil {
-				return err
-			}
-			i.State = StateCancelled
-			return config.repo.Update(i)
+			_, err := config.modify(final, id, func(i *Interval) bool {
+				i.State = StateCancelled
+				return true
+			})
+			return err
 		}
 	}
 }
 
-func nextCategory(r Repository) (string, error) {
-	li, err := r.Last()
+// nextCategory returns the category of the interval after the last one.
+// A long break follows every cycle pomodoros.
+func nextCategory(ctx context.Context, r Repository, user string,
+	cycle int) (string, error) {
+	li, err := r.Last(ctx, user)
 	if err != nil && err == ErrNoIntervals {
 		return CategoryPomodoro, nil
 	}
@@ -246,12 +560,16 @@ func nextCategory(r Repository) (string, error) {
 		return CategoryPomodoro, nil
 	}
 
-	lastBreaks, err := r.Breaks(3)
+	if cycle <= 1 {
+		return CategoryLongBreak, nil
+	}
+
+	lastBreaks, err := r.Breaks(ctx, user, cycle-1)
 	if err != nil {
 		return "", err
 	}
 
-	if len(lastBreaks) < 3 {
+	if len(lastBreaks) < cycle-1 {
 		return CategoryShortBreak, nil
 	}
 
@@ -102,7 +102,7 @@ func TestGetInterval(t *testing.T) {
 
     testName := fmt.Sprintf("%s%d", expCategory, i)
     t.Run(testName, func(t *testing.T) {
-      res, err := pomodoro.GetInterval(config)
+      res, err := pomodoro.GetInterval(context.Background(), config)
 
       if err != nil {
         t.Errorf("Expected no error, got %q.\n", err)
@@ -130,7 +130,7 @@ func TestGetInterval(t *testing.T) {
           pomodoro.StateNotStarted, res.State)
       }
 
-      ui, err := repo.ByID(res.ID)
+      ui, err := repo.ByID(context.Background(), res.ID)
       if err != nil {
         t.Errorf("Expected no error. Got %q.\n", err)
       }
@@ -143,6 +143,142 @@ func TestGetInterval(t *testing.T) {
   }
 }
 
+func TestGetIntervalCycle(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  const duration = 1 * time.Millisecond
+  config := pomodoro.NewConfig(repo, duration, duration, duration)
+  config.CycleLength = 2
+  config.Notify = false
+
+  noop := func(pomodoro.Interval) {}
+
+  for i := 1; i <= 8; i++ {
+    expCategory := pomodoro.CategoryPomodoro
+    switch {
+    case i%4 == 0:
+      expCategory = pomodoro.CategoryLongBreak
+    case i%2 == 0:
+      expCategory = pomodoro.CategoryShortBreak
+    }
+
+    res, err := pomodoro.GetInterval(context.Background(), config)
+    if err != nil {
+      t.Fatal(err)
+    }
+
+    if res.Category != expCategory {
+      t.Errorf("Interval %d: expected category %q, got %q.\n", i,
+        expCategory, res.Category)
+    }
+
+    if err := res.Start(context.Background(), config,
+      noop, noop, noop); err != nil {
+      t.Fatal(err)
+    }
+  }
+}
+
+func TestReconfigure(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 25*time.Minute, 0, 0)
+  config.Profile = "study"
+
+  if err := pomodoro.Reconfigure(ctx, config); err != nil {
+    t.Fatalf("Expected no error without intervals, got %q", err)
+  }
+
+  if _, err := pomodoro.GetInterval(ctx, config); err != nil {
+    t.Fatal(err)
+  }
+
+  config.PomodoroDuration = 50 * time.Minute
+  config.Profile = "deep-work"
+  if err := pomodoro.Reconfigure(ctx, config); err != nil {
+    t.Fatal(err)
+  }
+
+  next, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if next.PlannedDuration != config.PomodoroDuration ||
+    next.Profile != config.Profile {
+    t.Errorf("Expected %s of %s, got %s of %s instead\n",
+      config.PomodoroDuration, config.Profile, next.PlannedDuration,
+      next.Profile)
+  }
+
+  next.State = pomodoro.StatePaused
+  if err := repo.Update(ctx, next); err != nil {
+    t.Fatal(err)
+  }
+  if err := pomodoro.Reconfigure(ctx, config); !errors.Is(err,
+    pomodoro.ErrIntervalRunning) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrIntervalRunning, err)
+  }
+}
+
+func TestShorten(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 25*time.Minute, 0, 0)
+
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if _, err := i.Shorten(ctx, config, 0); !errors.Is(err,
+    pomodoro.ErrInvalidInterval) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidInterval, err)
+  }
+
+  i, err = i.Shorten(ctx, config, 18*time.Minute)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  next, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if next.ID != i.ID || next.PlannedDuration != 18*time.Minute {
+    t.Errorf("Expected pomodoro %d planned for 18m, got %d for %s", i.ID,
+      next.ID, next.PlannedDuration)
+  }
+
+  next.State = pomodoro.StateRunning
+  if err := repo.Update(ctx, next); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := next.Shorten(ctx, config, time.Minute); !errors.Is(err,
+    pomodoro.ErrIntervalRunning) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrIntervalRunning, err)
+  }
+}
+
+func TestGetIntervalCancelled(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  ctx, cancel := context.WithCancel(context.Background())
+  cancel()
+
+  if _, err := pomodoro.GetInterval(ctx, config); !errors.Is(err,
+    context.Canceled) {
+    t.Errorf("Expected error %q, got %q", context.Canceled, err)
+  }
+}
+
 func TestPause(t *testing.T) {
   const duration = 2 * time.Second
 
@@ -170,7 +306,7 @@ func TestPause(t *testing.T) {
     t.Run(tc.name, func(t *testing.T) {
       ctx, cancel := context.WithCancel(context.Background())
 
-      i, err := pomodoro.GetInterval(config)
+      i, err := pomodoro.GetInterval(context.Background(), config)
       if err != nil {
         t.Fatal(err)
       }
@@ -180,7 +316,7 @@ func TestPause(t *testing.T) {
         t.Errorf("End callback should not be executed")
       }
       periodic := func(i pomodoro.Interval) {
-        if err := i.Pause(config); err != nil {
+        if err := i.Pause(context.Background(), config); err != nil {
           t.Fatal(err)
         }
       }
@@ -191,12 +327,12 @@ func TestPause(t *testing.T) {
         }
       }
 
-      i, err = pomodoro.GetInterval(config)
+      i, err = pomodoro.GetInterval(context.Background(), config)
       if err != nil {
         t.Fatal(err)
       }
 
-      err = i.Pause(config)
+      err = i.Pause(context.Background(), config)
       if err != nil {
         if ! errors.Is(err, expError) {
           t.Fatalf("Expected error %q, got %q", expError, err)
@@ -207,7 +343,7 @@ func TestPause(t *testing.T) {
         t.Errorf("Expected error %q, got nil", expError)
       }
 
-      i, err = repo.ByID(i.ID)
+      i, err = repo.ByID(context.Background(), i.ID)
       if err != nil {
         t.Fatal(err)
       }
@@ -252,7 +388,7 @@ func TestStart(t *testing.T) {
     t.Run(tc.name, func(t *testing.T) {
       ctx, cancel := context.WithCancel(context.Background())
 
-      i, err := pomodoro.GetInterval(config)
+      i, err := pomodoro.GetInterval(context.Background(), config)
       if err != nil {
         t.Fatal(err)
       }
@@ -292,7 +428,7 @@ func TestStart(t *testing.T) {
         t.Fatal(err)
       }
 
-      i, err = repo.ByID(i.ID)
+      i, err = repo.ByID(context.Background(), i.ID)
       if err != nil {
         t.Fatal(err)
       }
@@ -308,4 +444,57 @@ func TestStart(t *testing.T) {
       cancel()
     })
   }
-}
\ No newline at end of file
+}
+// cancellingRepo cancels the context of a running interval when it's
+// read for the n-th time, as if the user quit in the middle of a tick.
+type cancellingRepo struct {
+  pomodoro.Repository
+  cancel context.CancelFunc
+  reads  *int
+  n      int
+}
+
+func (r cancellingRepo) ByID(ctx context.Context,
+  id int64) (pomodoro.Interval, error) {
+
+  *r.reads++
+  if *r.reads == r.n {
+    r.cancel()
+  }
+  if err := ctx.Err(); err != nil {
+    return pomodoro.Interval{}, err
+  }
+
+  return r.Repository.ByID(ctx, id)
+}
+
+func TestStartCancelledTick(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx, cancel := context.WithCancel(context.Background())
+  defer cancel()
+
+  // The first read starts the timer, the second one is the first tick
+  reads := 0
+  config := pomodoro.NewConfig(cancellingRepo{repo, cancel, &reads, 2},
+    time.Minute, time.Minute, time.Minute)
+
+  i, err := pomodoro.GetInterval(context.Background(), config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  noop := func(pomodoro.Interval) {}
+  if err := i.Start(ctx, config, noop, noop, noop); err != nil {
+    t.Fatal(err)
+  }
+
+  i, err = repo.ByID(context.Background(), i.ID)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.State != pomodoro.StateCancelled {
+    t.Errorf("Expected state %d, got %d", pomodoro.StateCancelled, i.State)
+  }
+}
@@ -0,0 +1,61 @@
+package pomodoro
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// AddManual records an interval done away from the timer, such as a
+// whiteboard session. It's stored as done and flagged as manual. The
+// interval must be over already and can't overlap another one, so the
+// same time isn't counted twice.
+func AddManual(ctx context.Context, config *IntervalConfig,
+	i Interval) (Interval, error) {
+
+	i.ID = 0
+	i.State = StateDone
+	i.Manual = true
+	i.User = config.User
+	if i.Profile == "" {
+		i.Profile = config.Profile
+	}
+	if i.PlannedDuration == 0 {
+		i.PlannedDuration = i.ActualDuration
+	}
+
+	if i.ActualDuration <= 0 {
+		return i, fmt.Errorf("%w: duration must be positive",
+			ErrInvalidInterval)
+	}
+
+	if err := i.Validate(config.AllowOvertime); err != nil {
+		return i, err
+	}
+
+	end := i.StartTime.Add(i.ActualDuration)
+	if end.After(time.Now()) {
+		return i, fmt.Errorf("%w: interval ends in the future",
+			ErrInvalidInterval)
+	}
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	overlaps, err := config.store().List(ctx, Query{
+		Start: i.StartTime,
+		End:   end,
+		Limit: 1,
+	})
+	if err != nil {
+		return i, err
+	}
+
+	if len(overlaps) > 0 {
+		return i, fmt.Errorf("%w: overlaps interval %d", ErrInvalidInterval,
+			overlaps[0].ID)
+	}
+
+	i.ID, err = config.store().Create(ctx, i)
+	return i, err
+}
@@ -0,0 +1,135 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestAddManual(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+  start := time.Date(2026, 10, 16, 14, 0, 0, 0, time.UTC)
+
+  // A timed pomodoro from 13:00 to 13:25
+  if _, err := repo.Create(ctx, pomodoro.Interval{
+    StartTime:       start.Add(-time.Hour),
+    PlannedDuration: 25 * time.Minute,
+    ActualDuration:  25 * time.Minute,
+    Category:        pomodoro.CategoryPomodoro,
+    State:           pomodoro.StateDone,
+  }); err != nil {
+    t.Fatal(err)
+  }
+
+  testCases := []struct {
+    name   string
+    start  time.Time
+    d      time.Duration
+    cat    string
+    expErr error
+  }{
+    {name: "Valid", start: start, d: 25 * time.Minute,
+      cat: pomodoro.CategoryPomodoro},
+    {name: "Overlap", start: start.Add(-50 * time.Minute), d: time.Hour,
+      cat: pomodoro.CategoryPomodoro, expErr: pomodoro.ErrInvalidInterval},
+    {name: "Future", start: time.Now(), d: 25 * time.Minute,
+      cat: pomodoro.CategoryPomodoro, expErr: pomodoro.ErrInvalidInterval},
+    {name: "NoDuration", start: start.Add(time.Hour),
+      cat: pomodoro.CategoryPomodoro, expErr: pomodoro.ErrInvalidInterval},
+    {name: "Category", start: start.Add(time.Hour), d: 25 * time.Minute,
+      cat: "Nap", expErr: pomodoro.ErrInvalidInterval},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      i, err := pomodoro.AddManual(ctx, config, pomodoro.Interval{
+        StartTime:      tc.start,
+        ActualDuration: tc.d,
+        Category:       tc.cat,
+        Task:           "whiteboard",
+      })
+
+      if tc.expErr != nil {
+        if !errors.Is(err, tc.expErr) {
+          t.Fatalf("Expected error %q, got %q", tc.expErr, err)
+        }
+        return
+      }
+      if err != nil {
+        t.Fatal(err)
+      }
+
+      stored, err := repo.ByID(ctx, i.ID)
+      if err != nil {
+        t.Fatal(err)
+      }
+      if !stored.Manual || stored.State != pomodoro.StateDone ||
+        stored.PlannedDuration != tc.d || stored.Task != "whiteboard" {
+        t.Errorf("Expected done manual interval, got %v instead\n", stored)
+      }
+    })
+  }
+
+  for _, exclude := range []bool{false, true} {
+    config.ExcludeManual = exclude
+    ds, err := pomodoro.DailySummary(ctx, start, config)
+    if err != nil {
+      t.Fatal(err)
+    }
+
+    exp := 50 * time.Minute
+    if exclude {
+      exp = 25 * time.Minute
+    }
+    if ds[0] != exp {
+      t.Errorf("Expected pomodoro time %s excluding manual %t, got %s\n",
+        exp, exclude, ds[0])
+    }
+  }
+}
+
+func TestAddManualPaused(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  paused, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.Edit(ctx, config, paused.ID,
+    func(i *pomodoro.Interval) {
+      i.StartTime = time.Now().Add(-10 * time.Minute)
+      i.ActualDuration = 5 * time.Minute
+      i.State = pomodoro.StatePaused
+    }); err != nil {
+    t.Fatal(err)
+  }
+
+  // A session done away from the timer the day before
+  if _, err := pomodoro.AddManual(ctx, config, pomodoro.Interval{
+    StartTime:      time.Now().Add(-24 * time.Hour),
+    ActualDuration: 25 * time.Minute,
+    Category:       pomodoro.CategoryPomodoro,
+  }); err != nil {
+    t.Fatal(err)
+  }
+
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.ID != paused.ID || i.State != pomodoro.StatePaused {
+    t.Errorf("Expected paused interval %d to resume, got %+v", paused.ID, i)
+  }
+}
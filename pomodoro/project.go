@@ -0,0 +1,241 @@
+package pomodoro
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Projects are paths such as "work/api/auth", so the time spent in a
+// project includes its subprojects. Tags such as "backend" are free-form.
+// Both are stored in lower case.
+
+// ParseProject returns project in its stored form, without surrounding
+// slashes. Each level of the path may hold letters, digits, '.', '-' and
+// '_'.
+func ParseProject(project string) (string, error) {
+	project = strings.ToLower(strings.Trim(strings.TrimSpace(project), "/"))
+	if project == "" {
+		return "", nil
+	}
+
+	for _, level := range strings.Split(project, "/") {
+		if level == "" || !validLabel(level) {
+			return "", fmt.Errorf("%w: invalid project %q", ErrInvalidInterval,
+				project)
+		}
+	}
+
+	return project, nil
+}
+
+// ParseTags returns the tags in list in their stored form: sorted, without
+// duplicates and without a leading '#'. Entries may hold several tags
+// separated by commas or spaces.
+func ParseTags(list []string) ([]string, error) {
+	tags := []string{}
+	for _, entry := range list {
+		for _, tag := range strings.FieldsFunc(entry, func(r rune) bool {
+			return r == ',' || r == ' '
+		}) {
+			tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+			if !validLabel(tag) {
+				return nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidInterval,
+					tag)
+			}
+			if !contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+
+	return tags, nil
+}
+
+func validLabel(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+		case r == '.', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+// InProject reports whether project is prefix or one of its subprojects.
+// Every project is in the empty prefix.
+func InProject(project, prefix string) bool {
+	return prefix == "" || project == prefix ||
+		strings.HasPrefix(project, prefix+"/")
+}
+
+// HasTag reports whether the interval is tagged with tag.
+func (i Interval) HasTag(tag string) bool {
+	return contains(i.Tags, tag)
+}
+
+// Filter narrows the summaries to a project, including its subprojects,
+// and to a tag. The zero Filter selects every interval.
+type Filter struct {
+	Project string
+	Tag     string
+}
+
+func (f Filter) String() string {
+	parts := []string{}
+	if f.Project != "" {
+		parts = append(parts, f.Project)
+	}
+	if f.Tag != "" {
+		parts = append(parts, "#"+f.Tag)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (f Filter) apply(q Query) Query {
+	q.Project = f.Project
+	q.Tag = f.Tag
+	return q
+}
+
+// SetFilter narrows the summaries to the intervals selected by f.
+func (c *IntervalConfig) SetFilter(f Filter) {
+	c.Set(func(c *IntervalConfig) { c.Filter = f })
+}
+
+func (c *IntervalConfig) filter() Filter {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Filter
+}
+
+// GroupTotal is the focus time spent in a project or with a tag.
+type GroupTotal struct {
+	Name  string
+	Focus time.Duration
+}
+
+// Depth is the level of a project in the hierarchy, 0 at the top.
+func (g GroupTotal) Depth() int {
+	if g.Name == "" {
+		return 0
+	}
+
+	return strings.Count(g.Name, "/")
+}
+
+// groupPomodoros adds up the focus time between start and end, within
+// filter f, under the names returned by groups for each pomodoro. The
+// totals are sorted by name, so subprojects follow their parent.
+func groupPomodoros(ctx context.Context, start, end time.Time,
+	config *IntervalConfig, f Filter, groups func(Interval) []string) (
+	[]GroupTotal, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	q := f.apply(Query{
+		Start:         start,
+		End:           end,
+		Categories:    []string{CategoryPomodoro},
+		ExcludeManual: config.ExcludeManual,
+	})
+
+	list, err := config.store().List(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	focus := map[string]time.Duration{}
+	for _, i := range list {
+		for _, name := range groups(i) {
+			focus[name] += q.Duration(i)
+		}
+	}
+
+	totals := make([]GroupTotal, 0, len(focus))
+	for name, d := range focus {
+		totals = append(totals, GroupTotal{Name: name, Focus: d})
+	}
+	sort.Slice(totals, func(a, b int) bool {
+		return totals[a].Name < totals[b].Name
+	})
+
+	return totals, nil
+}
+
+// ProjectSummary returns the focus time spent between start and end in
+// each project, rolled up so a project includes its subprojects. Time
+// outside any project is listed under the empty name.
+func ProjectSummary(ctx context.Context, start, end time.Time,
+	config *IntervalConfig) ([]GroupTotal, error) {
+
+	return groupPomodoros(ctx, start, end, config, config.filter(),
+		projectLevels)
+}
+
+// Projects returns the projects worked on between start and end and their
+// parents, sorted so subprojects follow their parent. The configured
+// filter doesn't apply, so it can be used to pick one.
+func Projects(ctx context.Context, start, end time.Time,
+	config *IntervalConfig) ([]string, error) {
+
+	totals, err := groupPomodoros(ctx, start, end, config, Filter{},
+		projectLevels)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := []string{}
+	for _, t := range totals {
+		if t.Name != "" {
+			projects = append(projects, t.Name)
+		}
+	}
+
+	return projects, nil
+}
+
+// projectLevels returns the project of i and its parents, or the empty
+// name without a project.
+func projectLevels(i Interval) []string {
+	if i.Project == "" {
+		return []string{""}
+	}
+
+	levels := strings.Split(i.Project, "/")
+	names := make([]string, len(levels))
+	for k := range levels {
+		names[k] = strings.Join(levels[:k+1], "/")
+	}
+
+	return names
+}
+
+// TagSummary returns the focus time spent between start and end with each
+// tag. Pomodoros with several tags count toward each of them, and those
+// without tags are listed under the empty name.
+func TagSummary(ctx context.Context, start, end time.Time,
+	config *IntervalConfig) ([]GroupTotal, error) {
+
+	return groupPomodoros(ctx, start, end, config, config.filter(),
+		func(i Interval) []string {
+			if len(i.Tags) == 0 {
+				return []string{""}
+			}
+
+			return i.Tags
+		})
+}
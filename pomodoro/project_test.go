@@ -0,0 +1,169 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestParseProjectAndTags(t *testing.T) {
+  testCases := []struct {
+    project string
+    exp     string
+    expErr  bool
+  }{
+    {"Work/API/auth", "work/api/auth", false},
+    {"/work/", "work", false},
+    {"", "", false},
+    {"work//api", "", true},
+    {"work/my api", "", true},
+  }
+
+  for _, tc := range testCases {
+    p, err := pomodoro.ParseProject(tc.project)
+    if tc.expErr != (err != nil) || p != tc.exp {
+      t.Errorf("%q: expected %q and error %t, got %q and %v", tc.project,
+        tc.exp, tc.expErr, p, err)
+    }
+  }
+
+  tags, err := pomodoro.ParseTags([]string{"#Review,backend", "api_v2 review"})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if exp := "api_v2 backend review"; strings.Join(tags, " ") != exp {
+    t.Errorf("Expected tags %q, got %q instead\n", exp, tags)
+  }
+
+  if _, err := pomodoro.ParseTags([]string{"50%"}); !errors.Is(err,
+    pomodoro.ErrInvalidInterval) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidInterval, err)
+  }
+}
+
+func TestProjectSummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  intervals := []struct {
+    project string
+    tags    []string
+    minutes int
+  }{
+    {"work/api/auth", []string{"backend", "review"}, 25},
+    {"work/api", []string{"backend"}, 20},
+    // "work/apiary" must not count toward "work/api"
+    {"work/apiary", nil, 15},
+    {"", []string{"under_score"}, 10},
+    {"home", nil, 5},
+  }
+
+  for k, i := range intervals {
+    d := time.Duration(i.minutes) * time.Minute
+    _, err := repo.Create(ctx, pomodoro.Interval{
+      StartTime:       start.Add(time.Duration(k) * time.Hour),
+      PlannedDuration: d,
+      ActualDuration:  d,
+      Category:        pomodoro.CategoryPomodoro,
+      State:           pomodoro.StateDone,
+      Project:         i.project,
+      Tags:            i.tags,
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  end := start.Add(24 * time.Hour)
+  totals, err := pomodoro.ProjectSummary(ctx, start, end, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  exp := []pomodoro.GroupTotal{
+    {Name: "", Focus: 10 * time.Minute},
+    {Name: "home", Focus: 5 * time.Minute},
+    {Name: "work", Focus: 60 * time.Minute},
+    {Name: "work/api", Focus: 45 * time.Minute},
+    {Name: "work/api/auth", Focus: 25 * time.Minute},
+    {Name: "work/apiary", Focus: 15 * time.Minute},
+  }
+  if len(totals) != len(exp) {
+    t.Fatalf("Expected %v, got %v instead\n", exp, totals)
+  }
+  for k := range exp {
+    if totals[k] != exp[k] {
+      t.Errorf("Expected %v, got %v instead\n", exp[k], totals[k])
+    }
+  }
+
+  tags, err := pomodoro.TagSummary(ctx, start, end, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(tags) != 4 || tags[1].Name != "backend" ||
+    tags[1].Focus != 45*time.Minute {
+    t.Errorf("Expected 45m tagged backend, got %v", tags)
+  }
+
+  // The filter narrows the daily summary and the queries
+  testCases := []struct {
+    filter pomodoro.Filter
+    exp    time.Duration
+  }{
+    {pomodoro.Filter{}, 75 * time.Minute},
+    {pomodoro.Filter{Project: "work/api"}, 45 * time.Minute},
+    {pomodoro.Filter{Project: "work", Tag: "review"}, 25 * time.Minute},
+    {pomodoro.Filter{Tag: "under_score"}, 10 * time.Minute},
+    {pomodoro.Filter{Tag: "under"}, 0},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.filter.String(), func(t *testing.T) {
+      config.SetFilter(tc.filter)
+
+      ds, err := pomodoro.DailySummary(ctx, start, config)
+      if err != nil {
+        t.Fatal(err)
+      }
+      if ds[0] != tc.exp {
+        t.Errorf("Expected %s of focus, got %s instead\n", tc.exp, ds[0])
+      }
+
+      list, err := pomodoro.List(ctx, config, pomodoro.Query{
+        Project: tc.filter.Project,
+        Tag:     tc.filter.Tag,
+      })
+      if err != nil {
+        t.Fatal(err)
+      }
+
+      var d time.Duration
+      for _, i := range list {
+        d += i.ActualDuration
+      }
+      if d != tc.exp {
+        t.Errorf("Expected %s listed, got %s instead\n", tc.exp, d)
+      }
+
+      // Projects lists every project whatever the filter
+      projects, err := pomodoro.Projects(ctx, start, end, config)
+      if err != nil {
+        t.Fatal(err)
+      }
+      if len(projects) != 5 || projects[0] != "home" ||
+        projects[4] != "work/apiary" {
+        t.Errorf("Expected 5 projects, got %v", projects)
+      }
+    })
+  }
+}
@@ -0,0 +1,185 @@
+package pomodoro
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Query selects intervals from a repository. Zero valued fields don't
+// restrict the result.
+type Query struct {
+	// Start and End select intervals that ran at some point between them.
+	Start time.Time
+	End   time.Time
+
+	Categories []string
+	States     []int
+	Task       string
+	TaskID     int64
+	// Project selects a project and its subprojects, Tag the intervals
+	// tagged with it.
+	Project string
+	Tag     string
+	// ExcludeManual leaves out manually entered intervals.
+	ExcludeManual bool
+	Profile       string
+	// User selects the intervals of a user, and those recorded before
+	// histories were kept per user, which have none.
+	User string
+	// ExcludeUnowned leaves out the intervals without a user.
+	ExcludeUnowned bool
+
+	// Limit and Offset page through the result, which is ordered by start
+	// time, or from the latest interval when Descending is set.
+	Limit      int
+	Offset     int
+	Descending bool
+}
+
+// Match reports whether i is selected by the query, ignoring paging.
+func (q Query) Match(i Interval) bool {
+	if !q.Start.IsZero() && !i.StartTime.Add(i.ActualDuration).After(q.Start) {
+		return false
+	}
+
+	if !q.End.IsZero() && !i.StartTime.Before(q.End) {
+		return false
+	}
+
+	if len(q.Categories) > 0 && !contains(q.Categories, i.Category) {
+		return false
+	}
+
+	if len(q.States) > 0 && !contains(q.States, i.State) {
+		return false
+	}
+
+	if q.Task != "" && i.Task != q.Task {
+		return false
+	}
+
+	if q.TaskID != 0 && i.TaskID != q.TaskID {
+		return false
+	}
+
+	if !InProject(i.Project, q.Project) {
+		return false
+	}
+
+	if q.Tag != "" && !i.HasTag(q.Tag) {
+		return false
+	}
+
+	if q.Profile != "" && i.Profile != q.Profile {
+		return false
+	}
+
+	if q.ExcludeManual && i.Manual {
+		return false
+	}
+
+	if q.User != "" && i.User != "" && i.User != q.User {
+		return false
+	}
+
+	if q.ExcludeUnowned && i.User == "" {
+		return false
+	}
+
+	return true
+}
+
+// Duration returns the part of the interval's actual duration that falls
+// within the query period.
+func (q Query) Duration(i Interval) time.Duration {
+	start, end := q.Start, q.End
+	if start.IsZero() {
+		start = i.StartTime
+	}
+	if end.IsZero() {
+		end = i.StartTime.Add(i.ActualDuration)
+	}
+
+	return i.Overlap(start, end)
+}
+
+// List returns the stored intervals selected by q.
+func List(ctx context.Context, config *IntervalConfig,
+	q Query) ([]Interval, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return config.store().List(ctx, q)
+}
+
+// LastPomodoro returns the latest pomodoro, or ErrNoIntervals if there's
+// none.
+func LastPomodoro(ctx context.Context,
+	config *IntervalConfig) (Interval, error) {
+
+	list, err := List(ctx, config, Query{
+		Categories: []string{CategoryPomodoro},
+		Limit:      1,
+		Descending: true,
+	})
+	if err != nil {
+		return Interval{}, err
+	}
+
+	if len(list) == 0 {
+		return Interval{}, ErrNoIntervals
+	}
+
+	return list[0], nil
+}
+
+// Current returns the latest interval, which may be waiting to start,
+// running or paused, or ErrNoIntervals if there's none. Unlike GetInterval
+// it never creates one.
+func Current(ctx context.Context, config *IntervalConfig) (Interval, error) {
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return config.store().Last(ctx, config.User)
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+var stateNames = []string{
+	StateNotStarted: "NotStarted",
+	StateRunning:    "Running",
+	StatePaused:     "Paused",
+	StateDone:       "Done",
+	StateCancelled:  "Cancelled",
+}
+
+// StateName returns the display name of an interval state.
+func StateName(state int) string {
+	if state < 0 || state >= len(stateNames) {
+		return fmt.Sprintf("State(%d)", state)
+	}
+
+	return stateNames[state]
+}
+
+// ParseState returns the state with the given name, ignoring case.
+func ParseState(name string) (int, error) {
+	for s, n := range stateNames {
+		if strings.EqualFold(n, name) {
+			return s, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", ErrInvalidState, name)
+}
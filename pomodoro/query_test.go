@@ -0,0 +1,113 @@
+package pomodoro_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestList(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+  intervals := []pomodoro.Interval{
+    {StartTime: day.Add(9 * time.Hour), Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone, Task: "report"},
+    {StartTime: day.Add(9*time.Hour + 25*time.Minute),
+      Category: pomodoro.CategoryShortBreak, State: pomodoro.StateDone},
+    {StartTime: day.Add(9*time.Hour + 30*time.Minute),
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateCancelled,
+      Task: "email"},
+    // Previous day, crossing into this one
+    {StartTime: day.Add(-10 * time.Minute), Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone, Task: "report"},
+    // Next day
+    {StartTime: day.AddDate(0, 0, 1).Add(time.Hour),
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone},
+  }
+
+  for _, i := range intervals {
+    i.PlannedDuration = 25 * time.Minute
+    i.ActualDuration = 20 * time.Minute
+    if _, err := repo.Create(ctx, i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  testCases := []struct {
+    name   string
+    q      pomodoro.Query
+    expIDs []int64
+  }{
+    {name: "All", q: pomodoro.Query{}, expIDs: []int64{4, 1, 2, 3, 5}},
+    {name: "Day",
+      q:      pomodoro.Query{Start: day, End: day.AddDate(0, 0, 1)},
+      expIDs: []int64{4, 1, 2, 3}},
+    {name: "Categories",
+      q: pomodoro.Query{Start: day, End: day.AddDate(0, 0, 1),
+        Categories: []string{pomodoro.CategoryShortBreak,
+          pomodoro.CategoryLongBreak}},
+      expIDs: []int64{2}},
+    {name: "States",
+      q:      pomodoro.Query{States: []int{pomodoro.StateCancelled}},
+      expIDs: []int64{3}},
+    {name: "Task", q: pomodoro.Query{Task: "report"},
+      expIDs: []int64{4, 1}},
+    {name: "Descending", q: pomodoro.Query{Descending: true},
+      expIDs: []int64{5, 3, 2, 1, 4}},
+    {name: "Paging", q: pomodoro.Query{Limit: 2, Offset: 1},
+      expIDs: []int64{1, 2}},
+    {name: "OffsetOnly", q: pomodoro.Query{Offset: 3},
+      expIDs: []int64{3, 5}},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      res, err := repo.List(ctx, tc.q)
+      if err != nil {
+        t.Fatal(err)
+      }
+
+      ids := []int64{}
+      for _, i := range res {
+        ids = append(ids, i.ID)
+      }
+
+      if len(ids) != len(tc.expIDs) {
+        t.Fatalf("Expected IDs %v, got %v instead\n", tc.expIDs, ids)
+      }
+      for k := range ids {
+        if ids[k] != tc.expIDs[k] {
+          t.Fatalf("Expected IDs %v, got %v instead\n", tc.expIDs, ids)
+        }
+      }
+    })
+  }
+}
+
+func TestIntervalTask(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Task = "write report"
+
+  i, err := pomodoro.GetInterval(context.Background(), config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  i, err = repo.ByID(context.Background(), i.ID)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if i.Task != config.Task {
+    t.Errorf("Expected task %q, got %q instead\n", config.Task, i.Task)
+  }
+}
@@ -0,0 +1,36 @@
+package pomodoro_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+// getRepo returns a fresh repository for the backend named in
+// POMO_TEST_REPO, such as "memory" or "sqlite" (the default).
+func getRepo(t *testing.T) (pomodoro.Repository, func()) {
+  t.Helper()
+
+  scheme := os.Getenv("POMO_TEST_REPO")
+  if scheme == "" {
+    scheme = "sqlite"
+  }
+
+  tf, err := os.CreateTemp("", "pomo")
+  if err != nil {
+    t.Fatal(err)
+  }
+  tf.Close()
+  os.Remove(tf.Name())
+
+  repo, err := repository.Open(scheme + ":" + tf.Name())
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  return repo, func() {
+    os.Remove(tf.Name())
+  }
+}
@@ -0,0 +1,83 @@
+package pomodoro
+
+import (
+	"context"
+)
+
+// Reset deletes the intervals selected by q, except a running one, and
+// returns them. If archive isn't nil, the intervals are copied to it
+// first, under new IDs, and nothing is deleted unless the copy succeeds.
+func Reset(ctx context.Context, config *IntervalConfig, q Query,
+	archive Repository) ([]Interval, error) {
+
+	list, err := ResetList(ctx, config, q)
+	if err != nil {
+		return nil, err
+	}
+
+	if archive != nil {
+		for _, i := range list {
+			if err := archiveInterval(ctx, config, archive, i); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for k, i := range list {
+		if err := Delete(ctx, config, i.ID); err != nil {
+			return list[:k], err
+		}
+	}
+
+	return list, nil
+}
+
+// ResetList returns the intervals Reset would delete. Those without a
+// user are left out while other users share the repository, as they
+// can't be deleted until adopted.
+func ResetList(ctx context.Context, config *IntervalConfig,
+	q Query) ([]Interval, error) {
+
+	unowned, err := canAdopt(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	all, err := List(ctx, config, q)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []Interval{}
+	for _, i := range all {
+		if i.State != StateRunning && (unowned || i.User != "") {
+			list = append(list, i)
+		}
+	}
+
+	return list, nil
+}
+
+// canAdopt reports whether the configured user can change the intervals
+// without a user, adopting them on the first change.
+func canAdopt(ctx context.Context, config *IntervalConfig) (bool, error) {
+	if config.User == "" {
+		return true, nil
+	}
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return alone(ctx, config.repo, config.User)
+}
+
+func archiveInterval(ctx context.Context, config *IntervalConfig,
+	archive Repository, i Interval) error {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	i.ID = 0
+	_, err := archive.Create(ctx, i)
+	return err
+}
@@ -1,7 +1,9 @@
 package pomodoro
 
 import (
+  "context"
   "fmt"
+  "sort"
   "time"
 )
 
@@ -11,15 +13,31 @@ type LineSeries struct {
   Values []float64
 }
 
-func DailySummary(day time.Time,
+func DailySummary(ctx context.Context, day time.Time,
   config *IntervalConfig) ([]time.Duration, error) {
 
-  dPomo, err := config.repo.CategorySummary(day, CategoryPomodoro)
+  ctx, cancel := config.withTimeout(ctx)
+  defer cancel()
+
+  start, end := config.DayBounds(day)
+
+  f := config.filter()
+  dPomo, err := config.store().CategorySummary(ctx, f.apply(Query{
+    Start:         start,
+    End:           end,
+    Categories:    []string{CategoryPomodoro},
+    ExcludeManual: config.ExcludeManual,
+  }))
   if err != nil {
     return nil, err
   }
 
-  dBreaks, err := config.repo.CategorySummary(day, "%Break")
+  dBreaks, err := config.store().CategorySummary(ctx, f.apply(Query{
+    Start:         start,
+    End:           end,
+    Categories:    []string{CategoryShortBreak, CategoryLongBreak},
+    ExcludeManual: config.ExcludeManual,
+  }))
   if err != nil {
     return nil, err
   }
@@ -30,7 +48,7 @@ func DailySummary(day time.Time,
   }, nil
 }
 
-func RangeSummary(start time.Time, n int,
+func RangeSummary(ctx context.Context, start time.Time, n int,
   config *IntervalConfig) ([]LineSeries, error) {
 
   pomodoroSeries := LineSeries{
@@ -47,12 +65,13 @@ func RangeSummary(start time.Time, n int,
 
   for i := 0; i < n; i++ {
     day := start.AddDate(0, 0, -i)
-    ds, err := DailySummary(day, config)
+    ds, err := DailySummary(ctx, day, config)
     if err != nil {
       return nil, err
     }
 
-    label := fmt.Sprintf("%02d/%s", day.Day(), day.Format("Jan"))
+    dayStart, _ := config.DayBounds(day)
+    label := fmt.Sprintf("%02d/%s", dayStart.Day(), dayStart.Format("Jan"))
 
     pomodoroSeries.Labels[i] = label
     pomodoroSeries.Values[i] = ds[0].Seconds()
@@ -65,4 +84,179 @@ func RangeSummary(start time.Time, n int,
     pomodoroSeries,
     breakSeries,
   }, nil
-}
\ No newline at end of file
+}
+
+// ProfileTotal is the focus time spent in one profile.
+type ProfileTotal struct {
+  Profile string
+  Focus   time.Duration
+}
+
+// ProfileSummary returns the time spent in pomodoros between start and end
+// within the configured filter, for each profile used in that period, by
+// profile name. Intervals timed without a profile have an empty name.
+func ProfileSummary(ctx context.Context, start, end time.Time,
+  config *IntervalConfig) ([]ProfileTotal, error) {
+
+  groups, err := groupPomodoros(ctx, start, end, config, config.filter(),
+    func(i Interval) []string { return []string{i.Profile} })
+  if err != nil {
+    return nil, err
+  }
+
+  totals := make([]ProfileTotal, len(groups))
+  for k, g := range groups {
+    totals[k] = ProfileTotal{Profile: g.Name, Focus: g.Focus}
+  }
+
+  return totals, nil
+}
+
+// DayTotal is the time spent on one day, and the pomodoros done that day.
+type DayTotal struct {
+  Day       time.Time
+  Focus     time.Duration
+  Breaks    time.Duration
+  Pomodoros int
+}
+
+// PeriodReport sums up the intervals of a period of days.
+type PeriodReport struct {
+  Start  time.Time
+  End    time.Time
+  Days   []DayTotal
+  Focus  time.Duration
+  Breaks time.Duration
+  // Done and Cancelled count the pomodoros finished and given up.
+  Done      int
+  Cancelled int
+  // Tasks and Tags are the focus time by task and by tag, the largest
+  // first. Time without a task or a tag is under the empty name.
+  Tasks []GroupTotal
+  Tags  []GroupTotal
+}
+
+// CompletionRate is the share of the pomodoros ended in the period that
+// were finished, or 0 if none ended.
+func (r PeriodReport) CompletionRate() float64 {
+  if r.Done+r.Cancelled == 0 {
+    return 0
+  }
+
+  return float64(r.Done) / float64(r.Done+r.Cancelled)
+}
+
+// PeriodSummary sums up the n days from start, within the configured
+// filter. Daily totals come from DailySummary, so they match the charts,
+// and pomodoros count toward the day they started.
+func PeriodSummary(ctx context.Context, start time.Time, n int,
+  config *IntervalConfig) (PeriodReport, error) {
+
+  r := PeriodReport{Days: make([]DayTotal, n)}
+  r.Start, _ = config.DayBounds(start)
+  _, r.End = config.DayBounds(start.AddDate(0, 0, n-1))
+
+  for k := range r.Days {
+    day, _ := config.DayBounds(start.AddDate(0, 0, k))
+    ds, err := DailySummary(ctx, day, config)
+    if err != nil {
+      return r, err
+    }
+
+    r.Days[k] = DayTotal{Day: day, Focus: ds[0], Breaks: ds[1]}
+    r.Focus += ds[0]
+    r.Breaks += ds[1]
+  }
+
+  list, err := List(ctx, config, config.filter().apply(Query{
+    Start:         r.Start,
+    End:           r.End,
+    Categories:    []string{CategoryPomodoro},
+    States:        []int{StateDone, StateCancelled},
+    ExcludeManual: config.ExcludeManual,
+  }))
+  if err != nil {
+    return r, err
+  }
+
+  for _, i := range list {
+    if i.StartTime.Before(r.Start) {
+      continue
+    }
+
+    if i.State == StateCancelled {
+      r.Cancelled++
+      continue
+    }
+
+    r.Done++
+    for k := len(r.Days) - 1; k >= 0; k-- {
+      if !i.StartTime.Before(r.Days[k].Day) {
+        r.Days[k].Pomodoros++
+        break
+      }
+    }
+  }
+
+  r.Tasks, err = groupPomodoros(ctx, r.Start, r.End, config, config.filter(),
+    func(i Interval) []string { return []string{i.Task} })
+  if err != nil {
+    return r, err
+  }
+
+  r.Tags, err = TagSummary(ctx, r.Start, r.End, config)
+  if err != nil {
+    return r, err
+  }
+
+  for _, totals := range [][]GroupTotal{r.Tasks, r.Tags} {
+    sort.SliceStable(totals, func(a, b int) bool {
+      return totals[a].Focus > totals[b].Focus
+    })
+  }
+
+  return r, nil
+}
+
+// Totals are the pomodoros finished and given up, and the time spent in
+// each category, over the whole history.
+type Totals struct {
+  Done      int
+  Cancelled int
+  Time      map[string]time.Duration
+}
+
+// Add counts n intervals of category in state, which lasted d in all.
+func (t *Totals) Add(category string, state, n int, d time.Duration) {
+  if t.Time == nil {
+    t.Time = make(map[string]time.Duration)
+  }
+  t.Time[category] += d
+
+  if category != CategoryPomodoro {
+    return
+  }
+  switch state {
+  case StateDone:
+    t.Done += n
+  case StateCancelled:
+    t.Cancelled += n
+  }
+}
+
+// AllTotals sums up every stored interval, ignoring the configured filter,
+// for monitoring tools. Editing or deleting intervals changes them.
+func AllTotals(ctx context.Context, config *IntervalConfig) (Totals, error) {
+  ctx, cancel := config.withTimeout(ctx)
+  defer cancel()
+
+  t, err := config.store().Totals(ctx, config.User)
+  if err != nil {
+    return t, err
+  }
+  if t.Time == nil {
+    t.Time = make(map[string]time.Duration)
+  }
+
+  return t, nil
+}
@@ -0,0 +1,345 @@
+package pomodoro_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestDayBounds(t *testing.T) {
+  loc := time.FixedZone("UTC+2", 2*60*60)
+
+  testCases := []struct {
+    name     string
+    dayStart time.Duration
+    t        time.Time
+    expStart time.Time
+  }{
+    {name: "Midnight", dayStart: 0,
+      t:        time.Date(2026, 10, 16, 23, 30, 0, 0, loc),
+      expStart: time.Date(2026, 10, 16, 0, 0, 0, 0, loc)},
+    {name: "OtherZone", dayStart: 0,
+      t:        time.Date(2026, 10, 16, 23, 30, 0, 0, time.UTC),
+      expStart: time.Date(2026, 10, 17, 0, 0, 0, 0, loc)},
+    {name: "NightOwlBefore", dayStart: 4 * time.Hour,
+      t:        time.Date(2026, 10, 17, 2, 0, 0, 0, loc),
+      expStart: time.Date(2026, 10, 16, 4, 0, 0, 0, loc)},
+    {name: "NightOwlAfter", dayStart: 4 * time.Hour,
+      t:        time.Date(2026, 10, 17, 5, 0, 0, 0, loc),
+      expStart: time.Date(2026, 10, 17, 4, 0, 0, 0, loc)},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      var repo pomodoro.Repository
+      config := pomodoro.NewConfig(repo, 0, 0, 0)
+      config.Location = loc
+      config.DayStart = tc.dayStart
+
+      start, end := config.DayBounds(tc.t)
+      if !start.Equal(tc.expStart) {
+        t.Errorf("Expected start %s, got %s instead\n", tc.expStart, start)
+      }
+
+      expEnd := tc.expStart.AddDate(0, 0, 1)
+      if !end.Equal(expEnd) {
+        t.Errorf("Expected end %s, got %s instead\n", expEnd, end)
+      }
+    })
+  }
+}
+
+func TestDayBoundsDST(t *testing.T) {
+  loc, err := time.LoadLocation("America/New_York")
+  if err != nil {
+    t.Skip("Skipped: no time zone database:", err)
+  }
+
+  var repo pomodoro.Repository
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = loc
+  config.DayStart = 4 * time.Hour
+
+  // Daylight saving time begins at 2:00 and ends at 2:00
+  for _, day := range []time.Time{
+    time.Date(2026, 3, 8, 12, 0, 0, 0, loc),
+    time.Date(2026, 11, 1, 12, 0, 0, 0, loc),
+  } {
+    start, end := config.DayBounds(day)
+
+    expStart := time.Date(day.Year(), day.Month(), day.Day(), 4, 0, 0, 0, loc)
+    expEnd := expStart.AddDate(0, 0, 1)
+    if !start.Equal(expStart) || !end.Equal(expEnd) {
+      t.Errorf("Expected day from %s to %s, got %s to %s instead\n", expStart,
+        expEnd, start, end)
+    }
+  }
+}
+
+func TestDailySummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  loc := time.FixedZone("UTC-5", -5*60*60)
+  day := time.Date(2026, 10, 16, 12, 0, 0, 0, loc)
+
+  intervals := []pomodoro.Interval{
+    // Fully inside the day
+    {StartTime: time.Date(2026, 10, 16, 9, 0, 0, 0, loc),
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro},
+    // Crosses midnight, 20 minutes fall on the 16th
+    {StartTime: time.Date(2026, 10, 16, 23, 40, 0, 0, loc),
+      ActualDuration: 30 * time.Minute, Category: pomodoro.CategoryPomodoro},
+    // Stored in another zone, but on the 16th in loc
+    {StartTime: time.Date(2026, 10, 17, 3, 0, 0, 0, time.UTC),
+      ActualDuration: 5 * time.Minute, Category: pomodoro.CategoryShortBreak},
+    // Previous day
+    {StartTime: time.Date(2026, 10, 15, 10, 0, 0, 0, loc),
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro},
+  }
+
+  for _, i := range intervals {
+    i.PlannedDuration = i.ActualDuration
+    i.State = pomodoro.StateDone
+    if _, err := repo.Create(context.Background(), i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  testCases := []struct {
+    name      string
+    dayStart  time.Duration
+    expPomo   time.Duration
+    expBreaks time.Duration
+  }{
+    {name: "Midnight", dayStart: 0,
+      expPomo: 45 * time.Minute, expBreaks: 5 * time.Minute},
+    {name: "NightOwl", dayStart: 4 * time.Hour,
+      expPomo: 55 * time.Minute, expBreaks: 5 * time.Minute},
+  }
+
+  for _, tc := range testCases {
+    t.Run(tc.name, func(t *testing.T) {
+      config := pomodoro.NewConfig(repo, 0, 0, 0)
+      config.Location = loc
+      config.DayStart = tc.dayStart
+
+      ds, err := pomodoro.DailySummary(context.Background(), day, config)
+      if err != nil {
+        t.Fatal(err)
+      }
+
+      if ds[0] != tc.expPomo {
+        t.Errorf("Expected pomodoro time %q, got %q instead\n",
+          tc.expPomo, ds[0])
+      }
+      if ds[1] != tc.expBreaks {
+        t.Errorf("Expected break time %q, got %q instead\n",
+          tc.expBreaks, ds[1])
+      }
+    })
+  }
+}
+
+func TestProfileSummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+  intervals := []pomodoro.Interval{
+    {StartTime: start.Add(9 * time.Hour), ActualDuration: 50 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, Profile: "deep-work"},
+    {StartTime: start.Add(10 * time.Hour), ActualDuration: 10 * time.Minute,
+      Category: pomodoro.CategoryShortBreak, Profile: "deep-work"},
+    {StartTime: start.Add(11 * time.Hour), ActualDuration: 25 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, Profile: "study",
+      Project: "school"},
+    {StartTime: start.Add(12 * time.Hour), ActualDuration: 50 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, Profile: "deep-work"},
+    {StartTime: start.Add(13 * time.Hour), ActualDuration: 20 * time.Minute,
+      Category: pomodoro.CategoryPomodoro},
+  }
+
+  for _, i := range intervals {
+    i.PlannedDuration = i.ActualDuration
+    i.State = pomodoro.StateDone
+    if _, err := repo.Create(context.Background(), i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  totals, err := pomodoro.ProfileSummary(context.Background(), start,
+    start.AddDate(0, 0, 1), config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  expected := []pomodoro.ProfileTotal{
+    {Profile: "", Focus: 20 * time.Minute},
+    {Profile: "deep-work", Focus: 100 * time.Minute},
+    {Profile: "study", Focus: 25 * time.Minute},
+  }
+
+  if len(totals) != len(expected) {
+    t.Fatalf("Expected %v, got %v instead\n", expected, totals)
+  }
+  for k := range expected {
+    if totals[k] != expected[k] {
+      t.Errorf("Expected %v, got %v instead\n", expected[k], totals[k])
+    }
+  }
+
+  // The filter narrows the profiles like the other summaries
+  config.SetFilter(pomodoro.Filter{Project: "school"})
+  totals, err = pomodoro.ProfileSummary(context.Background(), start,
+    start.AddDate(0, 0, 1), config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  exp := pomodoro.ProfileTotal{Profile: "study", Focus: 25 * time.Minute}
+  if len(totals) != 1 || totals[0] != exp {
+    t.Errorf("Expected [%v], got %v instead\n", exp, totals)
+  }
+}
+
+func TestPeriodSummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.Location = time.UTC
+
+  monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+  intervals := []pomodoro.Interval{
+    {StartTime: monday.Add(9 * time.Hour), ActualDuration: 25 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+      Task: "report", Tags: []string{"writing"}},
+    {StartTime: monday.Add(10 * time.Hour), ActualDuration: 25 * time.Minute,
+      Category: pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+      Task: "report"},
+    {StartTime: monday.Add(10*time.Hour + 25*time.Minute),
+      ActualDuration: 5 * time.Minute, Category: pomodoro.CategoryShortBreak,
+      State: pomodoro.StateDone},
+    // Given up after 10 minutes
+    {StartTime: monday.Add(2*24*time.Hour + 9*time.Hour),
+      ActualDuration: 10 * time.Minute, Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateCancelled, Task: "review"},
+    {StartTime: monday.Add(4*24*time.Hour + 9*time.Hour),
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone, Task: "review", Tags: []string{"writing"}},
+    // Next week
+    {StartTime: monday.Add(7*24*time.Hour + 9*time.Hour),
+      ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro,
+      State: pomodoro.StateDone},
+  }
+
+  for _, i := range intervals {
+    i.PlannedDuration = i.ActualDuration
+    if _, err := repo.Create(ctx, i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  r, err := pomodoro.PeriodSummary(ctx, monday, 7, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if !r.Start.Equal(monday) || !r.End.Equal(monday.AddDate(0, 0, 7)) ||
+    len(r.Days) != 7 {
+    t.Fatalf("Expected a week from %s, got %d days from %s to %s", monday,
+      len(r.Days), r.Start, r.End)
+  }
+
+  if r.Focus != 85*time.Minute || r.Breaks != 5*time.Minute ||
+    r.Done != 3 || r.Cancelled != 1 || r.CompletionRate() != 0.75 {
+    t.Errorf("Unexpected totals %+v", r)
+  }
+
+  expDays := []int{2, 0, 0, 0, 1, 0, 0}
+  for k, exp := range expDays {
+    if r.Days[k].Pomodoros != exp {
+      t.Errorf("Expected %d pomodoros on day %d, got %d", exp, k,
+        r.Days[k].Pomodoros)
+    }
+  }
+  if r.Days[2].Focus != 10*time.Minute {
+    t.Errorf("Expected the time of the cancelled pomodoro, got %s",
+      r.Days[2].Focus)
+  }
+
+  expTasks := []pomodoro.GroupTotal{
+    {Name: "report", Focus: 50 * time.Minute},
+    {Name: "review", Focus: 35 * time.Minute},
+  }
+  if len(r.Tasks) != 2 || r.Tasks[0] != expTasks[0] ||
+    r.Tasks[1] != expTasks[1] {
+    t.Errorf("Expected tasks %v, got %v", expTasks, r.Tasks)
+  }
+  if len(r.Tags) != 2 || r.Tags[0].Name != "writing" ||
+    r.Tags[0].Focus != 50*time.Minute {
+    t.Errorf("Expected 50m tagged writing first, got %v", r.Tags)
+  }
+}
+
+func TestAllTotals(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  start := time.Date(2026, 10, 14, 9, 0, 0, 0, time.UTC)
+  intervals := []struct {
+    user     string
+    category string
+    state    int
+    d        time.Duration
+  }{
+    {"alice", pomodoro.CategoryPomodoro, pomodoro.StateDone, 25 * time.Minute},
+    {"alice", pomodoro.CategoryPomodoro, pomodoro.StateDone, 25 * time.Minute},
+    {"alice", pomodoro.CategoryPomodoro, pomodoro.StateCancelled, 10 * time.Minute},
+    {"alice", pomodoro.CategoryShortBreak, pomodoro.StateDone, 5 * time.Minute},
+    // Recorded before histories were kept per user
+    {"", pomodoro.CategoryLongBreak, pomodoro.StateDone, 15 * time.Minute},
+    {"bob", pomodoro.CategoryPomodoro, pomodoro.StateDone, 25 * time.Minute},
+  }
+  for k, i := range intervals {
+    if _, err := repo.Create(ctx, pomodoro.Interval{
+      StartTime:       start.Add(time.Duration(k) * time.Hour),
+      PlannedDuration: i.d,
+      ActualDuration:  i.d,
+      Category:        i.category,
+      State:           i.state,
+      User:            i.user,
+    }); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.User = "alice"
+
+  totals, err := pomodoro.AllTotals(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  exp := map[string]time.Duration{
+    pomodoro.CategoryPomodoro:   time.Hour,
+    pomodoro.CategoryShortBreak: 5 * time.Minute,
+    pomodoro.CategoryLongBreak:  15 * time.Minute,
+  }
+  if totals.Done != 2 || totals.Cancelled != 1 {
+    t.Errorf("Expected 2 pomodoros done and 1 cancelled, got %+v", totals)
+  }
+  for c, d := range exp {
+    if totals.Time[c] != d {
+      t.Errorf("Expected %s in %s, got %s", d, c, totals.Time[c])
+    }
+  }
+}
@@ -0,0 +1,278 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrInvalidTask is returned for tasks that fail validation or can't be
+// changed.
+var ErrInvalidTask = errors.New("Invalid task")
+
+// Task is an entry of the planned task list, the "To Do Today" sheet of
+// the technique. Pomodoros count toward the task whose ID they record.
+type Task struct {
+	ID   int64
+	Name string
+	// Estimate is the number of pomodoros the task is expected to take, 0
+	// if it wasn't estimated.
+	Estimate int
+	Created  time.Time
+	// Completed is when the task was marked done, zero while it's open.
+	Completed time.Time
+	// Source names the tool the task was imported from, empty for tasks
+	// added with pomo, and ExternalID identifies it there.
+	Source     string
+	ExternalID string
+	// User is who planned the task.
+	User string
+}
+
+// Done reports whether the task was marked done.
+func (t Task) Done() bool {
+	return !t.Completed.IsZero()
+}
+
+// TaskProgress is a task with the number of pomodoros done for it.
+type TaskProgress struct {
+	Task
+	Pomodoros int
+}
+
+// Over reports whether more pomodoros were spent than estimated.
+func (p TaskProgress) Over() bool {
+	return p.Estimate > 0 && p.Pomodoros > p.Estimate
+}
+
+// Validate checks that the task has a name and a sensible estimate.
+func (t Task) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: name can't be empty", ErrInvalidTask)
+	}
+
+	if t.Estimate < 0 {
+		return fmt.Errorf("%w: estimate can't be negative, got %d",
+			ErrInvalidTask, t.Estimate)
+	}
+
+	return nil
+}
+
+// AddTask adds a task to the list.
+func AddTask(ctx context.Context, config *IntervalConfig, name string,
+	estimate int) (Task, error) {
+
+	t := Task{
+		Name:     strings.TrimSpace(name),
+		Estimate: estimate,
+		Created:  time.Now(),
+	}
+
+	if err := t.Validate(); err != nil {
+		return t, err
+	}
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	var err error
+	t.ID, err = config.store().CreateTask(ctx, t)
+	return t, err
+}
+
+// ImportTask adds t, read from another tool, to the list, or updates the
+// task imported earlier with the same source and external ID. A task done
+// in the other tool is marked done, but a task done here isn't opened
+// again. It reports whether the task was added.
+func ImportTask(ctx context.Context, config *IntervalConfig,
+	t Task) (Task, bool, error) {
+
+	t.Name = strings.TrimSpace(t.Name)
+	if err := t.Validate(); err != nil {
+		return t, false, err
+	}
+
+	if t.Source == "" || t.ExternalID == "" {
+		return t, false, fmt.Errorf("%w: imported tasks need a source and ID",
+			ErrInvalidTask)
+	}
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	tasks, err := config.store().Tasks(ctx, config.User)
+	if err != nil {
+		return t, false, err
+	}
+
+	for _, old := range tasks {
+		if old.Source != t.Source || old.ExternalID != t.ExternalID {
+			continue
+		}
+
+		if old.Done() || t.Completed.IsZero() {
+			t.Completed = old.Completed
+		}
+		t.ID, t.Created = old.ID, old.Created
+
+		return t, false, config.store().UpdateTask(ctx, t)
+	}
+
+	if t.Created.IsZero() {
+		t.Created = time.Now()
+	}
+
+	t.ID, err = config.store().CreateTask(ctx, t)
+	return t, true, err
+}
+
+// GetTask returns the stored task id.
+func GetTask(ctx context.Context, config *IntervalConfig,
+	id int64) (Task, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return config.store().TaskByID(ctx, id)
+}
+
+// CompleteTask marks task id done, or open again if done is false.
+func CompleteTask(ctx context.Context, config *IntervalConfig, id int64,
+	done bool) (Task, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	t, err := config.store().TaskByID(ctx, id)
+	if err != nil {
+		return t, err
+	}
+
+	if t.Done() == done {
+		state := "open"
+		if done {
+			state = "done"
+		}
+		return t, fmt.Errorf("%w: task %d is already %s", ErrInvalidTask, id,
+			state)
+	}
+
+	t.Completed = time.Time{}
+	if done {
+		t.Completed = time.Now()
+	}
+
+	return t, config.store().UpdateTask(ctx, t)
+}
+
+// DeleteTask removes task id from the list. Its intervals keep the task
+// name but no longer count toward it, so a later task can't inherit them
+// if the ID is reused.
+func DeleteTask(ctx context.Context, config *IntervalConfig, id int64) error {
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	if _, err := config.store().TaskByID(ctx, id); err != nil {
+		return err
+	}
+
+	list, err := config.store().List(ctx, Query{TaskID: id})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, i := range list {
+		unlinked := i
+		unlinked.TaskID = 0
+
+		err := config.store().Update(ctx, unlinked, changes(i, unlinked, now)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	config.mu.Lock()
+	if config.TaskID == id {
+		config.TaskID = 0
+	}
+	config.mu.Unlock()
+
+	return config.store().DeleteTask(ctx, id)
+}
+
+// Tasks returns the tasks in the order they were added, with the
+// pomodoros done for each. Done tasks are left out unless all is set.
+func Tasks(ctx context.Context, config *IntervalConfig,
+	all bool) ([]TaskProgress, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	tasks, err := config.store().Tasks(ctx, config.User)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []TaskProgress{}
+	for _, t := range tasks {
+		if t.Done() && !all {
+			continue
+		}
+
+		done, err := config.store().List(ctx, Query{
+			Categories: []string{CategoryPomodoro},
+			States:     []int{StateDone},
+			TaskID:     t.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, TaskProgress{Task: t, Pomodoros: len(done)})
+	}
+
+	return list, nil
+}
+
+// FindTask returns the open task called name, ignoring case, or
+// ErrInvalidTask if there's none.
+func FindTask(ctx context.Context, config *IntervalConfig,
+	name string) (Task, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	tasks, err := config.store().Tasks(ctx, config.User)
+	if err != nil {
+		return Task{}, err
+	}
+
+	for _, t := range tasks {
+		if !t.Done() && strings.EqualFold(t.Name, strings.TrimSpace(name)) {
+			return t, nil
+		}
+	}
+
+	return Task{}, fmt.Errorf("%w: no open task %q", ErrInvalidTask, name)
+}
+
+// SetTask makes new pomodoros count toward t, or toward no task if t is
+// the zero Task.
+func (c *IntervalConfig) SetTask(t Task) {
+	c.Set(func(c *IntervalConfig) {
+		c.Task = t.Name
+		c.TaskID = t.ID
+	})
+}
+
+// CurrentTask returns the task new pomodoros count toward.
+func (c *IntervalConfig) CurrentTask() (string, int64) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Task, c.TaskID
+}
@@ -0,0 +1,121 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+func TestTasks(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+
+  for _, tc := range []struct {
+    name     string
+    estimate int
+  }{
+    {"  ", 1},
+    {"write report", -1},
+  } {
+    if _, err := pomodoro.AddTask(ctx, config, tc.name,
+      tc.estimate); !errors.Is(err, pomodoro.ErrInvalidTask) {
+      t.Errorf("Expected error %q for %q, got %q", pomodoro.ErrInvalidTask,
+        tc.name, err)
+    }
+  }
+
+  report, err := pomodoro.AddTask(ctx, config, "write report", 2)
+  if err != nil {
+    t.Fatal(err)
+  }
+  review, err := pomodoro.AddTask(ctx, config, "review PR", 1)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  found, err := pomodoro.FindTask(ctx, config, "Write Report")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if found.ID != report.ID {
+    t.Errorf("Expected task %d, got %d instead\n", report.ID, found.ID)
+  }
+
+  // New pomodoros count toward the picked task
+  config.SetTask(report)
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.TaskID != report.ID || i.Task != report.Name {
+    t.Errorf("Expected task %d %q, got %d %q instead\n", report.ID,
+      report.Name, i.TaskID, i.Task)
+  }
+
+  start := time.Now().Add(-3 * time.Hour)
+  for k := 0; k < 3; k++ {
+    _, err := pomodoro.AddManual(ctx, config, pomodoro.Interval{
+      StartTime:      start.Add(time.Duration(k) * 30 * time.Minute),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+      Task:           report.Name,
+      TaskID:         report.ID,
+    })
+    if err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  if _, err := pomodoro.CompleteTask(ctx, config, review.ID, true); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.CompleteTask(ctx, config, review.ID,
+    true); !errors.Is(err, pomodoro.ErrInvalidTask) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidTask, err)
+  }
+
+  open, err := pomodoro.Tasks(ctx, config, false)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(open) != 1 || open[0].ID != report.ID {
+    t.Fatalf("Expected only task %d to be open, got %v", report.ID, open)
+  }
+  if open[0].Pomodoros != 3 || !open[0].Over() {
+    t.Errorf("Expected 3 pomodoros over the estimate, got %d", open[0].Pomodoros)
+  }
+
+  all, err := pomodoro.Tasks(ctx, config, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(all) != 2 || !all[1].Done() {
+    t.Errorf("Expected 2 tasks with the second done, got %v", all)
+  }
+
+  // Deleting a task unlinks its intervals
+  if err := pomodoro.DeleteTask(ctx, config, report.ID); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.GetTask(ctx, config, report.ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %q", pomodoro.ErrInvalidID, err)
+  }
+
+  list, err := pomodoro.List(ctx, config, pomodoro.Query{TaskID: report.ID})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 0 {
+    t.Errorf("Expected no intervals of the deleted task, got %d", len(list))
+  }
+  if _, id := config.CurrentTask(); id != 0 {
+    t.Errorf("Expected no current task, got %d", id)
+  }
+}
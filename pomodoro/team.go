@@ -0,0 +1,137 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+)
+
+// Member sums up the history of one user of a shared repository over a
+// period, with the interval they're on.
+type Member struct {
+	User string
+	// Current is the latest interval timed for the user, which may have
+	// ended, and is zero if they only entered intervals manually.
+	Current Interval
+	Focus   time.Duration
+	Breaks  time.Duration
+	// Pomodoros counts the pomodoros done that started in the period.
+	Pomodoros int
+}
+
+// Busy reports whether the member is in a running or paused interval.
+func (m Member) Busy() bool {
+	return m.Current.State == StateRunning || m.Current.State == StatePaused
+}
+
+// Activity describes what the member is doing: "focusing", "on break",
+// "paused" or "idle".
+func (m Member) Activity() string {
+	switch {
+	case m.Current.State == StatePaused:
+		return "paused"
+	case m.Current.State != StateRunning:
+		return "idle"
+	case m.Current.Category == CategoryPomodoro:
+		return "focusing"
+	}
+
+	return "on break"
+}
+
+// teamLookBack is how far before a team summary users count as members,
+// so those idle over the period are listed too.
+const teamLookBack = 7 * 24 * time.Hour
+
+// TeamSummary sums up the intervals of every user of the repository
+// between start and end, within the configured filter, whatever the
+// configured user. Members are the users with intervals in the period or
+// the week before it, sorted by name. Intervals recorded before histories
+// were kept per user aren't anyone's, so they're left out.
+func TeamSummary(ctx context.Context, start, end time.Time,
+	config *IntervalConfig) ([]Member, error) {
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	list, err := config.repo.List(ctx, config.filter().apply(Query{
+		Start:          start.Add(-teamLookBack),
+		End:            end,
+		ExcludeManual:  config.ExcludeManual,
+		ExcludeUnowned: true,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	members := map[string]*Member{}
+	for _, i := range list {
+		m, ok := members[i.User]
+		if !ok {
+			m = &Member{User: i.User}
+			members[i.User] = m
+		}
+		m.add(i, start, end)
+	}
+
+	team := make([]Member, 0, len(members))
+	for user, m := range members {
+		m.Current, err = config.repo.Last(ctx, user)
+		if err != nil && !errors.Is(err, ErrNoIntervals) {
+			return nil, err
+		}
+
+		team = append(team, *m)
+	}
+
+	sort.Slice(team, func(a, b int) bool {
+		return team[a].User < team[b].User
+	})
+
+	return team, nil
+}
+
+// MemberSummary sums up the intervals of the configured user between
+// start and end, within the configured filter, like TeamSummary does for
+// every user. Current is zero if the user has no intervals.
+func MemberSummary(ctx context.Context, start, end time.Time,
+	config *IntervalConfig) (Member, error) {
+
+	m := Member{User: config.User}
+
+	list, err := List(ctx, config, config.filter().apply(Query{
+		Start:         start,
+		End:           end,
+		ExcludeManual: config.ExcludeManual,
+	}))
+	if err != nil {
+		return m, err
+	}
+
+	for _, i := range list {
+		m.add(i, start, end)
+	}
+
+	m.Current, err = Current(ctx, config)
+	if err != nil && !errors.Is(err, ErrNoIntervals) {
+		return m, err
+	}
+
+	return m, nil
+}
+
+// add counts the part of interval i between start and end.
+func (m *Member) add(i Interval, start, end time.Time) {
+	d := Query{Start: start, End: end}.Duration(i)
+	if i.Category != CategoryPomodoro {
+		m.Breaks += d
+		return
+	}
+
+	m.Focus += d
+	if i.State == StateDone && !i.StartTime.Before(start) &&
+		i.StartTime.Before(end) {
+		m.Pomodoros++
+	}
+}
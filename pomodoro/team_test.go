@@ -0,0 +1,396 @@
+package pomodoro_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// timedPomodoro records a pomodoro of config done with the timer at
+// start, which the timer then follows.
+func timedPomodoro(t *testing.T, config *pomodoro.IntervalConfig,
+  start time.Time) pomodoro.Interval {
+
+  t.Helper()
+
+  ctx := context.Background()
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  i, err = pomodoro.Edit(ctx, config, i.ID, func(i *pomodoro.Interval) {
+    i.StartTime = start
+    i.Category = pomodoro.CategoryPomodoro
+    i.PlannedDuration = config.PomodoroDuration
+    i.ActualDuration = config.PomodoroDuration
+    i.State = pomodoro.StateDone
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  return i
+}
+
+func TestUserScope(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  alice := pomodoro.NewConfig(repo, 0, 0, 0)
+  alice.User = "alice"
+  bob := pomodoro.NewConfig(repo, 0, 0, 0)
+  bob.User = "bob"
+
+  // Recorded before histories were kept per user
+  legacy, err := pomodoro.AddManual(ctx, pomodoro.NewConfig(repo, 0, 0, 0),
+    pomodoro.Interval{
+      StartTime:      time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+    })
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+  mine := timedPomodoro(t, alice, start)
+  if mine.User != "alice" {
+    t.Errorf("Expected the interval of alice, got %q", mine.User)
+  }
+
+  // Teammates may work at the same time
+  timedPomodoro(t, bob, start)
+
+  list, err := pomodoro.List(ctx, alice, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 2 || list[0].ID != legacy.ID || list[1].ID != mine.ID {
+    t.Errorf("Expected the legacy interval and the one of alice, got %v",
+      list)
+  }
+
+  // Bob's next interval follows his own pomodoro
+  i, err := pomodoro.GetInterval(ctx, bob)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.Category != pomodoro.CategoryShortBreak || i.User != "bob" {
+    t.Errorf("Expected a short break of bob, got %+v", i)
+  }
+
+  if _, err := pomodoro.Get(ctx, alice, i.ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+  if err := pomodoro.Delete(ctx, alice, i.ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+
+  task, err := pomodoro.AddTask(ctx, bob, "review", 2)
+  if err != nil {
+    t.Fatal(err)
+  }
+  shared, err := pomodoro.AddTask(ctx, pomodoro.NewConfig(repo, 0, 0, 0),
+    "plan", 1)
+  if err != nil {
+    t.Fatal(err)
+  }
+  tasks, err := pomodoro.Tasks(ctx, alice, true)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(tasks) != 1 || tasks[0].ID != shared.ID {
+    t.Errorf("Expected the legacy task for alice, got %v", tasks)
+  }
+
+  if _, err := pomodoro.Edit(ctx, bob, i.ID, func(i *pomodoro.Interval) {
+    i.Note = "lunch"
+  }); err != nil {
+    t.Fatal(err)
+  }
+  changes, err := pomodoro.Changes(ctx, alice, i.ID)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(changes) != 0 {
+    t.Errorf("Expected no changes for alice, got %v", changes)
+  }
+  if changes, err := pomodoro.Changes(ctx, bob, i.ID); err != nil ||
+    len(changes) != 1 {
+    t.Errorf("Expected the change of bob, got %v, %v", changes, err)
+  }
+  if _, err := pomodoro.CompleteTask(ctx, alice, task.ID,
+    true); !errors.Is(err, pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+
+  // Legacy intervals are shared for reading only, and the timer of alice
+  // doesn't pick them up
+  if _, err := pomodoro.Get(ctx, bob, legacy.ID); err != nil {
+    t.Errorf("Expected bob to read the legacy interval, got %v", err)
+  }
+  if err := pomodoro.Delete(ctx, bob, legacy.ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+  current, err := pomodoro.Current(ctx, alice)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if current.ID != mine.ID {
+    t.Errorf("Expected the interval of alice, got %+v", current)
+  }
+
+  if _, err := pomodoro.Adopt(ctx, pomodoro.NewConfig(repo, 0, 0,
+    0)); !errors.Is(err, pomodoro.ErrNoUser) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrNoUser, err)
+  }
+  n, err := pomodoro.Adopt(ctx, alice)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if n != 2 {
+    t.Errorf("Expected 1 interval and 1 task adopted, got %d", n)
+  }
+  if _, err := pomodoro.Get(ctx, bob, legacy.ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+  if err := pomodoro.Delete(ctx, alice, legacy.ID); err != nil {
+    t.Errorf("Expected alice to delete the adopted interval, got %v", err)
+  }
+}
+
+func TestLegacyIntervals(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  alice := pomodoro.NewConfig(repo, 0, 0, 0)
+  alice.User = "alice"
+  bob := pomodoro.NewConfig(repo, 0, 0, 0)
+  bob.User = "bob"
+
+  // History recorded before histories were kept per user
+  legacy := []pomodoro.Interval{}
+  for k := 0; k < 3; k++ {
+    i, err := pomodoro.AddManual(ctx, pomodoro.NewConfig(repo, 0, 0, 0),
+      pomodoro.Interval{
+        StartTime:      time.Date(2026, 10, 1, 9+k, 0, 0, 0, time.UTC),
+        ActualDuration: 25 * time.Minute,
+        Category:       pomodoro.CategoryPomodoro,
+      })
+    if err != nil {
+      t.Fatal(err)
+    }
+    legacy = append(legacy, i)
+  }
+
+  // Bob can't change them while alice may have recorded them too
+  task, err := pomodoro.AddTask(ctx, bob, "review", 1)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.Edit(ctx, alice, legacy[0].ID,
+    func(i *pomodoro.Interval) {
+      i.ActualDuration = 20 * time.Minute
+    }); !errors.Is(err, pomodoro.ErrInvalidID) {
+    t.Errorf("Expected error %q, got %v", pomodoro.ErrInvalidID, err)
+  }
+  list, err := pomodoro.ResetList(ctx, alice, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(list) != 0 {
+    t.Errorf("Expected nothing to reset for alice, got %v", list)
+  }
+
+  // Alone, alice adopts them on the first change
+  if err := pomodoro.DeleteTask(ctx, bob, task.ID); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.Edit(ctx, alice, legacy[0].ID,
+    func(i *pomodoro.Interval) {
+      i.ActualDuration = 20 * time.Minute
+    }); err != nil {
+    t.Fatal(err)
+  }
+  i, err := pomodoro.Get(ctx, alice, legacy[0].ID)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.User != "alice" || i.ActualDuration != 20*time.Minute {
+    t.Errorf("Expected the interval edited and adopted by alice, got %+v", i)
+  }
+  if _, err := pomodoro.Get(ctx, bob, legacy[2].ID); !errors.Is(err,
+    pomodoro.ErrInvalidID) {
+    t.Errorf("Expected every interval adopted, got %v", err)
+  }
+
+  if err := pomodoro.Delete(ctx, alice, legacy[1].ID); err != nil {
+    t.Fatal(err)
+  }
+
+  archive, cleanupArchive := getRepo(t)
+  defer cleanupArchive()
+
+  deleted, err := pomodoro.Reset(ctx, alice, pomodoro.Query{}, archive)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(deleted) != 2 {
+    t.Errorf("Expected 2 intervals reset, got %v", deleted)
+  }
+  archived, err := archive.List(ctx, pomodoro.Query{})
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(archived) != 2 {
+    t.Errorf("Expected 2 intervals archived, got %v", archived)
+  }
+}
+
+func TestTeamSummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+  users := []struct {
+    name      string
+    pomodoros int
+  }{
+    {"bob", 1},
+    {"alice", 3},
+    // Only worked the day before
+    {"carol", 0},
+  }
+
+  for _, u := range users {
+    config := pomodoro.NewConfig(repo, 0, 0, 0)
+    config.User = u.name
+
+    day := start
+    if u.pomodoros == 0 {
+      day = start.AddDate(0, 0, -1)
+    }
+    for k := 0; k < u.pomodoros || k == 0; k++ {
+      timedPomodoro(t, config, day.Add(time.Duration(9+k)*time.Hour))
+    }
+  }
+
+  // Recorded before histories were kept per user
+  if _, err := pomodoro.AddManual(ctx, pomodoro.NewConfig(repo, 0, 0, 0),
+    pomodoro.Interval{
+      StartTime:      start.Add(12 * time.Hour),
+      ActualDuration: 25 * time.Minute,
+      Category:       pomodoro.CategoryPomodoro,
+    }); err != nil {
+    t.Fatal(err)
+  }
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.User = "bob"
+
+  // Bob is on a break
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if _, err := pomodoro.Edit(ctx, config, i.ID, func(i *pomodoro.Interval) {
+    i.StartTime = start.Add(10 * time.Hour)
+    i.State = pomodoro.StatePaused
+    i.ActualDuration = 2 * time.Minute
+  }); err != nil {
+    t.Fatal(err)
+  }
+
+  team, err := pomodoro.TeamSummary(ctx, start, start.AddDate(0, 0, 1), config)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  exp := []struct {
+    user      string
+    pomodoros int
+    focus     time.Duration
+    activity  string
+  }{
+    {"alice", 3, 75 * time.Minute, "idle"},
+    {"bob", 1, 25 * time.Minute, "paused"},
+    {"carol", 0, 0, "idle"},
+  }
+
+  if len(team) != len(exp) {
+    t.Fatalf("Expected %d members, got %+v", len(exp), team)
+  }
+  for k, e := range exp {
+    m := team[k]
+    if m.User != e.user || m.Pomodoros != e.pomodoros || m.Focus != e.focus ||
+      m.Activity() != e.activity {
+      t.Errorf("Expected %+v, got %s: %d pomodoros, %s, %s", e, m.User,
+        m.Pomodoros, m.Focus, m.Activity())
+    }
+  }
+
+  if !team[1].Busy() || team[1].Current.Remaining() != 3*time.Minute ||
+    team[1].Breaks != 2*time.Minute {
+    t.Errorf("Expected bob busy with 3m left on a 2m break, got %+v", team[1])
+  }
+}
+
+func TestMemberSummary(t *testing.T) {
+  repo, cleanup := getRepo(t)
+  defer cleanup()
+
+  ctx := context.Background()
+  start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+  end := start.AddDate(0, 0, 1)
+
+  config := pomodoro.NewConfig(repo, 0, 0, 0)
+  config.User = "alice"
+
+  m, err := pomodoro.MemberSummary(ctx, start, end, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if m.User != "alice" || m.Busy() || m.Activity() != "idle" {
+    t.Errorf("Expected alice idle without intervals, got %+v", m)
+  }
+
+  bob := pomodoro.NewConfig(repo, 0, 0, 0)
+  bob.User = "bob"
+  for _, c := range []*pomodoro.IntervalConfig{config, bob} {
+    timedPomodoro(t, c, start.Add(9*time.Hour))
+  }
+
+  // Alice's timer runs her break
+  i, err := pomodoro.GetInterval(ctx, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  i.StartTime = start.Add(10 * time.Hour)
+  i.State = pomodoro.StateRunning
+  i.ActualDuration = time.Minute
+  if err := repo.Update(ctx, i); err != nil {
+    t.Fatal(err)
+  }
+
+  m, err = pomodoro.MemberSummary(ctx, start, end, config)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if m.Pomodoros != 1 || m.Focus != 25*time.Minute ||
+    m.Breaks != time.Minute || m.Activity() != "on break" ||
+    m.Current.Remaining() != 4*time.Minute {
+    t.Errorf("Expected alice on break with 4m left after 1 pomodoro, got %+v",
+      m)
+  }
+}
@@ -0,0 +1,268 @@
+package pomodoro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNoUser is returned when adopting intervals without a user to adopt
+// them.
+var ErrNoUser = errors.New("No user")
+
+// userRepo limits a repository shared by several users to the history of
+// one of them. New intervals, tasks and changes are recorded as theirs,
+// and those of other users can't be read or changed. Intervals and tasks
+// without a user, recorded before histories were kept per user, can be
+// read by all but only changed once adopted. The user adopts them on the
+// first change while nobody else shares the repository.
+type userRepo struct {
+	Repository
+	user string
+}
+
+// store returns the repository limited to the configured user.
+func (c *IntervalConfig) store() Repository {
+	if c.User == "" {
+		return c.repo
+	}
+
+	return userRepo{Repository: c.repo, user: c.User}
+}
+
+// sees reports whether the user of r can read an interval or task of
+// owner.
+func (r userRepo) sees(owner string) bool {
+	return owner == "" || owner == r.user
+}
+
+// mine returns an error unless the user of r owns the interval or task id
+// of owner, so it can be changed.
+func (r userRepo) mine(ctx context.Context, owner, kind string,
+	id int64) error {
+
+	switch owner {
+	case r.user:
+		return nil
+	case "":
+		alone, err := adoptAlone(ctx, r.Repository, r.user)
+		if err != nil || alone {
+			return err
+		}
+
+		return fmt.Errorf("%w: %s %d has no user, adopt it first", ErrInvalidID,
+			kind, id)
+	}
+
+	return fmt.Errorf("%w: %s %d", ErrInvalidID, kind, id)
+}
+
+func (r userRepo) Create(ctx context.Context, i Interval) (int64, error) {
+	i.User = r.user
+	return r.Repository.Create(ctx, i)
+}
+
+// Update records the interval as the user's, since the interval given may
+// have been created without one.
+func (r userRepo) Update(ctx context.Context, i Interval,
+	changes ...Change) error {
+
+	old, err := r.ByID(ctx, i.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.mine(ctx, old.User, "interval", i.ID); err != nil {
+		return err
+	}
+	i.User = r.user
+
+	return r.Repository.Update(ctx, i, r.sign(changes)...)
+}
+
+func (r userRepo) ByID(ctx context.Context, id int64) (Interval, error) {
+	i, err := r.Repository.ByID(ctx, id)
+	if err == nil && !r.sees(i.User) {
+		return Interval{}, fmt.Errorf("%w: %d", ErrInvalidID, id)
+	}
+
+	return i, err
+}
+
+func (r userRepo) List(ctx context.Context, q Query) ([]Interval, error) {
+	q.User = r.user
+	return r.Repository.List(ctx, q)
+}
+
+func (r userRepo) Delete(ctx context.Context, id int64,
+	changes ...Change) error {
+
+	i, err := r.ByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.mine(ctx, i.User, "interval", id); err != nil {
+		return err
+	}
+
+	return r.Repository.Delete(ctx, id, r.sign(changes)...)
+}
+
+// sign records changes as made by the user.
+func (r userRepo) sign(changes []Change) []Change {
+	signed := make([]Change, len(changes))
+	for k, c := range changes {
+		c.User = r.user
+		signed[k] = c
+	}
+
+	return signed
+}
+
+func (r userRepo) Changes(ctx context.Context, id int64,
+	user string) ([]Change, error) {
+
+	return r.Repository.Changes(ctx, id, r.user)
+}
+
+func (r userRepo) CategorySummary(ctx context.Context,
+	q Query) (time.Duration, error) {
+
+	q.User = r.user
+	return r.Repository.CategorySummary(ctx, q)
+}
+
+func (r userRepo) Totals(ctx context.Context, user string) (Totals,
+	error) {
+
+	return r.Repository.Totals(ctx, r.user)
+}
+
+func (r userRepo) CreateTask(ctx context.Context, t Task) (int64, error) {
+	t.User = r.user
+	return r.Repository.CreateTask(ctx, t)
+}
+
+func (r userRepo) UpdateTask(ctx context.Context, t Task) error {
+	old, err := r.TaskByID(ctx, t.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.mine(ctx, old.User, "task", t.ID); err != nil {
+		return err
+	}
+	t.User = r.user
+
+	return r.Repository.UpdateTask(ctx, t)
+}
+
+func (r userRepo) TaskByID(ctx context.Context, id int64) (Task, error) {
+	t, err := r.Repository.TaskByID(ctx, id)
+	if err == nil && !r.sees(t.User) {
+		return Task{}, fmt.Errorf("%w: task %d", ErrInvalidID, id)
+	}
+
+	return t, err
+}
+
+func (r userRepo) DeleteTask(ctx context.Context, id int64) error {
+	t, err := r.TaskByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := r.mine(ctx, t.User, "task", id); err != nil {
+		return err
+	}
+
+	return r.Repository.DeleteTask(ctx, id)
+}
+
+func (r userRepo) Tasks(ctx context.Context, user string) ([]Task, error) {
+	return r.Repository.Tasks(ctx, r.user)
+}
+
+// Adopt makes the configured user the owner of the intervals and tasks
+// recorded before histories were kept per user, so they can be changed,
+// and returns how many were adopted.
+func Adopt(ctx context.Context, config *IntervalConfig) (int, error) {
+	if config.User == "" {
+		return 0, ErrNoUser
+	}
+
+	ctx, cancel := config.withTimeout(ctx)
+	defer cancel()
+
+	return adopt(ctx, config.repo, config.User)
+}
+
+// alone reports whether nobody but user has intervals or tasks in repo,
+// so those without a user can only be theirs.
+func alone(ctx context.Context, repo Repository, user string) (bool,
+	error) {
+
+	users, err := repo.Users(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	for _, u := range users {
+		if u != "" && u != user {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// adoptAlone adopts the intervals and tasks without a user for user if
+// they're alone in repo, and reports whether it did.
+func adoptAlone(ctx context.Context, repo Repository,
+	user string) (bool, error) {
+
+	ok, err := alone(ctx, repo, user)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	_, err = adopt(ctx, repo, user)
+	return err == nil, err
+}
+
+func adopt(ctx context.Context, repo Repository, user string) (int, error) {
+	list, err := repo.List(ctx, Query{})
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, i := range list {
+		if i.User != "" {
+			continue
+		}
+
+		i.User = user
+		if err := repo.Update(ctx, i); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	tasks, err := repo.Tasks(ctx, "")
+	if err != nil {
+		return n, err
+	}
+
+	for _, t := range tasks {
+		if t.User != "" {
+			continue
+		}
+
+		t.User = user
+		if err := repo.UpdateTask(ctx, t); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
@@ -0,0 +1,116 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"image/color"
+	"io"
+
+	"github.com/xasterKies/pomanalyzer/chart"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+var htmlReport = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; color: #222; }
+table { border-collapse: collapse; margin: 1rem 0; }
+th, td { padding: 0.25rem 0.75rem; border-bottom: 1px solid #ddd; text-align: left; }
+td.n { text-align: right; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<ul>
+{{- range .Summary}}
+<li><strong>{{.Label}}:</strong> {{.Value}}</li>
+{{- end}}
+</ul>
+{{.Chart}}
+<table>
+<tr><th>Day</th><th>Pomodoros</th><th>Focus</th></tr>
+{{- range .Days}}
+<tr><td>{{.Label}}</td><td class="n">{{.Pomodoros}}</td><td class="n">{{.Focus}}</td></tr>
+{{- end}}
+</table>
+{{- range .Totals}}
+{{- if .Rows}}
+<h2>{{.Heading}}</h2>
+<table>
+{{- range .Rows}}
+<tr><td>{{.Name}}</td><td class="n">{{.Focus}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// chartColor is the color of the bars of the chart.
+var chartColor = color.RGBA{0x4a, 0x7f, 0xd6, 0xff}
+
+func writeHTML(w io.Writer, r pomodoro.PeriodReport, o Options) error {
+	type item struct{ Label, Value string }
+	type day struct {
+		Label     string
+		Pomodoros int
+		Focus     string
+	}
+	type totals struct {
+		Heading string
+		Rows    []pomodoro.GroupTotal
+	}
+
+	data := struct {
+		Title   string
+		Summary []item
+		Chart   template.HTML
+		Days    []day
+		Totals  []totals
+	}{
+		Title: title(r, o),
+		Chart: template.HTML(svgChart(r.Days)),
+		Totals: []totals{
+			{"Top tasks", top(r.Tasks, o.Top, "", "(no task)")},
+			{"Top tags", top(r.Tags, o.Top, "#", "(no tag)")},
+		},
+	}
+
+	for _, l := range summary(r, o) {
+		data.Summary = append(data.Summary, item{l.label, l.value})
+	}
+	for _, d := range r.Days {
+		data.Days = append(data.Days, day{dayLabel(d), d.Pomodoros,
+			d.Focus.String()})
+	}
+
+	return htmlReport.Execute(w, data)
+}
+
+// svgChart draws the focus time of each day as an SVG bar chart, in
+// hours.
+func svgChart(days []pomodoro.DayTotal) string {
+	c := chart.Chart{
+		Kind:       chart.Bar,
+		ShowValues: true,
+		Format: func(v float64) string {
+			return fmt.Sprintf("%.1fh", v)
+		},
+		Height: 240,
+	}
+
+	focus := chart.Series{Name: "Focus", Color: chartColor}
+	for _, d := range days {
+		c.Labels = append(c.Labels, fmt.Sprint(d.Day.Day()))
+		focus.Values = append(focus.Values, d.Focus.Hours())
+		focus.Tips = append(focus.Tips, fmt.Sprintf("%s: %s", dayLabel(d),
+			d.Focus))
+	}
+	c.Series = []chart.Series{focus}
+
+	return c.SVG()
+}
@@ -0,0 +1,183 @@
+// Package report writes weekly and monthly summaries of the pomodoro
+// history as text, Markdown or HTML, to paste into status updates.
+package report
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+// Formats of the reports.
+const (
+	FormatText     = "txt"
+	FormatMarkdown = "md"
+	FormatHTML     = "html"
+)
+
+// Periods covered by the reports.
+const (
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+var (
+	ErrUnknownFormat = errors.New("Unknown report format")
+	ErrUnknownPeriod = errors.New("Unknown report period")
+)
+
+// ParseFormat checks a format name given by the user, such as "markdown".
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "txt", "text":
+		return FormatText, nil
+	case "md", "markdown":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	}
+
+	return "", fmt.Errorf("%w: %q, use txt, md or html", ErrUnknownFormat,
+		name)
+}
+
+// Period returns the first day and the number of days of period around
+// day: its week, from Monday, or its month.
+func Period(period string, day time.Time) (time.Time, int, error) {
+	switch period {
+	case PeriodWeek:
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		return monday, 7, nil
+	case PeriodMonth:
+		first := day.AddDate(0, 0, 1-day.Day())
+		return first, first.AddDate(0, 1, -1).Day(), nil
+	}
+
+	return day, 0, fmt.Errorf("%w: %q, use week or month", ErrUnknownPeriod,
+		period)
+}
+
+// Options are the settings of a report.
+type Options struct {
+	Period string
+	Goals  settings.Goals
+	// Now is when the report is made. Days after it don't count toward
+	// the daily goal.
+	Now time.Time
+	// Top is the number of tasks and tags listed.
+	Top int
+}
+
+// Write writes report r to w in format.
+func Write(w io.Writer, format string, r pomodoro.PeriodReport,
+	o Options) error {
+
+	if o.Top <= 0 {
+		o.Top = 5
+	}
+
+	switch format {
+	case FormatText:
+		return writeText(w, r, o)
+	case FormatMarkdown:
+		return writeMarkdown(w, r, o)
+	case FormatHTML:
+		return writeHTML(w, r, o)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// title names the report and its period.
+func title(r pomodoro.PeriodReport, o Options) string {
+	name := "Weekly"
+	if o.Period == PeriodMonth {
+		name = "Monthly"
+	}
+
+	last := r.Start
+	if len(r.Days) > 0 {
+		last = r.Days[len(r.Days)-1].Day
+	}
+
+	return fmt.Sprintf("%s focus report, %s to %s", name,
+		r.Start.Format("Mon 2 Jan 2006"), last.Format("Mon 2 Jan 2006"))
+}
+
+// line is a labelled figure of the summary.
+type line struct {
+	label string
+	value string
+}
+
+// summary returns the totals, completion rate and goal attainment of r.
+func summary(r pomodoro.PeriodReport, o Options) []line {
+	lines := []line{
+		{"Focus", fmt.Sprintf("%s in %d pomodoros", r.Focus, r.Done)},
+		{"Breaks", r.Breaks.String()},
+		{"Completion", fmt.Sprintf("%d of %d pomodoros finished (%s)", r.Done,
+			r.Done+r.Cancelled, percent(r.CompletionRate()))},
+	}
+
+	if o.Goals.Daily > 0 {
+		met, elapsed := 0, 0
+		for _, d := range r.Days {
+			if d.Day.After(o.Now) {
+				continue
+			}
+
+			elapsed++
+			if d.Pomodoros >= o.Goals.Daily {
+				met++
+			}
+		}
+
+		lines = append(lines, line{"Daily goal", fmt.Sprintf(
+			"%d of %d days at %d pomodoros", met, elapsed, o.Goals.Daily)})
+	}
+
+	if o.Goals.Weekly > 0 {
+		// The weekly goal is prorated over the days of the period
+		target := int(math.Round(float64(o.Goals.Weekly*len(r.Days)) / 7))
+		lines = append(lines, line{"Weekly goal", fmt.Sprintf(
+			"%d of %d pomodoros (%s)", r.Done, target,
+			percent(float64(r.Done)/float64(target)))})
+	}
+
+	return lines
+}
+
+func percent(f float64) string {
+	return fmt.Sprintf("%.0f%%", f*100)
+}
+
+// top returns the first n totals, naming the empty one none.
+func top(totals []pomodoro.GroupTotal, n int, prefix,
+	none string) []pomodoro.GroupTotal {
+
+	if len(totals) > n {
+		totals = totals[:n]
+	}
+
+	named := make([]pomodoro.GroupTotal, len(totals))
+	for k, t := range totals {
+		named[k] = t
+		named[k].Name = prefix + t.Name
+		if t.Name == "" {
+			named[k].Name = none
+		}
+	}
+
+	return named
+}
+
+// dayLabel is the short name of a day in the tables.
+func dayLabel(d pomodoro.DayTotal) string {
+	return d.Day.Format("Mon 2 Jan")
+}
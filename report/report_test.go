@@ -0,0 +1,117 @@
+package report_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/report"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+func TestPeriod(t *testing.T) {
+	day := time.Date(2026, 10, 15, 14, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		period   string
+		expStart time.Time
+		expDays  int
+	}{
+		{report.PeriodWeek, time.Date(2026, 10, 12, 14, 0, 0, 0, time.UTC), 7},
+		{report.PeriodMonth, time.Date(2026, 10, 1, 14, 0, 0, 0, time.UTC), 31},
+	}
+
+	for _, tc := range testCases {
+		start, n, err := report.Period(tc.period, day)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !start.Equal(tc.expStart) || n != tc.expDays {
+			t.Errorf("Expected %d days from %s, got %d from %s", tc.expDays,
+				tc.expStart, n, start)
+		}
+	}
+
+	if _, _, err := report.Period("year", day); !errors.Is(err,
+		report.ErrUnknownPeriod) {
+		t.Errorf("Expected error %q, got %v", report.ErrUnknownPeriod, err)
+	}
+	if _, err := report.ParseFormat("pdf"); !errors.Is(err,
+		report.ErrUnknownFormat) {
+		t.Errorf("Expected error %q, got %v", report.ErrUnknownFormat, err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	monday := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	r := pomodoro.PeriodReport{
+		Start: monday, End: monday.AddDate(0, 0, 7),
+		Focus: 3 * time.Hour, Breaks: 30 * time.Minute, Done: 7, Cancelled: 1,
+		Tasks: []pomodoro.GroupTotal{
+			{Name: "<script>report</script>", Focus: 2 * time.Hour},
+			{Name: "", Focus: time.Hour},
+		},
+		Tags: []pomodoro.GroupTotal{{Name: "writing", Focus: 2 * time.Hour}},
+	}
+	for k := 0; k < 7; k++ {
+		r.Days = append(r.Days, pomodoro.DayTotal{Day: monday.AddDate(0, 0, k)})
+	}
+	r.Days[0].Focus, r.Days[0].Pomodoros = 2*time.Hour, 5
+	r.Days[2].Focus, r.Days[2].Pomodoros = time.Hour, 2
+
+	o := report.Options{
+		Period: report.PeriodWeek,
+		Goals:  settings.Goals{Daily: 4, Weekly: 20},
+		Now:    monday.Add(2*24*time.Hour + 12*time.Hour),
+	}
+
+	testCases := []struct {
+		format string
+		exp    []string
+	}{
+		{report.FormatText, []string{
+			"Weekly focus report, Mon 12 Oct 2026 to Sun 18 Oct 2026\n====",
+			"Completion   7 of 8 pomodoros finished (88%)\n",
+			"Daily goal   1 of 3 days at 4 pomodoros\n",
+			"Weekly goal  7 of 20 pomodoros (35%)\n",
+			"Mon 12 Oct  5          2h0m0s  " + strings.Repeat("█", 30) + "\n",
+			"Wed 14 Oct  2          1h0m0s  " + strings.Repeat("█", 15) + "\n",
+			"  (no task)                1h0m0s\n",
+			"  #writing  2h0m0s\n",
+		}},
+		{report.FormatMarkdown, []string{
+			"## Weekly focus report, Mon 12 Oct 2026 to Sun 18 Oct 2026\n",
+			"- **Focus:** 3h0m0s in 7 pomodoros\n",
+			"| Mon 12 Oct | 5 | 2h0m0s |\n",
+			"```text\nDAY",
+			"| &lt;script>report&lt;/script> | 2h0m0s |\n",
+			"| \\#writing | 2h0m0s |\n",
+		}},
+		{report.FormatHTML, []string{
+			"<h1>Weekly focus report, Mon 12 Oct 2026 to Sun 18 Oct 2026</h1>",
+			"<li><strong>Weekly goal:</strong> 7 of 20 pomodoros (35%)</li>",
+			`<svg xmlns="http://www.w3.org/2000/svg"`,
+			"<title>Mon 12 Oct: 2h0m0s</title>",
+			">2.0h</text>",
+			"<td>&lt;script&gt;report&lt;/script&gt;</td>",
+		}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := report.Write(&out, tc.format, r, o); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, exp := range tc.exp {
+				if !strings.Contains(out.String(), exp) {
+					t.Errorf("Expected %q in:\n%s", exp, out.String())
+				}
+			}
+		})
+	}
+}
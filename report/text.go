@@ -0,0 +1,131 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// barWidth is the width of the longest bar of the text charts.
+const barWidth = 30
+
+// bar draws d as a bar of blocks, scaled so max is barWidth long.
+func bar(d, max time.Duration) string {
+	if max <= 0 || d <= 0 {
+		return ""
+	}
+
+	n := int(float64(barWidth)*float64(d)/float64(max) + 0.5)
+	if n == 0 {
+		n = 1
+	}
+
+	return strings.Repeat("█", n)
+}
+
+func maxFocus(days []pomodoro.DayTotal) time.Duration {
+	var max time.Duration
+	for _, d := range days {
+		if d.Focus > max {
+			max = d.Focus
+		}
+	}
+
+	return max
+}
+
+func writeText(w io.Writer, r pomodoro.PeriodReport, o Options) error {
+	t := title(r, o)
+	fmt.Fprintf(w, "%s\n%s\n\n", t, strings.Repeat("=", len([]rune(t))))
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, l := range summary(r, o) {
+		fmt.Fprintf(tw, "%s\t%s\n", l.label, l.value)
+	}
+	tw.Flush()
+
+	fmt.Fprint(w, "\n")
+	if err := writeDays(w, r); err != nil {
+		return err
+	}
+
+	writeTotals(w, "Top tasks", top(r.Tasks, o.Top, "", "(no task)"))
+	writeTotals(w, "Top tags", top(r.Tags, o.Top, "#", "(no tag)"))
+
+	return nil
+}
+
+// writeDays writes the day by day table, with a bar chart of the focus
+// time.
+func writeDays(w io.Writer, r pomodoro.PeriodReport) error {
+	max := maxFocus(r.Days)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DAY\tPOMODOROS\tFOCUS\t")
+	for _, d := range r.Days {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\n", dayLabel(d), d.Pomodoros, d.Focus,
+			bar(d.Focus, max))
+	}
+
+	return tw.Flush()
+}
+
+func writeTotals(w io.Writer, heading string, totals []pomodoro.GroupTotal) {
+	if len(totals) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s\n", heading)
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	for _, t := range totals {
+		fmt.Fprintf(tw, "  %s\t%s\n", t.Name, t.Focus)
+	}
+	tw.Flush()
+}
+
+func writeMarkdown(w io.Writer, r pomodoro.PeriodReport, o Options) error {
+	fmt.Fprintf(w, "## %s\n\n", mdEscape(title(r, o)))
+
+	for _, l := range summary(r, o) {
+		fmt.Fprintf(w, "- **%s:** %s\n", l.label, l.value)
+	}
+
+	fmt.Fprint(w, "\n| Day | Pomodoros | Focus |\n|---|---:|---:|\n")
+	for _, d := range r.Days {
+		fmt.Fprintf(w, "| %s | %d | %s |\n", dayLabel(d), d.Pomodoros, d.Focus)
+	}
+
+	// The chart keeps its alignment in a code block
+	fmt.Fprint(w, "\n```text\n")
+	if err := writeDays(w, r); err != nil {
+		return err
+	}
+	fmt.Fprint(w, "```\n")
+
+	mdTotals(w, "Top tasks", top(r.Tasks, o.Top, "", "(no task)"))
+	mdTotals(w, "Top tags", top(r.Tags, o.Top, "#", "(no tag)"))
+
+	return nil
+}
+
+func mdTotals(w io.Writer, heading string, totals []pomodoro.GroupTotal) {
+	if len(totals) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n### %s\n\n| Name | Focus |\n|---|---:|\n", heading)
+	for _, t := range totals {
+		fmt.Fprintf(w, "| %s | %s |\n", mdEscape(t.Name), t.Focus)
+	}
+}
+
+// mdEscape escapes the characters that would format text or break a
+// table.
+func mdEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "|", `\|`, "*", `\*`, "_", `\_`,
+		"`", "\\`", "#", `\#`, "<", "&lt;").Replace(s)
+}
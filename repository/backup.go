@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+var (
+	// ErrNoBackup is returned for backends that can't be backed up.
+	ErrNoBackup = errors.New("Backend doesn't support backups")
+	// ErrCorruptBackup is returned when restoring a backup that fails the
+	// integrity check.
+	ErrCorruptBackup = errors.New("Backup is corrupt")
+)
+
+// Backuper is implemented by repositories that can copy their database to
+// a file and replace it from one while open.
+type Backuper interface {
+	// Backup writes a consistent copy of the database to path, which must
+	// not exist.
+	Backup(ctx context.Context, path string) error
+	// Restore checks the backup at path and replaces the database with it.
+	Restore(ctx context.Context, path string) error
+}
+
+// Backup file names hold their creation time, so they sort by age.
+const (
+	backupPrefix = "pomo-"
+	backupSuffix = ".db"
+	backupLayout = "20060102-150405.000"
+)
+
+// Backup writes a timestamped backup of repo to dir and returns its path.
+// Only the keep latest backups are kept in dir, all of them if keep is 0.
+func Backup(ctx context.Context, repo pomodoro.Repository, dir string,
+	keep int) (string, error) {
+
+	b, ok := repo.(Backuper)
+	if !ok {
+		return "", ErrNoBackup
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir,
+		backupPrefix+time.Now().UTC().Format(backupLayout)+backupSuffix)
+
+	if err := b.Backup(ctx, path); err != nil {
+		os.Remove(path)
+		return "", err
+	}
+
+	return path, Rotate(dir, keep)
+}
+
+// Restore replaces the database of repo with the backup at path.
+func Restore(ctx context.Context, repo pomodoro.Repository, path string) error {
+	b, ok := repo.(Backuper)
+	if !ok {
+		return ErrNoBackup
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+
+	return b.Restore(ctx, path)
+}
+
+// Backups returns the paths of the backups in dir, oldest first. A missing
+// dir has no backups.
+func Backups(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := []string{}
+	for _, e := range entries {
+		if _, ok := backupTime(e.Name()); ok && !e.IsDir() {
+			list = append(list, filepath.Join(dir, e.Name()))
+		}
+	}
+	sort.Strings(list)
+
+	return list, nil
+}
+
+// BackupTime returns the time the backup at path was made, from its name.
+func BackupTime(path string) (time.Time, error) {
+	t, ok := backupTime(filepath.Base(path))
+	if !ok {
+		return t, fmt.Errorf("%s is not a backup file name", path)
+	}
+
+	return t, nil
+}
+
+func backupTime(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, backupPrefix) ||
+		!strings.HasSuffix(name, backupSuffix) {
+		return time.Time{}, false
+	}
+
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix),
+		backupSuffix)
+	t, err := time.Parse(backupLayout, stamp)
+	return t, err == nil
+}
+
+// Rotate removes the oldest backups in dir, keeping the keep latest, all of
+// them if keep is 0.
+func Rotate(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+
+	list, err := Backups(dir)
+	if err != nil {
+		return err
+	}
+
+	for len(list) > keep {
+		if err := os.Remove(list[0]); err != nil {
+			return err
+		}
+		list = list[1:]
+	}
+
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestBackupRestore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backupDir := filepath.Join(dir, "backups")
+
+	repo, err := repository.Open(filepath.Join(dir, "pomo.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func() {
+		if _, err := repo.Create(ctx, pomodoro.Interval{
+			StartTime:       time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+			PlannedDuration: 25 * time.Minute,
+			Category:        pomodoro.CategoryPomodoro,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func() int {
+		list, err := repo.List(ctx, pomodoro.Query{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(list)
+	}
+
+	create()
+	first, err := repository.Backup(ctx, repo, backupDir, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k := 0; k < 2; k++ {
+		time.Sleep(2 * time.Millisecond)
+		create()
+		if _, err := repository.Backup(ctx, repo, backupDir, 2); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := repository.Backups(backupDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0] == first {
+		t.Fatalf("Expected the 2 latest backups, got %v instead\n", list)
+	}
+
+	create()
+	if err := repository.Restore(ctx, repo, list[0]); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 2 {
+		t.Errorf("Expected 2 intervals after restore, got %d instead\n", n)
+	}
+
+	// The repository keeps working after the restore
+	create()
+	if n := count(); n != 3 {
+		t.Errorf("Expected 3 intervals, got %d instead\n", n)
+	}
+
+	corrupt := filepath.Join(dir, "corrupt.db")
+	if err := os.WriteFile(corrupt, []byte("not a database"),
+		0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.Restore(ctx, repo, corrupt); err == nil {
+		t.Error("Expected error restoring a corrupt backup")
+	}
+	if n := count(); n != 3 {
+		t.Errorf("Expected 3 intervals after failed restore, got %d\n", n)
+	}
+
+	// Characters that mean something in a URI are part of the file name
+	data, err := os.ReadFile(list[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	odd := filepath.Join(dir, "odd?#%20.db")
+	if err := os.WriteFile(odd, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := repository.Restore(ctx, repo, odd); err != nil {
+		t.Fatal(err)
+	}
+	if n := count(); n != 3 {
+		t.Errorf("Expected 3 intervals after restore, got %d instead\n", n)
+	}
+
+	memory, err := repository.Open("memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repository.Backup(ctx, memory, backupDir, 0)
+	if !errors.Is(err, repository.ErrNoBackup) {
+		t.Errorf("Expected error %q, got %q", repository.ErrNoBackup, err)
+	}
+}
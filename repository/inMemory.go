@@ -1,18 +1,27 @@
-//go:build inmemory
-// +build inmemory
-
 package repository
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/xasterKies/pomanalyzer/pomodoro"
 )
 
+func init() {
+	Register("memory", func(string) (pomodoro.Repository, error) {
+		return NewInMemoryRepo(), nil
+	})
+}
+
 type inMemoryRepo struct {
 	sync.RWMutex
-	intervals []pomodoro.Interval
+	intervals  []pomodoro.Interval
+	changes    []pomodoro.Change
+	lastID     int64
+	tasks      []pomodoro.Task
+	lastTaskID int64
 }
 
 func NewInMemoryRepo() *inMemoryRepo {
@@ -21,84 +30,235 @@ func NewInMemoryRepo() *inMemoryRepo {
 	}
 }
 
-func (r *inMemoryRepo) Create(i pomodoro.Interval) (int64, error) {
+func (r *inMemoryRepo) Create(ctx context.Context,
+	i pomodoro.Interval) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+
 	r.Lock()
 	defer r.Unlock()
 
-	i.ID = int64(len(r.intervals)) + 1
+	r.lastID++
+	i.ID = r.lastID
 
 	r.intervals = append(r.intervals, i)
 
 	return i.ID, nil
 }
 
-func (r *inMemoryRepo) Update(i pomodoro.Interval) error {
+func (r *inMemoryRepo) Update(ctx context.Context, i pomodoro.Interval,
+	changes ...pomodoro.Change) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	r.Lock()
 	defer r.Unlock()
-	if i.ID == 0 {
+	k, ok := findInterval(r.intervals, i.ID)
+	if !ok {
 		return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, i.ID)
 	}
 
-	r.intervals[i.ID-1] = i
+	r.intervals[k] = i
+	r.addChanges(changes)
 	return nil
 }
 
-func (r *inMemoryRepo) ByID(id int64) (pomodoro.Interval, error) {
+func (r *inMemoryRepo) ByID(ctx context.Context,
+	id int64) (pomodoro.Interval, error) {
+	if err := ctx.Err(); err != nil {
+		return pomodoro.Interval{}, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
 	i := pomodoro.Interval{}
-	if id == 0 {
+	k, ok := findInterval(r.intervals, id)
+	if !ok {
 		return i, fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
 	}
 
-	i = r.intervals[id-1]
+	i = r.intervals[k]
 	return i, nil
 }
 
-func (r *inMemoryRepo) Last() (pomodoro.Interval, error) {
+func (r *inMemoryRepo) Last(ctx context.Context,
+	user string) (pomodoro.Interval, error) {
+	if err := ctx.Err(); err != nil {
+		return pomodoro.Interval{}, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	return lastInterval(r.intervals, user)
+}
+
+func (r *inMemoryRepo) Breaks(ctx context.Context, user string,
+	n int) ([]pomodoro.Interval, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
-	i := pomodoro.Interval{}
-	if len(r.intervals) == 0 {
-		return i, pomodoro.ErrNoIntervals
+	return lastBreaks(r.intervals, user, n), nil
+}
+
+func (r *inMemoryRepo) List(ctx context.Context,
+	q pomodoro.Query) ([]pomodoro.Interval, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	return listIntervals(r.intervals, q), nil
+}
+
+func (r *inMemoryRepo) Delete(ctx context.Context, id int64,
+	changes ...pomodoro.Change) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	k, ok := findInterval(r.intervals, id)
+	if !ok {
+		return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+	}
+
+	r.intervals = append(r.intervals[:k], r.intervals[k+1:]...)
+	r.addChanges(changes)
+	return nil
+}
+
+// addChanges records changes in the audit trail. It must be called
+// holding the lock.
+func (r *inMemoryRepo) addChanges(changes []pomodoro.Change) {
+	for _, c := range changes {
+		c.ID = int64(len(r.changes)) + 1
+		r.changes = append(r.changes, c)
+	}
+}
+
+func (r *inMemoryRepo) Changes(ctx context.Context, id int64,
+	user string) ([]pomodoro.Change, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	return listChanges(r.changes, id, user), nil
+}
+
+func (r *inMemoryRepo) CategorySummary(ctx context.Context,
+	q pomodoro.Query) (time.Duration, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
 	}
 
-	return r.intervals[len(r.intervals)-1], nil
+	// Return a summary for the given period
+	r.RLock()
+	defer r.RUnlock()
+	return sumIntervals(r.intervals, q), nil
 }
 
-func (r *inMemoryRepo) Breaks(n int) ([]pomodoro.Interval, error) {
+func (r *inMemoryRepo) Totals(ctx context.Context,
+	user string) (pomodoro.Totals, error) {
+	if err := ctx.Err(); err != nil {
+		return pomodoro.Totals{}, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
-	data := []pomodoro.Interval{}
-	for k := len(r.intervals) - 1; k >= 0; k-- {
-		if r.intervals[k].Category == pomodoro.CategoryPomodoro {
-			continue
-		}
+	return totalIntervals(r.intervals, user), nil
+}
 
-		data = append(data, r.intervals[k])
+func (r *inMemoryRepo) Users(ctx context.Context) ([]string, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	return listUsers(r.intervals, r.tasks), nil
+}
 
-		if len(data) == n {
-			return data, nil
-		}
+func (r *inMemoryRepo) CreateTask(ctx context.Context,
+	t pomodoro.Task) (int64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
 	}
 
-	return data, nil
+	r.Lock()
+	defer r.Unlock()
+
+	r.lastTaskID++
+	t.ID = r.lastTaskID
+	r.tasks = append(r.tasks, t)
+
+	return t.ID, nil
 }
 
-func (r *inMemoryRepo) CategorySummary(day time.Time,
-	filter string) (time.Duration, error) {
-	// Return a daily summary
+func (r *inMemoryRepo) UpdateTask(ctx context.Context, t pomodoro.Task) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	k, ok := findTask(r.tasks, t.ID)
+	if !ok {
+		return fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, t.ID)
+	}
+
+	r.tasks[k] = t
+	return nil
+}
+
+func (r *inMemoryRepo) TaskByID(ctx context.Context,
+	id int64) (pomodoro.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return pomodoro.Task{}, err
+	}
+
+	r.RLock()
+	defer r.RUnlock()
+	k, ok := findTask(r.tasks, id)
+	if !ok {
+		return pomodoro.Task{}, fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID,
+			id)
+	}
+
+	return r.tasks[k], nil
+}
+
+func (r *inMemoryRepo) DeleteTask(ctx context.Context, id int64) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	k, ok := findTask(r.tasks, id)
+	if !ok {
+		return fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+	}
+
+	r.tasks = append(r.tasks[:k], r.tasks[k+1:]...)
+	return nil
+}
+
+func (r *inMemoryRepo) Tasks(ctx context.Context,
+	user string) ([]pomodoro.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	r.RLock()
 	defer r.RUnlock()
-	var d time.Duration
-	filter = strings.Trim(filter, "%")
-	for _, i := range r.intervals {
-		if i.StartTime.Year() == day.Year() &&
-			i.StartTime.YearDay() == day.YearDay() {
-			if strings.Contains(i.Category, filter) {
-				d += i.ActualDuration
-			}
-		}
-	}
-	return d, nil
+	return listTasks(r.tasks, user), nil
 }
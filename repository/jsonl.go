@@ -0,0 +1,835 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// compactThreshold is the number of superseded events the history file may
+// hold before it is rewritten with one line per interval.
+const compactThreshold = 1000
+
+func init() {
+	open := func(dsn string) (pomodoro.Repository, error) {
+		repo, err := NewJSONRepo(Path(dsn))
+		if err != nil {
+			return nil, err
+		}
+
+		return repo, nil
+	}
+
+	Register("json", open)
+	Register("jsonl", open)
+}
+
+// jsonDuration stores durations as readable strings such as "25m0s".
+type jsonDuration time.Duration
+
+func (d jsonDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *jsonDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+
+	*d = jsonDuration(v)
+	return nil
+}
+
+// jsonEvent is a single line of the history file, recording the state of
+// an interval after it was created or updated, or the ID of a deleted
+// interval.
+type jsonEvent struct {
+	Op              string       `json:"op"`
+	ID              int64        `json:"id"`
+	StartTime       time.Time    `json:"start_time"`
+	PlannedDuration jsonDuration `json:"planned_duration"`
+	ActualDuration  jsonDuration `json:"actual_duration"`
+	Category        string       `json:"category"`
+	State           int          `json:"state"`
+	Task            string       `json:"task,omitempty"`
+	Manual          bool         `json:"manual,omitempty"`
+	Profile         string       `json:"profile,omitempty"`
+	Note            string       `json:"note,omitempty"`
+	Internal        int          `json:"internal_interruptions,omitempty"`
+	External        int          `json:"external_interruptions,omitempty"`
+	TaskID          int64        `json:"task_id,omitempty"`
+	Project         string       `json:"project,omitempty"`
+	Tags            []string     `json:"tags,omitempty"`
+	User            string       `json:"user,omitempty"`
+}
+
+func newJSONEvent(op string, i pomodoro.Interval) jsonEvent {
+	return jsonEvent{
+		Op:              op,
+		ID:              i.ID,
+		StartTime:       i.StartTime,
+		PlannedDuration: jsonDuration(i.PlannedDuration),
+		ActualDuration:  jsonDuration(i.ActualDuration),
+		Category:        i.Category,
+		State:           i.State,
+		Task:            i.Task,
+		Manual:          i.Manual,
+		Profile:         i.Profile,
+		Note:            i.Note,
+		Internal:        i.InternalInterruptions,
+		External:        i.ExternalInterruptions,
+		TaskID:          i.TaskID,
+		Project:         i.Project,
+		Tags:            i.Tags,
+		User:            i.User,
+	}
+}
+
+// jsonDelete records that an interval was deleted.
+type jsonDelete struct {
+	Op string `json:"op"`
+	ID int64  `json:"id"`
+}
+
+// jsonIDs records the last IDs given. It's written last when the history
+// is compacted, so the IDs of deleted records aren't given again.
+type jsonIDs struct {
+	Op       string `json:"op"`
+	Interval int64  `json:"last_interval"`
+	Change   int64  `json:"last_change"`
+	Task     int64  `json:"last_task"`
+}
+
+// jsonChange records an entry of the audit trail.
+type jsonChange struct {
+	Op         string    `json:"op"`
+	ID         int64     `json:"id"`
+	IntervalID int64     `json:"interval_id"`
+	Time       time.Time `json:"time"`
+	Field      string    `json:"field"`
+	OldValue   string    `json:"old_value"`
+	NewValue   string    `json:"new_value"`
+	User       string    `json:"user,omitempty"`
+}
+
+func newJSONChange(c pomodoro.Change) jsonChange {
+	return jsonChange{
+		Op:         "change",
+		ID:         c.ID,
+		IntervalID: c.IntervalID,
+		Time:       c.Time,
+		Field:      c.Field,
+		OldValue:   c.OldValue,
+		NewValue:   c.NewValue,
+		User:       c.User,
+	}
+}
+
+func (c jsonChange) change() pomodoro.Change {
+	return pomodoro.Change{
+		ID:         c.ID,
+		IntervalID: c.IntervalID,
+		Time:       c.Time,
+		Field:      c.Field,
+		OldValue:   c.OldValue,
+		NewValue:   c.NewValue,
+		User:       c.User,
+	}
+}
+
+func (e jsonEvent) interval() pomodoro.Interval {
+	return pomodoro.Interval{
+		ID:                    e.ID,
+		StartTime:             e.StartTime,
+		PlannedDuration:       time.Duration(e.PlannedDuration),
+		ActualDuration:        time.Duration(e.ActualDuration),
+		Category:              e.Category,
+		State:                 e.State,
+		Task:                  e.Task,
+		Manual:                e.Manual,
+		Profile:               e.Profile,
+		Note:                  e.Note,
+		InternalInterruptions: e.Internal,
+		ExternalInterruptions: e.External,
+		TaskID:                e.TaskID,
+		Project:               e.Project,
+		Tags:                  e.Tags,
+		User:                  e.User,
+	}
+}
+
+// jsonTask records the state of a task after it was created or updated.
+type jsonTask struct {
+	Op         string    `json:"op"`
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Estimate   int       `json:"estimate,omitempty"`
+	Created    time.Time `json:"created"`
+	Completed  time.Time `json:"completed,omitempty"`
+	Source     string    `json:"source,omitempty"`
+	ExternalID string    `json:"external_id,omitempty"`
+	User       string    `json:"user,omitempty"`
+}
+
+func newJSONTask(t pomodoro.Task) jsonTask {
+	return jsonTask{
+		Op:         "task",
+		ID:         t.ID,
+		Name:       t.Name,
+		Estimate:   t.Estimate,
+		Created:    t.Created,
+		Completed:  t.Completed,
+		Source:     t.Source,
+		ExternalID: t.ExternalID,
+		User:       t.User,
+	}
+}
+
+func (t jsonTask) task() pomodoro.Task {
+	return pomodoro.Task{
+		ID:         t.ID,
+		Name:       t.Name,
+		Estimate:   t.Estimate,
+		Created:    t.Created,
+		Completed:  t.Completed,
+		Source:     t.Source,
+		ExternalID: t.ExternalID,
+		User:       t.User,
+	}
+}
+
+// jsonRepo keeps the history in an append-only JSONL file, one event per
+// line. Every operation holds a lock on a companion ".lock" file and
+// reloads the history if another process changed it, so several pomo
+// instances can share the same file.
+type jsonRepo struct {
+	sync.Mutex
+	path         string
+	lock         *os.File
+	intervals    []pomodoro.Interval
+	changes      []pomodoro.Change
+	lastID       int64
+	lastChangeID int64
+	tasks        []pomodoro.Task
+	lastTaskID   int64
+	events       int
+	size         int64
+	validSize    int64
+	modTime      time.Time
+}
+
+func NewJSONRepo(path string) (*jsonRepo, error) {
+	lock, err := os.OpenFile(path+".lock", os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &jsonRepo{
+		path: path,
+		lock: lock,
+	}
+
+	// Load the history, compacting it if it grew too large last time
+	err = r.exclusive(context.Background(), func() error {
+		return r.maybeCompact()
+	})
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// shared runs fn holding a shared lock on the history with the in-memory
+// copy up to date.
+func (r *jsonRepo) shared(ctx context.Context, fn func() error) error {
+	return r.withLock(ctx, false, fn)
+}
+
+// exclusive runs fn holding an exclusive lock on the history with the
+// in-memory copy up to date.
+func (r *jsonRepo) exclusive(ctx context.Context, fn func() error) error {
+	return r.withLock(ctx, true, fn)
+}
+
+func (r *jsonRepo) withLock(ctx context.Context, exclusive bool,
+	fn func() error) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if err := lockFile(ctx, r.lock, exclusive); err != nil {
+		return err
+	}
+	defer unlockFile(r.lock)
+
+	if err := r.refresh(); err != nil {
+		return err
+	}
+
+	return fn()
+}
+
+// refresh reloads the history file if it changed since it was last read
+// or written by this repository.
+func (r *jsonRepo) refresh() error {
+	fi, err := os.Stat(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		r.intervals = []pomodoro.Interval{}
+		r.changes = []pomodoro.Change{}
+		r.lastID = 0
+		r.lastChangeID = 0
+		r.tasks = []pomodoro.Task{}
+		r.lastTaskID = 0
+		r.events, r.size, r.validSize = 0, 0, 0
+		r.modTime = time.Time{}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if fi.Size() == r.size && fi.ModTime().Equal(r.modTime) {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+
+	intervals := []pomodoro.Interval{}
+	changes := []pomodoro.Change{}
+	tasks := []pomodoro.Task{}
+	var lastID, lastChangeID, lastTaskID int64
+	events := 0
+	lines := bytes.Split(data, []byte("\n"))
+
+	// A last line without a newline is a write that didn't complete. It is
+	// ignored, and cut off before the next append.
+	validSize := int64(len(data) - len(lines[len(lines)-1]))
+	lines = lines[:len(lines)-1]
+
+	for n, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var e jsonEvent
+		if err := json.Unmarshal(line, &e); err != nil {
+			return fmt.Errorf("%s:%d: %w", r.path, n+1, err)
+		}
+
+		k, found := findInterval(intervals, e.ID)
+
+		switch {
+		case e.Op == "create" && e.ID > lastID:
+			intervals = append(intervals, e.interval())
+			lastID = e.ID
+		case e.Op == "update" && found:
+			intervals[k] = e.interval()
+		case e.Op == "delete" && found:
+			intervals = append(intervals[:k], intervals[k+1:]...)
+		case e.Op == "change":
+			var c jsonChange
+			if err := json.Unmarshal(line, &c); err != nil {
+				return fmt.Errorf("%s:%d: %w", r.path, n+1, err)
+			}
+			changes = append(changes, c.change())
+			lastChangeID = max(lastChangeID, c.ID)
+
+			// Keep IDs of deleted intervals from being reused after
+			// compaction
+			if c.IntervalID > lastID {
+				lastID = c.IntervalID
+			}
+		case e.Op == "ids":
+			var ids jsonIDs
+			if err := json.Unmarshal(line, &ids); err != nil {
+				return fmt.Errorf("%s:%d: %w", r.path, n+1, err)
+			}
+
+			lastID = max(lastID, ids.Interval)
+			lastChangeID = max(lastChangeID, ids.Change)
+			lastTaskID = max(lastTaskID, ids.Task)
+		case e.Op == "task":
+			var t jsonTask
+			if err := json.Unmarshal(line, &t); err != nil {
+				return fmt.Errorf("%s:%d: %w", r.path, n+1, err)
+			}
+
+			if k, found := findTask(tasks, t.ID); found {
+				tasks[k] = t.task()
+			} else if t.ID > lastTaskID {
+				tasks = append(tasks, t.task())
+				lastTaskID = t.ID
+			}
+		case e.Op == "task_delete":
+			if k, found := findTask(tasks, e.ID); found {
+				tasks = append(tasks[:k], tasks[k+1:]...)
+			}
+		default:
+			return fmt.Errorf("%s:%d: invalid %q event for ID %d",
+				r.path, n+1, e.Op, e.ID)
+		}
+
+		events++
+	}
+
+	r.intervals = intervals
+	r.changes = changes
+	r.lastID = lastID
+	r.lastChangeID = lastChangeID
+	r.tasks = tasks
+	r.lastTaskID = lastTaskID
+	r.events = events
+	r.size = fi.Size()
+	r.validSize = validSize
+	r.modTime = fi.ModTime()
+
+	return nil
+}
+
+// stat records the size and modification time of the history file after
+// this repository wrote to it.
+func (r *jsonRepo) stat() error {
+	fi, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+
+	r.size = fi.Size()
+	r.validSize = fi.Size()
+	r.modTime = fi.ModTime()
+
+	return nil
+}
+
+// appendEvent writes e to the end of the history file. It must be called
+// holding the exclusive lock.
+func (r *jsonRepo) appendEvent(e any) error {
+	return r.appendEvents(e)
+}
+
+// appendEvents writes events to the end of the history file at once, like
+// appendEvent.
+func (r *jsonRepo) appendEvents(events ...any) error {
+	var lines []byte
+	for _, e := range events {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if r.validSize < r.size {
+		if err := f.Truncate(r.validSize); err != nil {
+			return err
+		}
+	}
+
+	if _, err := f.Write(lines); err != nil {
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	r.events += len(events)
+	if err := r.stat(); err != nil {
+		return err
+	}
+
+	return r.maybeCompact()
+}
+
+func (r *jsonRepo) maybeCompact() error {
+	if r.events-len(r.intervals)-len(r.changes)-len(r.tasks) <
+		compactThreshold {
+		return nil
+	}
+
+	return r.compact()
+}
+
+// compact rewrites the history with a single create event per interval,
+// followed by the audit trail, the task list and the last IDs given.
+// The new file is written next to the old one and renamed over it, so a
+// crash leaves either the old or the new history in place.
+func (r *jsonRepo) compact() error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+
+	for _, i := range r.intervals {
+		if err := enc.Encode(newJSONEvent("create", i)); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range r.changes {
+		if err := enc.Encode(newJSONChange(c)); err != nil {
+			return err
+		}
+	}
+
+	for _, t := range r.tasks {
+		if err := enc.Encode(newJSONTask(t)); err != nil {
+			return err
+		}
+	}
+
+	ids := jsonIDs{Op: "ids", Interval: r.lastID, Change: r.lastChangeID,
+		Task: r.lastTaskID}
+	if err := enc.Encode(ids); err != nil {
+		return err
+	}
+
+	dir, base := filepath.Split(r.path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := os.CreateTemp(dir, base+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), r.path); err != nil {
+		return err
+	}
+
+	r.events = len(r.intervals) + len(r.changes) + len(r.tasks) + 1
+	return r.stat()
+}
+
+// Compact rewrites the history file keeping only the latest state of each
+// interval.
+func (r *jsonRepo) Compact(ctx context.Context) error {
+	return r.exclusive(ctx, r.compact)
+}
+
+func (r *jsonRepo) Create(ctx context.Context,
+	i pomodoro.Interval) (int64, error) {
+	err := r.exclusive(ctx, func() error {
+		i.ID = r.lastID + 1
+
+		if err := r.appendEvent(newJSONEvent("create", i)); err != nil {
+			return err
+		}
+
+		r.intervals = append(r.intervals, i)
+		r.lastID = i.ID
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return i.ID, nil
+}
+
+func (r *jsonRepo) Update(ctx context.Context, i pomodoro.Interval,
+	changes ...pomodoro.Change) error {
+
+	return r.exclusive(ctx, func() error {
+		k, ok := findInterval(r.intervals, i.ID)
+		if !ok {
+			return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, i.ID)
+		}
+
+		changes = r.numberChanges(changes)
+		err := r.appendEvents(changeEvents(changes, newJSONEvent("update", i))...)
+		if err != nil {
+			return err
+		}
+
+		r.intervals[k] = i
+		r.addChanges(changes)
+		return nil
+	})
+}
+
+func (r *jsonRepo) ByID(ctx context.Context,
+	id int64) (pomodoro.Interval, error) {
+	i := pomodoro.Interval{}
+
+	err := r.shared(ctx, func() error {
+		k, ok := findInterval(r.intervals, id)
+		if !ok {
+			return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+		}
+
+		i = r.intervals[k]
+		return nil
+	})
+
+	return i, err
+}
+
+func (r *jsonRepo) Last(ctx context.Context,
+	user string) (pomodoro.Interval, error) {
+	i := pomodoro.Interval{}
+
+	err := r.shared(ctx, func() error {
+		var err error
+		i, err = lastInterval(r.intervals, user)
+		return err
+	})
+
+	return i, err
+}
+
+func (r *jsonRepo) Breaks(ctx context.Context, user string,
+	n int) ([]pomodoro.Interval, error) {
+	var data []pomodoro.Interval
+
+	err := r.shared(ctx, func() error {
+		data = lastBreaks(r.intervals, user, n)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *jsonRepo) List(ctx context.Context,
+	q pomodoro.Query) ([]pomodoro.Interval, error) {
+
+	var data []pomodoro.Interval
+
+	err := r.shared(ctx, func() error {
+		data = listIntervals(r.intervals, q)
+		return nil
+	})
+
+	return data, err
+}
+
+func (r *jsonRepo) Delete(ctx context.Context, id int64,
+	changes ...pomodoro.Change) error {
+
+	return r.exclusive(ctx, func() error {
+		k, ok := findInterval(r.intervals, id)
+		if !ok {
+			return fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+		}
+
+		changes = r.numberChanges(changes)
+		err := r.appendEvents(changeEvents(changes, jsonDelete{"delete", id})...)
+		if err != nil {
+			return err
+		}
+
+		r.intervals = append(r.intervals[:k], r.intervals[k+1:]...)
+		r.addChanges(changes)
+		return nil
+	})
+}
+
+// numberChanges gives changes the IDs they're recorded under.
+func (r *jsonRepo) numberChanges(changes []pomodoro.Change) []pomodoro.Change {
+	numbered := make([]pomodoro.Change, len(changes))
+	for k, c := range changes {
+		c.ID = r.lastChangeID + int64(k) + 1
+		numbered[k] = c
+	}
+
+	return numbered
+}
+
+// addChanges keeps numbered changes once written.
+func (r *jsonRepo) addChanges(changes []pomodoro.Change) {
+	r.changes = append(r.changes, changes...)
+	if len(changes) > 0 {
+		r.lastChangeID = changes[len(changes)-1].ID
+	}
+}
+
+// changeEvents returns the events recording changes followed by e, the
+// event they describe. Written together, a torn write can only lose e
+// after its changes, never the changes of an interval saved.
+func changeEvents(changes []pomodoro.Change, e any) []any {
+	events := make([]any, 0, len(changes)+1)
+	for _, c := range changes {
+		events = append(events, newJSONChange(c))
+	}
+
+	return append(events, e)
+}
+
+func (r *jsonRepo) Changes(ctx context.Context, id int64,
+	user string) ([]pomodoro.Change, error) {
+
+	var data []pomodoro.Change
+
+	err := r.shared(ctx, func() error {
+		data = listChanges(r.changes, id, user)
+		return nil
+	})
+
+	return data, err
+}
+
+func (r *jsonRepo) CategorySummary(ctx context.Context,
+	q pomodoro.Query) (time.Duration, error) {
+
+	var d time.Duration
+
+	err := r.shared(ctx, func() error {
+		d = sumIntervals(r.intervals, q)
+		return nil
+	})
+
+	return d, err
+}
+
+func (r *jsonRepo) Totals(ctx context.Context,
+	user string) (pomodoro.Totals, error) {
+
+	var t pomodoro.Totals
+
+	err := r.shared(ctx, func() error {
+		t = totalIntervals(r.intervals, user)
+		return nil
+	})
+
+	return t, err
+}
+
+func (r *jsonRepo) Users(ctx context.Context) ([]string, error) {
+	var data []string
+
+	err := r.shared(ctx, func() error {
+		data = listUsers(r.intervals, r.tasks)
+		return nil
+	})
+
+	return data, err
+}
+
+func (r *jsonRepo) CreateTask(ctx context.Context,
+	t pomodoro.Task) (int64, error) {
+
+	err := r.exclusive(ctx, func() error {
+		t.ID = r.lastTaskID + 1
+
+		if err := r.appendEvent(newJSONTask(t)); err != nil {
+			return err
+		}
+
+		r.tasks = append(r.tasks, t)
+		r.lastTaskID = t.ID
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return t.ID, nil
+}
+
+func (r *jsonRepo) UpdateTask(ctx context.Context, t pomodoro.Task) error {
+	return r.exclusive(ctx, func() error {
+		k, ok := findTask(r.tasks, t.ID)
+		if !ok {
+			return fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, t.ID)
+		}
+
+		if err := r.appendEvent(newJSONTask(t)); err != nil {
+			return err
+		}
+
+		r.tasks[k] = t
+		return nil
+	})
+}
+
+func (r *jsonRepo) TaskByID(ctx context.Context,
+	id int64) (pomodoro.Task, error) {
+	t := pomodoro.Task{}
+
+	err := r.shared(ctx, func() error {
+		k, ok := findTask(r.tasks, id)
+		if !ok {
+			return fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+		}
+
+		t = r.tasks[k]
+		return nil
+	})
+
+	return t, err
+}
+
+func (r *jsonRepo) DeleteTask(ctx context.Context, id int64) error {
+	return r.exclusive(ctx, func() error {
+		k, ok := findTask(r.tasks, id)
+		if !ok {
+			return fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+		}
+
+		if err := r.appendEvent(jsonDelete{"task_delete", id}); err != nil {
+			return err
+		}
+
+		r.tasks = append(r.tasks[:k], r.tasks[k+1:]...)
+		return nil
+	})
+}
+
+func (r *jsonRepo) Tasks(ctx context.Context,
+	user string) ([]pomodoro.Task, error) {
+	var data []pomodoro.Task
+
+	err := r.shared(ctx, func() error {
+		data = listTasks(r.tasks, user)
+		return nil
+	})
+
+	return data, err
+}
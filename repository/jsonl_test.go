@@ -0,0 +1,263 @@
+package repository_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestJSONRepo(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pomo.jsonl")
+
+	r1, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := pomodoro.Interval{
+		StartTime:       time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		PlannedDuration: 25 * time.Minute,
+		Category:        pomodoro.CategoryPomodoro,
+	}
+
+	if i.ID, err = r1.Create(ctx, i); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second repository on the same file sees the changes of the first
+	r2, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i.ActualDuration = 25 * time.Minute
+	i.State = pomodoro.StateDone
+	if err := r2.Update(ctx, i); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := r1.Last(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.State != pomodoro.StateDone || got.ActualDuration != i.ActualDuration {
+		t.Errorf("Expected %v, got %v instead\n", i, got)
+	}
+
+	if _, err := r1.Create(ctx, pomodoro.Interval{
+		Category: pomodoro.CategoryShortBreak,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	breaks, err := r2.Breaks(ctx, "", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(breaks) != 1 || breaks[0].ID != 2 {
+		t.Errorf("Expected break with ID 2, got %v instead\n", breaks)
+	}
+
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	d, err := r2.CategorySummary(ctx, pomodoro.Query{
+		Start:      day,
+		End:        day.AddDate(0, 0, 1),
+		Categories: []string{pomodoro.CategoryPomodoro},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d != 25*time.Minute {
+		t.Errorf("Expected summary %q, got %q instead\n", 25*time.Minute, d)
+	}
+}
+
+func TestJSONRepoCompact(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pomo.jsonl")
+
+	r, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	task := pomodoro.Task{Name: "write report", Created: time.Now()}
+	if task.ID, err = r.CreateTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+
+	i := pomodoro.Interval{Category: pomodoro.CategoryPomodoro}
+	if i.ID, err = r.Create(ctx, i); err != nil {
+		t.Fatal(err)
+	}
+
+	// Every update appends a line until the file is compacted
+	for k := 0; k < 1500; k++ {
+		i.ActualDuration += time.Second
+		if err := r.Update(ctx, i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := bytes.Count(data, []byte("\n")); n > 1000 {
+		t.Errorf("Expected compacted file, got %d lines\n", n)
+	}
+
+	got, err := r.ByID(ctx, i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ActualDuration != i.ActualDuration {
+		t.Errorf("Expected duration %q, got %q instead\n",
+			i.ActualDuration, got.ActualDuration)
+	}
+
+	// Tasks are kept by the compaction
+	reopened, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := reopened.TaskByID(ctx, task.ID); err != nil ||
+		got.Name != task.Name {
+		t.Errorf("Expected task %q, got %q and error %v\n", task.Name,
+			got.Name, err)
+	}
+}
+
+func TestJSONRepoCompactIDs(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pomo.jsonl")
+
+	r, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for k := 0; k < 2; k++ {
+		i := pomodoro.Interval{Category: pomodoro.CategoryPomodoro}
+		i.ID, err = r.Create(ctx, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := r.Update(ctx, i, pomodoro.Change{IntervalID: i.ID,
+			Field: "note"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	taskID, err := r.CreateTask(ctx, pomodoro.Task{Name: "write report"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The latest interval and task are deleted without any change
+	if err := r.Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.DeleteTask(ctx, taskID); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Compact(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := reopened.Create(ctx, pomodoro.Interval{
+		Category: pomodoro.CategoryShortBreak})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 3 {
+		t.Errorf("Expected interval ID 3, got %d", id)
+	}
+
+	if err := reopened.Update(ctx, pomodoro.Interval{ID: 3,
+		Category: pomodoro.CategoryShortBreak},
+		pomodoro.Change{IntervalID: 3, Field: "note"}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := reopened.Changes(ctx, 3, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].ID != 3 {
+		t.Errorf("Expected change ID 3, got %v", changes)
+	}
+
+	id, err = reopened.CreateTask(ctx, pomodoro.Task{Name: "review"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != taskID+1 {
+		t.Errorf("Expected task ID %d, got %d", taskID+1, id)
+	}
+}
+
+func TestJSONRepoTornWrite(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pomo.jsonl")
+
+	r, err := repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.Create(ctx, pomodoro.Interval{
+		Category: pomodoro.CategoryPomodoro,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Simulate a crash in the middle of writing a line
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"op":"create","id":2,`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	r, err = repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := r.Create(ctx, pomodoro.Interval{
+		Category: pomodoro.CategoryShortBreak,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 {
+		t.Errorf("Expected ID 2, got %d instead\n", id)
+	}
+
+	r, err = repository.NewJSONRepo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := r.Last(ctx, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last.Category != pomodoro.CategoryShortBreak {
+		t.Errorf("Expected category %q, got %q instead\n",
+			pomodoro.CategoryShortBreak, last.Category)
+	}
+}
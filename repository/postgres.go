@@ -0,0 +1,474 @@
+package repository
+
+import (
+  "context"
+  "database/sql"
+  "fmt"
+  "sync"
+  "time"
+
+  // Blank import for postgres driver only
+  _ "github.com/lib/pq"
+  "github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// pgMigrations holds the schema changes for the postgres repository. Each
+// entry is applied once, in order, and recorded in schema_migrations.
+// Append new migrations to the end; never edit an applied one.
+var pgMigrations = []string{
+  `CREATE TABLE IF NOT EXISTS "interval" (
+        "id"    BIGSERIAL PRIMARY KEY,
+        "start_time"    TIMESTAMPTZ NOT NULL,
+        "planned_duration"      BIGINT DEFAULT 0,
+        "actual_duration"       BIGINT DEFAULT 0,
+        "category"  TEXT NOT NULL,
+        "state" INTEGER DEFAULT 1
+);`,
+  `ALTER TABLE "interval" ADD COLUMN "task" TEXT NOT NULL DEFAULT ''`,
+  `CREATE TABLE IF NOT EXISTS "interval_change" (
+        "id"    BIGSERIAL PRIMARY KEY,
+        "interval_id"   BIGINT NOT NULL,
+        "time"  TIMESTAMPTZ NOT NULL,
+        "field" TEXT NOT NULL,
+        "old_value"     TEXT NOT NULL DEFAULT '',
+        "new_value"     TEXT NOT NULL DEFAULT ''
+);`,
+  `ALTER TABLE "interval" ADD COLUMN "manual" BOOLEAN NOT NULL DEFAULT FALSE`,
+  `ALTER TABLE "interval" ADD COLUMN "profile" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "note" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "internal_interruptions" INTEGER NOT NULL DEFAULT 0`,
+  `ALTER TABLE "interval" ADD COLUMN "external_interruptions" INTEGER NOT NULL DEFAULT 0`,
+  `CREATE TABLE IF NOT EXISTS "task" (
+        "id"    BIGSERIAL PRIMARY KEY,
+        "name"  TEXT NOT NULL,
+        "estimate"      INTEGER NOT NULL DEFAULT 0,
+        "created"       TIMESTAMPTZ NOT NULL,
+        "completed"     TIMESTAMPTZ
+);`,
+  `ALTER TABLE "interval" ADD COLUMN "task_id" BIGINT NOT NULL DEFAULT 0`,
+  `ALTER TABLE "task" ADD COLUMN "source" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "task" ADD COLUMN "external_id" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "project" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "tags" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval_change" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "task" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+}
+
+// pgDialect builds queries for postgres.
+var pgDialect = sqlDialect{
+  placeholder: func(n int) string { return fmt.Sprintf("$%d", n) },
+  startsBefore: func(p string) string {
+    return "start_time < " + p
+  },
+  endsAfter: func(p string) string {
+    return fmt.Sprintf(`start_time +
+    actual_duration / 1000 * INTERVAL '1 microsecond' > %s`, p)
+  },
+  orderBy: "start_time",
+  noLimit: "ALL",
+}
+
+func init() {
+  open := func(dsn string) (pomodoro.Repository, error) {
+    repo, err := NewPostgresRepo(dsn)
+    if err != nil {
+      return nil, err
+    }
+
+    return repo, nil
+  }
+
+  Register("postgres", open)
+  Register("postgresql", open)
+}
+
+type pgRepo struct {
+  db *sql.DB
+  sync.RWMutex
+}
+
+func NewPostgresRepo(dsn string) (*pgRepo, error) {
+  db, err := sql.Open("postgres", dsn)
+  if err != nil {
+    return nil, err
+  }
+
+  db.SetConnMaxLifetime(30 * time.Minute)
+
+  if err := db.Ping(); err != nil {
+    return nil, err
+  }
+
+  if err := pgMigrate(db); err != nil {
+    return nil, err
+  }
+
+  return &pgRepo{
+    db: db,
+  }, nil
+}
+
+// pgMigrate brings the database schema up to date. Migrations run in a
+// single transaction holding an advisory lock, so several clients starting
+// at the same time don't apply them twice.
+func pgMigrate(db *sql.DB) error {
+  tx, err := db.Begin()
+  if err != nil {
+    return err
+  }
+  defer tx.Rollback()
+
+  if _, err := tx.Exec("SELECT pg_advisory_xact_lock(7270766)"); err != nil {
+    return err
+  }
+
+  if _, err := tx.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+        "version" INTEGER PRIMARY KEY,
+        "applied_at" TIMESTAMPTZ NOT NULL DEFAULT now()
+  );`); err != nil {
+    return err
+  }
+
+  var version int
+  err = tx.QueryRow(
+    `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+  if err != nil {
+    return err
+  }
+
+  for v := version; v < len(pgMigrations); v++ {
+    if _, err := tx.Exec(pgMigrations[v]); err != nil {
+      return fmt.Errorf("migration %d: %w", v+1, err)
+    }
+
+    _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES ($1)`,
+      v+1)
+    if err != nil {
+      return err
+    }
+  }
+
+  return tx.Commit()
+}
+
+func (r *pgRepo) Create(ctx context.Context, i pomodoro.Interval) (int64, error) {
+  // Create entry in the repository
+  r.Lock()
+  defer r.Unlock()
+
+  // Exec INSERT statement returning the new ID
+  var id int64
+  err := r.db.QueryRowContext(ctx, `INSERT INTO "interval"
+  (start_time, planned_duration, actual_duration, category, state, task,
+  manual, profile, note, internal_interruptions, external_interruptions,
+  task_id, project, tags, user_name)
+  VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+  RETURNING id`,
+    i.StartTime, i.PlannedDuration, i.ActualDuration,
+    i.Category, i.State, i.Task, i.Manual, i.Profile, i.Note,
+    i.InternalInterruptions, i.ExternalInterruptions, i.TaskID, i.Project,
+    joinTags(i.Tags), i.User).Scan(&id)
+  if err != nil {
+    return 0, err
+  }
+
+  return id, nil
+}
+
+func (r *pgRepo) Update(ctx context.Context, i pomodoro.Interval,
+  changes ...pomodoro.Change) error {
+
+  // Update entry in the repository
+  r.Lock()
+  defer r.Unlock()
+
+  exec := func(tx *sql.Tx) error {
+    // Exec UPDATE statement
+    res, err := tx.ExecContext(ctx, `UPDATE "interval"
+  SET start_time=$1, planned_duration=$2, actual_duration=$3, category=$4,
+  state=$5, task=$6, manual=$7, profile=$8, note=$9,
+  internal_interruptions=$10, external_interruptions=$11, task_id=$12,
+  project=$13, tags=$14, user_name=$15 WHERE id=$16`,
+      i.StartTime, i.PlannedDuration, i.ActualDuration, i.Category,
+      i.State, i.Task, i.Manual, i.Profile, i.Note, i.InternalInterruptions,
+      i.ExternalInterruptions, i.TaskID, i.Project, joinTags(i.Tags), i.User,
+      i.ID)
+    if err != nil {
+      return err
+    }
+
+    n, err := res.RowsAffected()
+    if err == nil && n == 0 {
+      err = fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, i.ID)
+    }
+    return err
+  }
+
+  return withChanges(ctx, r.db, pgAddChange, changes, exec)
+}
+
+func (r *pgRepo) ByID(ctx context.Context, id int64) (pomodoro.Interval, error) {
+  // Search items in the repository by ID
+  r.RLock()
+  defer r.RUnlock()
+
+  // Query DB row based on ID and parse it into Interval struct
+  row := r.db.QueryRowContext(ctx,
+    `SELECT `+intervalColumns+` FROM "interval" WHERE id=$1`, id)
+  i, err := scanInterval(row)
+  if err == sql.ErrNoRows {
+    return i, fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+  }
+  return i, err
+}
+
+func (r *pgRepo) Last(ctx context.Context,
+  user string) (pomodoro.Interval, error) {
+
+  // Search last item of the user in the repository
+  r.RLock()
+  defer r.RUnlock()
+
+  // Query and parse last row into Interval struct
+  last, err := scanInterval(r.db.QueryRowContext(ctx,
+    `SELECT `+intervalColumns+` FROM "interval"
+  WHERE NOT manual AND ($1='' OR user_name = $1) ORDER BY id DESC LIMIT 1`,
+    user))
+
+  if err == sql.ErrNoRows {
+    return last, pomodoro.ErrNoIntervals
+  }
+
+  if err != nil {
+    return last, err
+  }
+
+  return last, nil
+}
+
+func (r *pgRepo) Breaks(ctx context.Context, user string,
+  n int) ([]pomodoro.Interval, error) {
+
+  // Search last n items of type break of the user in the repository
+  r.RLock()
+  defer r.RUnlock()
+
+  // Define SELECT query for breaks
+  stmt := `SELECT ` + intervalColumns + ` FROM "interval"
+  WHERE category LIKE '%Break' AND NOT manual
+  AND ($1='' OR user_name = $1)
+  ORDER BY id DESC LIMIT $2`
+
+  // Query DB for breaks
+  rows, err := r.db.QueryContext(ctx, stmt, user, n)
+  if err != nil {
+    return nil, err
+  }
+
+  // Parse data into slice of Interval
+  return scanIntervals(rows)
+}
+
+func (r *pgRepo) List(ctx context.Context,
+  q pomodoro.Query) ([]pomodoro.Interval, error) {
+
+  // Search items matching the query in the repository
+  r.RLock()
+  defer r.RUnlock()
+
+  stmt, args := pgDialect.list(`"interval"`, q)
+
+  rows, err := r.db.QueryContext(ctx, stmt, args...)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanIntervals(rows)
+}
+
+func (r *pgRepo) Delete(ctx context.Context, id int64,
+  changes ...pomodoro.Change) error {
+
+  // Delete entry from the repository
+  r.Lock()
+  defer r.Unlock()
+
+  exec := func(tx *sql.Tx) error {
+    res, err := tx.ExecContext(ctx, `DELETE FROM "interval" WHERE id=$1`, id)
+    if err != nil {
+      return err
+    }
+
+    n, err := res.RowsAffected()
+    if err == nil && n == 0 {
+      err = fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+    }
+    return err
+  }
+
+  return withChanges(ctx, r.db, pgAddChange, changes, exec)
+}
+
+// pgAddChange records an entry of the audit trail.
+const pgAddChange = `INSERT INTO "interval_change"
+  (interval_id, time, field, old_value, new_value, user_name)
+  VALUES ($1, $2, $3, $4, $5, $6)`
+
+func (r *pgRepo) Changes(ctx context.Context, id int64,
+  user string) ([]pomodoro.Change, error) {
+
+  // Search the audit trail of an interval, or all of it, seen by the user
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, `SELECT `+changeColumns+`
+  FROM "interval_change" WHERE ($1=0 OR interval_id=$1)
+  AND ($2='' OR user_name IN ($2, '')) ORDER BY id`, id, user)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanChanges(rows)
+}
+
+func (r *pgRepo) CategorySummary(ctx context.Context,
+  q pomodoro.Query) (time.Duration, error) {
+
+  // Return a summary for the given period
+  r.RLock()
+  defer r.RUnlock()
+
+  // Query intervals overlapping the period
+  where, args := pgDialect.where(q)
+  rows, err := r.db.QueryContext(ctx,
+    `SELECT `+intervalColumns+` FROM "interval" `+where, args...)
+  if err != nil {
+    return 0, err
+  }
+
+  data, err := scanIntervals(rows)
+  if err != nil {
+    return 0, err
+  }
+
+  // Add the part of each interval that falls within the period
+  var d time.Duration
+  for _, i := range data {
+    d += q.Duration(i)
+  }
+
+  return d, nil
+}
+
+func (r *pgRepo) Totals(ctx context.Context,
+  user string) (pomodoro.Totals, error) {
+
+  r.RLock()
+  defer r.RUnlock()
+
+  // Sum up the intervals in the database rather than reading them all
+  stmt, args := pgDialect.totals(`"interval"`, user)
+  rows, err := r.db.QueryContext(ctx, stmt, args...)
+  if err != nil {
+    return pomodoro.Totals{}, err
+  }
+
+  return scanTotals(rows)
+}
+
+func (r *pgRepo) Users(ctx context.Context) ([]string, error) {
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, `SELECT user_name FROM "interval"
+  UNION SELECT user_name FROM "task" ORDER BY 1`)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanUsers(rows)
+}
+
+func (r *pgRepo) CreateTask(ctx context.Context,
+  t pomodoro.Task) (int64, error) {
+
+  // Add a task to the list
+  r.Lock()
+  defer r.Unlock()
+
+  var id int64
+  err := r.db.QueryRowContext(ctx, `INSERT INTO "task"
+  (name, estimate, created, completed, source, external_id, user_name)
+  VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+    t.Name, t.Estimate, t.Created, nullTime(t.Completed), t.Source,
+    t.ExternalID, t.User).Scan(&id)
+  return id, err
+}
+
+func (r *pgRepo) UpdateTask(ctx context.Context, t pomodoro.Task) error {
+  // Update a task of the list
+  r.Lock()
+  defer r.Unlock()
+
+  res, err := r.db.ExecContext(ctx, `UPDATE "task"
+  SET name=$1, estimate=$2, created=$3, completed=$4, source=$5,
+  external_id=$6, user_name=$7 WHERE id=$8`,
+    t.Name, t.Estimate, t.Created, nullTime(t.Completed), t.Source,
+    t.ExternalID, t.User, t.ID)
+  if err != nil {
+    return err
+  }
+
+  n, err := res.RowsAffected()
+  if err == nil && n == 0 {
+    err = fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, t.ID)
+  }
+  return err
+}
+
+func (r *pgRepo) TaskByID(ctx context.Context, id int64) (pomodoro.Task, error) {
+  // Search a task by ID
+  r.RLock()
+  defer r.RUnlock()
+
+  t, err := scanTask(r.db.QueryRowContext(ctx,
+    `SELECT `+taskColumns+` FROM "task" WHERE id=$1`, id))
+  if err == sql.ErrNoRows {
+    return t, fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+  }
+  return t, err
+}
+
+func (r *pgRepo) DeleteTask(ctx context.Context, id int64) error {
+  // Remove a task from the list
+  r.Lock()
+  defer r.Unlock()
+
+  res, err := r.db.ExecContext(ctx, `DELETE FROM "task" WHERE id=$1`, id)
+  if err != nil {
+    return err
+  }
+
+  n, err := res.RowsAffected()
+  if err == nil && n == 0 {
+    err = fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+  }
+  return err
+}
+
+func (r *pgRepo) Tasks(ctx context.Context,
+  user string) ([]pomodoro.Task, error) {
+
+  // Search every task in the list seen by the user
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, `SELECT `+taskColumns+` FROM "task"
+  WHERE $1='' OR user_name IN ($1, '') ORDER BY id`, user)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanTasks(rows)
+}
@@ -0,0 +1,172 @@
+package repository_test
+
+import (
+  "context"
+  "database/sql"
+  "errors"
+  "os"
+  "testing"
+  "time"
+
+  "github.com/xasterKies/pomanalyzer/pomodoro"
+  "github.com/xasterKies/pomanalyzer/repository"
+)
+
+// getPostgresRepo connects to the database in POMO_TEST_POSTGRES, or to a
+// local pomanalyzer_test database, skipping the test if none is reachable.
+// The test database is emptied before use.
+func getPostgresRepo(t *testing.T) pomodoro.Repository {
+  t.Helper()
+
+  dsn := os.Getenv("POMO_TEST_POSTGRES")
+  if dsn == "" {
+    dsn = "postgres://localhost/pomanalyzer_test?sslmode=disable"
+  }
+
+  db, err := sql.Open("postgres", dsn)
+  if err != nil {
+    t.Skip("Skipped: postgres not available:", err)
+  }
+  defer db.Close()
+
+  if err := db.Ping(); err != nil {
+    t.Skip("Skipped: postgres not available:", err)
+  }
+
+  _, err = db.Exec(`DROP TABLE IF EXISTS "interval", "interval_change",
+  "task", "schema_migrations"`)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  repo, err := repository.NewPostgresRepo(dsn)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  return repo
+}
+
+func TestPostgresRepo(t *testing.T) {
+  repo := getPostgresRepo(t)
+
+  if _, err := repo.Last(context.Background(), ""); !errors.Is(err, pomodoro.ErrNoIntervals) {
+    t.Fatalf("Expected error %q, got %q", pomodoro.ErrNoIntervals, err)
+  }
+
+  start := time.Date(2026, 10, 16, 23, 50, 0, 0, time.UTC)
+  categories := []string{
+    pomodoro.CategoryPomodoro,
+    pomodoro.CategoryShortBreak,
+    pomodoro.CategoryPomodoro,
+  }
+
+  for k, c := range categories {
+    i := pomodoro.Interval{
+      StartTime:       start.Add(time.Duration(k) * 20 * time.Minute),
+      PlannedDuration: 20 * time.Minute,
+      Category:        c,
+    }
+
+    id, err := repo.Create(context.Background(), i)
+    if err != nil {
+      t.Fatal(err)
+    }
+
+    i.ID = id
+    i.ActualDuration = 20 * time.Minute
+    i.State = pomodoro.StateDone
+    if err := repo.Update(context.Background(), i); err != nil {
+      t.Fatal(err)
+    }
+  }
+
+  last, err := repo.Last(context.Background(), "")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if last.ID != 3 || last.State != pomodoro.StateDone {
+    t.Errorf("Expected done interval 3, got %d in state %d instead\n",
+      last.ID, last.State)
+  }
+
+  i, err := repo.ByID(context.Background(), 2)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if i.Category != pomodoro.CategoryShortBreak {
+    t.Errorf("Expected category %q, got %q instead\n",
+      pomodoro.CategoryShortBreak, i.Category)
+  }
+
+  breaks, err := repo.Breaks(context.Background(), "", 3)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(breaks) != 1 {
+    t.Errorf("Expected 1 break, got %d instead\n", len(breaks))
+  }
+
+  dayStart := time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)
+  d, err := repo.CategorySummary(context.Background(), pomodoro.Query{
+    Start:      dayStart,
+    End:        dayStart.AddDate(0, 0, 1),
+    Categories: []string{pomodoro.CategoryPomodoro},
+  })
+  if err != nil {
+    t.Fatal(err)
+  }
+  if exp := 30 * time.Minute; d != exp {
+    t.Errorf("Expected summary %q, got %q instead\n", exp, d)
+  }
+
+  // Histories kept per user
+  ctx := context.Background()
+  mine := pomodoro.Interval{
+    StartTime:       start.Add(2 * time.Hour),
+    PlannedDuration: 20 * time.Minute,
+    Category:        pomodoro.CategoryPomodoro,
+    User:            "alice",
+  }
+  if mine.ID, err = repo.Create(ctx, mine); err != nil {
+    t.Fatal(err)
+  }
+  mine.Note = "review"
+  if err := repo.Update(ctx, mine, pomodoro.Change{IntervalID: mine.ID,
+    Field: "note", NewValue: "review", User: "alice"}); err != nil {
+    t.Fatal(err)
+  }
+  if _, err := repo.CreateTask(ctx, pomodoro.Task{Name: "review",
+    Created: start, User: "alice"}); err != nil {
+    t.Fatal(err)
+  }
+
+  last, err = repo.Last(ctx, "alice")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if last.ID != mine.ID || last.User != "alice" || last.Note != "review" {
+    t.Errorf("Expected the interval of alice, got %+v", last)
+  }
+
+  users, err := repo.Users(ctx)
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(users) != 2 || users[0] != "" || users[1] != "alice" {
+    t.Errorf("Expected users \"\" and alice, got %q", users)
+  }
+
+  tasks, err := repo.Tasks(ctx, "bob")
+  if err != nil {
+    t.Fatal(err)
+  }
+  changes, err := repo.Changes(ctx, mine.ID, "bob")
+  if err != nil {
+    t.Fatal(err)
+  }
+  if len(tasks) != 0 || len(changes) != 0 {
+    t.Errorf("Expected nothing of alice for bob, got %v and %v", tasks,
+      changes)
+  }
+}
@@ -0,0 +1,180 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// findInterval returns the position of interval id in a list ordered by
+// ID, for backends that keep every interval in memory.
+func findInterval(all []pomodoro.Interval, id int64) (int, bool) {
+	k := sort.Search(len(all), func(k int) bool { return all[k].ID >= id })
+	return k, k < len(all) && all[k].ID == id
+}
+
+// findTask returns the position of task id in a list ordered by ID.
+func findTask(all []pomodoro.Task, id int64) (int, bool) {
+	k := sort.Search(len(all), func(k int) bool { return all[k].ID >= id })
+	return k, k < len(all) && all[k].ID == id
+}
+
+// listChanges returns the audit trail of interval id, or all changes if
+// id is 0, seen by user.
+func listChanges(all []pomodoro.Change, id int64,
+	user string) []pomodoro.Change {
+
+	data := []pomodoro.Change{}
+	for _, c := range all {
+		if (id == 0 || c.IntervalID == id) && seenBy(c.User, user) {
+			data = append(data, c)
+		}
+	}
+
+	return data
+}
+
+// listTasks returns the tasks seen by user.
+func listTasks(all []pomodoro.Task, user string) []pomodoro.Task {
+	data := []pomodoro.Task{}
+	for _, t := range all {
+		if seenBy(t.User, user) {
+			data = append(data, t)
+		}
+	}
+
+	return data
+}
+
+// seenBy reports whether user sees what owner recorded: their own and
+// what was recorded without a user, or everything if user is empty.
+func seenBy(owner, user string) bool {
+	return user == "" || owner == "" || owner == user
+}
+
+// timedBy reports whether interval i was timed for user, by anyone if
+// user is empty, rather than entered manually.
+func timedBy(i pomodoro.Interval, user string) bool {
+	return !i.Manual && (user == "" || i.User == user)
+}
+
+// lastInterval returns the latest interval timed for user, for backends
+// that keep every interval in memory, ordered by ID.
+func lastInterval(all []pomodoro.Interval,
+	user string) (pomodoro.Interval, error) {
+
+	for k := len(all) - 1; k >= 0; k-- {
+		if timedBy(all[k], user) {
+			return all[k], nil
+		}
+	}
+
+	return pomodoro.Interval{}, pomodoro.ErrNoIntervals
+}
+
+// lastBreaks returns the latest n breaks timed for user, the latest first,
+// for backends that keep every interval in memory, ordered by ID.
+func lastBreaks(all []pomodoro.Interval, user string,
+	n int) []pomodoro.Interval {
+
+	data := []pomodoro.Interval{}
+	for k := len(all) - 1; k >= 0 && len(data) < n; k-- {
+		if all[k].Category == pomodoro.CategoryPomodoro ||
+			!timedBy(all[k], user) {
+			continue
+		}
+
+		data = append(data, all[k])
+	}
+
+	return data
+}
+
+// listUsers returns the users of intervals and tasks, sorted, for
+// backends that keep everything in memory.
+func listUsers(intervals []pomodoro.Interval,
+	tasks []pomodoro.Task) []string {
+
+	seen := map[string]bool{}
+	for _, i := range intervals {
+		seen[i.User] = true
+	}
+	for _, t := range tasks {
+		seen[t.User] = true
+	}
+
+	data := make([]string, 0, len(seen))
+	for user := range seen {
+		data = append(data, user)
+	}
+	sort.Strings(data)
+
+	return data
+}
+
+// listIntervals returns the intervals selected by q, for backends that
+// keep every interval in memory.
+func listIntervals(all []pomodoro.Interval,
+	q pomodoro.Query) []pomodoro.Interval {
+
+	data := []pomodoro.Interval{}
+	for _, i := range all {
+		if q.Match(i) {
+			data = append(data, i)
+		}
+	}
+
+	sort.SliceStable(data, func(a, b int) bool {
+		if !data[a].StartTime.Equal(data[b].StartTime) {
+			return data[a].StartTime.Before(data[b].StartTime)
+		}
+		return data[a].ID < data[b].ID
+	})
+
+	if q.Descending {
+		for a, b := 0, len(data)-1; a < b; a, b = a+1, b-1 {
+			data[a], data[b] = data[b], data[a]
+		}
+	}
+
+	if q.Offset > 0 {
+		if q.Offset > len(data) {
+			q.Offset = len(data)
+		}
+		data = data[q.Offset:]
+	}
+
+	if q.Limit > 0 && q.Limit < len(data) {
+		data = data[:q.Limit]
+	}
+
+	return data
+}
+
+// totalIntervals sums up the intervals of user by category and state.
+func totalIntervals(all []pomodoro.Interval, user string) pomodoro.Totals {
+	t := pomodoro.Totals{Time: make(map[string]time.Duration)}
+
+	q := pomodoro.Query{User: user}
+	for _, i := range all {
+		if q.Match(i) {
+			t.Add(i.Category, i.State, 1, q.Duration(i))
+		}
+	}
+
+	return t
+}
+
+// sumIntervals adds up the time spent in the intervals selected by q, for
+// backends that keep every interval in memory.
+func sumIntervals(all []pomodoro.Interval, q pomodoro.Query) time.Duration {
+	var d time.Duration
+	for _, i := range all {
+		if q.Match(i) {
+			d += q.Duration(i)
+		}
+	}
+
+	return d
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// DefaultScheme is the backend used for DSNs without a scheme, which are
+// treated as plain database file names.
+const DefaultScheme = "sqlite"
+
+// Opener creates a repository for a DSN. It receives the full DSN,
+// including the scheme it was registered for.
+type Opener func(dsn string) (pomodoro.Repository, error)
+
+var (
+	backendsMu sync.RWMutex
+	backends   = make(map[string]Opener)
+)
+
+// Register makes a repository backend available under the given DSN scheme.
+// Backends call it from their init function. It panics if the scheme is
+// registered twice.
+func Register(scheme string, open Opener) {
+	backendsMu.Lock()
+	defer backendsMu.Unlock()
+
+	if _, dup := backends[scheme]; dup {
+		panic("repository: Register called twice for scheme " + scheme)
+	}
+
+	backends[scheme] = open
+}
+
+// Schemes returns the sorted list of registered DSN schemes.
+func Schemes() []string {
+	backendsMu.RLock()
+	defer backendsMu.RUnlock()
+
+	list := make([]string, 0, len(backends))
+	for s := range backends {
+		list = append(list, s)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// Open returns the repository for dsn, picking the backend registered for
+// its scheme, e.g. "memory:", "sqlite:pomo.db" or "postgres://host/db".
+func Open(dsn string) (pomodoro.Repository, error) {
+	scheme := Scheme(dsn)
+
+	backendsMu.RLock()
+	open, ok := backends[scheme]
+	backendsMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown repository scheme %q (available: %s)",
+			scheme, strings.Join(Schemes(), ", "))
+	}
+
+	return open(dsn)
+}
+
+// Scheme returns the scheme of dsn, or DefaultScheme if it has none.
+// Single letters are not taken as schemes so Windows paths like
+// C:\pomo.db still work.
+func Scheme(dsn string) string {
+	k := strings.Index(dsn, ":")
+	if k < 2 {
+		return DefaultScheme
+	}
+
+	for _, c := range dsn[:k] {
+		if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+			c == '+' || c == '-' || c == '.') {
+			return DefaultScheme
+		}
+	}
+
+	return dsn[:k]
+}
+
+// Path removes the scheme and an optional "//" from dsn, leaving the path
+// for file based backends.
+func Path(dsn string) string {
+	if Scheme(dsn) == DefaultScheme && !strings.HasPrefix(dsn, DefaultScheme+":") {
+		return dsn
+	}
+
+	dsn = dsn[strings.Index(dsn, ":")+1:]
+	return strings.TrimPrefix(dsn, "//")
+}
@@ -0,0 +1,48 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestScheme(t *testing.T) {
+	testCases := []struct {
+		dsn string
+		exp string
+	}{
+		{"pomo.db", "sqlite"},
+		{"/home/user/pomo.db", "sqlite"},
+		{`C:\Users\user\pomo.db`, "sqlite"},
+		{"sqlite:pomo.db", "sqlite"},
+		{"memory:", "memory"},
+		{"postgres://localhost/pomo", "postgres"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.dsn, func(t *testing.T) {
+			if s := repository.Scheme(tc.dsn); s != tc.exp {
+				t.Errorf("Expected %q, got %q instead\n", tc.exp, s)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	repo, err := repository.Open("memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Create(context.Background(), pomodoro.Interval{
+		Category: pomodoro.CategoryPomodoro,
+	}); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := repository.Open("unknown://foo"); err == nil {
+		t.Error("Expected error for unknown scheme, got nil")
+	}
+}
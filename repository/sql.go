@@ -0,0 +1,325 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// intervalColumns lists the interval columns in the order scanInterval
+// reads them.
+const intervalColumns = `id, start_time, planned_duration, actual_duration,
+  category, state, task, manual, profile, note, internal_interruptions,
+  external_interruptions, task_id, project, tags, user_name`
+
+// changeColumns lists the audit trail columns in the order scanChanges
+// reads them.
+const changeColumns = `id, interval_id, time, field, old_value, new_value,
+  user_name`
+
+// taskColumns lists the task columns in the order scanTask reads them.
+const taskColumns = `id, name, estimate, created, completed, source,
+  external_id, user_name`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanInterval(row rowScanner) (pomodoro.Interval, error) {
+	i := pomodoro.Interval{}
+	var tags string
+	err := row.Scan(&i.ID, &i.StartTime, &i.PlannedDuration,
+		&i.ActualDuration, &i.Category, &i.State, &i.Task, &i.Manual,
+		&i.Profile, &i.Note, &i.InternalInterruptions, &i.ExternalInterruptions,
+		&i.TaskID, &i.Project, &tags, &i.User)
+	if tags != "" {
+		i.Tags = strings.Fields(tags)
+	}
+	return i, err
+}
+
+// joinTags stores tags as a space separated list, which the tag filter
+// matches with LIKE.
+func joinTags(tags []string) string {
+	return strings.Join(tags, " ")
+}
+
+// likeEscape escapes the LIKE wildcards in s, for patterns with an
+// ESCAPE '\' clause.
+func likeEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// scanTask reads a task row. Open tasks have no completion time.
+func scanTask(row rowScanner) (pomodoro.Task, error) {
+	t := pomodoro.Task{}
+	completed := sql.NullTime{}
+	err := row.Scan(&t.ID, &t.Name, &t.Estimate, &t.Created, &completed,
+		&t.Source, &t.ExternalID, &t.User)
+	t.Completed = completed.Time
+	return t, err
+}
+
+// nullTime stores the zero time as NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+func scanTasks(rows *sql.Rows) ([]pomodoro.Task, error) {
+	defer rows.Close()
+
+	data := []pomodoro.Task{}
+	for rows.Next() {
+		t, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func scanIntervals(rows *sql.Rows) ([]pomodoro.Interval, error) {
+	defer rows.Close()
+
+	data := []pomodoro.Interval{}
+	for rows.Next() {
+		i, err := scanInterval(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, i)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// withChanges runs exec in a transaction of db that also records changes
+// with insert, the statement adding a change to the audit trail, so
+// neither is saved without the other.
+func withChanges(ctx context.Context, db *sql.DB, insert string,
+	changes []pomodoro.Change, exec func(tx *sql.Tx) error) error {
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := exec(tx); err != nil {
+		return err
+	}
+
+	for _, c := range changes {
+		if _, err := tx.ExecContext(ctx, insert, c.IntervalID, c.Time, c.Field,
+			c.OldValue, c.NewValue, c.User); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func scanUsers(rows *sql.Rows) ([]string, error) {
+	defer rows.Close()
+
+	data := []string{}
+	for rows.Next() {
+		var user string
+		if err := rows.Scan(&user); err != nil {
+			return nil, err
+		}
+
+		data = append(data, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func scanChanges(rows *sql.Rows) ([]pomodoro.Change, error) {
+	defer rows.Close()
+
+	data := []pomodoro.Change{}
+	for rows.Next() {
+		c := pomodoro.Change{}
+		err := rows.Scan(&c.ID, &c.IntervalID, &c.Time, &c.Field,
+			&c.OldValue, &c.NewValue, &c.User)
+		if err != nil {
+			return nil, err
+		}
+
+		data = append(data, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// sqlDialect holds the SQL that differs between database backends when
+// building queries.
+type sqlDialect struct {
+	// placeholder returns the n-th (1-based) query parameter
+	placeholder func(n int) string
+	// startsBefore and endsAfter compare the start and end of an
+	// interval with a time parameter
+	startsBefore func(param string) string
+	endsAfter    func(param string) string
+	// orderBy orders intervals by start time
+	orderBy string
+	// noLimit is the LIMIT value that returns every row
+	noLimit string
+}
+
+// where builds the WHERE clause and its arguments for q, without paging.
+func (d sqlDialect) where(q pomodoro.Query) (string, []any) {
+	conds := []string{}
+	args := []any{}
+
+	param := func(v any) string {
+		args = append(args, v)
+		return d.placeholder(len(args))
+	}
+
+	list := func(column string, n int, v func(k int) any) {
+		ps := make([]string, n)
+		for k := range ps {
+			ps[k] = param(v(k))
+		}
+		conds = append(conds,
+			fmt.Sprintf("%s IN (%s)", column, strings.Join(ps, ", ")))
+	}
+
+	if !q.Start.IsZero() {
+		conds = append(conds, d.endsAfter(param(q.Start)))
+	}
+
+	if !q.End.IsZero() {
+		conds = append(conds, d.startsBefore(param(q.End)))
+	}
+
+	if len(q.Categories) > 0 {
+		list("category", len(q.Categories),
+			func(k int) any { return q.Categories[k] })
+	}
+
+	if len(q.States) > 0 {
+		list("state", len(q.States), func(k int) any { return q.States[k] })
+	}
+
+	if q.Task != "" {
+		conds = append(conds, "task = "+param(q.Task))
+	}
+
+	if q.TaskID != 0 {
+		conds = append(conds, "task_id = "+param(q.TaskID))
+	}
+
+	if q.Project != "" {
+		conds = append(conds, fmt.Sprintf(
+			`(project = %s OR project LIKE %s ESCAPE '\')`, param(q.Project),
+			param(likeEscape(q.Project)+"/%")))
+	}
+
+	if q.Tag != "" {
+		conds = append(conds, fmt.Sprintf(
+			`' ' || tags || ' ' LIKE %s ESCAPE '\'`,
+			param("% "+likeEscape(q.Tag)+" %")))
+	}
+
+	if q.Profile != "" {
+		conds = append(conds, "profile = "+param(q.Profile))
+	}
+
+	if q.ExcludeManual {
+		conds = append(conds, "manual = "+param(false))
+	}
+
+	if q.User != "" {
+		conds = append(conds,
+			fmt.Sprintf("user_name IN (%s, '')", param(q.User)))
+	}
+
+	if q.ExcludeUnowned {
+		conds = append(conds, "user_name <> ''")
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+
+	return "WHERE " + strings.Join(conds, " AND "), args
+}
+
+// totals builds the statement summing up the intervals of user by
+// category and state, read by scanTotals.
+func (d sqlDialect) totals(table, user string) (string, []any) {
+	where, args := d.where(pomodoro.Query{User: user})
+
+	return fmt.Sprintf(`SELECT category, state, COUNT(*),
+  CAST(COALESCE(SUM(CASE WHEN actual_duration > 0 THEN actual_duration
+  ELSE 0 END), 0) AS BIGINT) FROM %s %s GROUP BY category, state`, table, where), args
+}
+
+func scanTotals(rows *sql.Rows) (pomodoro.Totals, error) {
+	defer rows.Close()
+
+	t := pomodoro.Totals{Time: make(map[string]time.Duration)}
+	for rows.Next() {
+		var (
+			category string
+			state, n int
+			d        int64
+		)
+		if err := rows.Scan(&category, &state, &n, &d); err != nil {
+			return t, err
+		}
+		t.Add(category, state, n, time.Duration(d))
+	}
+
+	return t, rows.Err()
+}
+
+// list builds the full SELECT statement for q, including order and paging.
+func (d sqlDialect) list(table string, q pomodoro.Query) (string, []any) {
+	where, args := d.where(q)
+
+	dir := "ASC"
+	if q.Descending {
+		dir = "DESC"
+	}
+
+	stmt := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s %s, id %s",
+		intervalColumns, table, where, d.orderBy, dir, dir)
+
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := d.noLimit
+		if q.Limit > 0 {
+			limit = fmt.Sprint(q.Limit)
+		}
+		stmt += fmt.Sprintf(" LIMIT %s OFFSET %d", limit, q.Offset)
+	}
+
+	return stmt, args
+}
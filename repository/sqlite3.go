@@ -1,15 +1,15 @@
-//go:build !inmemory
-// +build !inmemory
-
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
 	"sync"
 	"time"
 
-	// Blank import for sqlite3 driver only
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 	"github.com/xasterKies/pomanalyzer/pomodoro"
 )
 
@@ -25,6 +25,73 @@ const (
 );`
 )
 
+// sqliteMigrations holds the schema changes for the sqlite repository.
+// The number of applied migrations is kept in the user_version pragma.
+// Append new migrations to the end; never edit an applied one.
+var sqliteMigrations = []string{
+  createTableInterval,
+  `ALTER TABLE "interval" ADD COLUMN "task" TEXT NOT NULL DEFAULT ''`,
+  `CREATE TABLE IF NOT EXISTS "interval_change" (
+        "id"    INTEGER,
+        "interval_id"   INTEGER NOT NULL,
+        "time"  DATETIME NOT NULL,
+        "field" TEXT NOT NULL,
+        "old_value"     TEXT NOT NULL DEFAULT '',
+        "new_value"     TEXT NOT NULL DEFAULT '',
+        PRIMARY KEY("id")
+);`,
+  `ALTER TABLE "interval" ADD COLUMN "manual" INTEGER NOT NULL DEFAULT 0`,
+  `ALTER TABLE "interval" ADD COLUMN "profile" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "note" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "internal_interruptions" INTEGER NOT NULL DEFAULT 0`,
+  `ALTER TABLE "interval" ADD COLUMN "external_interruptions" INTEGER NOT NULL DEFAULT 0`,
+  `CREATE TABLE IF NOT EXISTS "task" (
+        "id"    INTEGER,
+        "name"  TEXT NOT NULL,
+        "estimate"      INTEGER NOT NULL DEFAULT 0,
+        "created"       DATETIME NOT NULL,
+        "completed"     DATETIME,
+        PRIMARY KEY("id")
+);`,
+  `ALTER TABLE "interval" ADD COLUMN "task_id" INTEGER NOT NULL DEFAULT 0`,
+  `ALTER TABLE "task" ADD COLUMN "source" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "task" ADD COLUMN "external_id" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "project" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "tags" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "interval_change" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+  `ALTER TABLE "task" ADD COLUMN "user_name" TEXT NOT NULL DEFAULT ''`,
+}
+
+// sqliteDialect builds queries for sqlite. Times are stored as text with
+// their zone offset, so they are compared as julian days.
+var sqliteDialect = sqlDialect{
+  placeholder: func(int) string { return "?" },
+  startsBefore: func(p string) string {
+    return fmt.Sprintf("julianday(start_time) < julianday(%s)", p)
+  },
+  endsAfter: func(p string) string {
+    return fmt.Sprintf(`julianday(start_time) +
+    actual_duration / 86400000000000.0 > julianday(%s)`, p)
+  },
+  orderBy: "julianday(start_time)",
+  noLimit: "-1",
+}
+
+func init() {
+  open := func(dsn string) (pomodoro.Repository, error) {
+    repo, err := NewSQLite3Repo(Path(dsn))
+    if err != nil {
+      return nil, err
+    }
+
+    return repo, nil
+  }
+
+  Register("sqlite", open)
+  Register("sqlite3", open)
+}
+
 type dbRepo struct {
   db *sql.DB
   sync.RWMutex
@@ -43,7 +110,7 @@ func NewSQLite3Repo(dbfile string) (*dbRepo, error) {
     return nil, err
   }
 
-  if _, err := db.Exec(createTableInterval); err != nil {
+  if err := sqliteMigrate(db); err != nil {
     return nil, err
   }
 
@@ -52,21 +119,56 @@ func NewSQLite3Repo(dbfile string) (*dbRepo, error) {
   }, nil
 }
 
-func (r *dbRepo) Create(i pomodoro.Interval) (int64, error) {
+// sqliteMigrate brings the database schema up to date.
+func sqliteMigrate(db *sql.DB) error {
+  tx, err := db.Begin()
+  if err != nil {
+    return err
+  }
+  defer tx.Rollback()
+
+  var version int
+  if err := tx.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+    return err
+  }
+
+  for v := version; v < len(sqliteMigrations); v++ {
+    if _, err := tx.Exec(sqliteMigrations[v]); err != nil {
+      return fmt.Errorf("migration %d: %w", v+1, err)
+    }
+  }
+
+  if version < len(sqliteMigrations) {
+    _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d",
+      len(sqliteMigrations)))
+    if err != nil {
+      return err
+    }
+  }
+
+  return tx.Commit()
+}
+
+func (r *dbRepo) Create(ctx context.Context, i pomodoro.Interval) (int64, error) {
   // Create entry in the repository
   r.Lock()
   defer r.Unlock()
 
   // Prepare INSERT statement
-  insStmt, err := r.db.Prepare("INSERT INTO interval VALUES(NULL, ?,?,?,?,?)")
+  insStmt, err := r.db.PrepareContext(ctx, `INSERT INTO interval
+  (start_time, planned_duration, actual_duration, category, state, task,
+  manual, profile, note, internal_interruptions, external_interruptions,
+  task_id, project, tags, user_name) VALUES(?,?,?,?,?,?,?,?,?,?,?,?,?,?,?)`)
   if err != nil {
     return 0, err
   }
   defer insStmt.Close()
 
   // Exec INSERT statement
-  res, err := insStmt.Exec(i.StartTime, i.PlannedDuration,
-    i.ActualDuration, i.Category, i.State)
+  res, err := insStmt.ExecContext(ctx, i.StartTime, i.PlannedDuration,
+    i.ActualDuration, i.Category, i.State, i.Task, i.Manual,
+    i.Profile, i.Note, i.InternalInterruptions, i.ExternalInterruptions,
+    i.TaskID, i.Project, joinTags(i.Tags), i.User)
   if err != nil {
     return 0, err
   }
@@ -80,56 +182,65 @@ func (r *dbRepo) Create(i pomodoro.Interval) (int64, error) {
   return id, nil
 }
 
-func (r *dbRepo) Update(i pomodoro.Interval) error {
+func (r *dbRepo) Update(ctx context.Context, i pomodoro.Interval,
+  changes ...pomodoro.Change) error {
+
   // Update entry in the repository
   r.Lock()
   defer r.Unlock()
 
-  // Prepare UPDATE statement
-  updStmt, err := r.db.Prepare(
-    "UPDATE interval SET start_time=?, actual_duration=?, state=? WHERE id=?")
-  if err != nil {
-    return err
-  }
-  defer updStmt.Close()
+  exec := func(tx *sql.Tx) error {
+    // Exec UPDATE statement
+    res, err := tx.ExecContext(ctx, `UPDATE interval
+  SET start_time=?, planned_duration=?, actual_duration=?, category=?,
+  state=?, task=?, manual=?, profile=?, note=?, internal_interruptions=?,
+  external_interruptions=?, task_id=?, project=?, tags=?, user_name=?
+  WHERE id=?`, i.StartTime, i.PlannedDuration, i.ActualDuration,
+      i.Category, i.State, i.Task, i.Manual, i.Profile, i.Note,
+      i.InternalInterruptions, i.ExternalInterruptions, i.TaskID, i.Project,
+      joinTags(i.Tags), i.User, i.ID)
+    if err != nil {
+      return err
+    }
 
-  // Exec UPDATE statement
-  res, err := updStmt.Exec(i.StartTime, i.ActualDuration, i.State, i.ID)
-  if err != nil {
+    // UPDATE results
+    n, err := res.RowsAffected()
+    if err == nil && n == 0 {
+      err = fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, i.ID)
+    }
     return err
   }
 
-  // UPDATE results
-  _, err = res.RowsAffected()
-  return err
+  return withChanges(ctx, r.db, sqliteAddChange, changes, exec)
 }
 
-func (r *dbRepo) ByID(id int64) (pomodoro.Interval, error) {
+func (r *dbRepo) ByID(ctx context.Context, id int64) (pomodoro.Interval, error) {
   // Search items in the repository by ID
   r.RLock()
   defer r.RUnlock()
 
-  // Query DB row based on ID
-  row := r.db.QueryRow("SELECT * FROM interval WHERE id=?", id)
-
-  // Parse row into Interval struct
-  i := pomodoro.Interval{}
-  err := row.Scan(&i.ID, &i.StartTime, &i.PlannedDuration,
-    &i.ActualDuration, &i.Category, &i.State)
+  // Query DB row based on ID and parse it into Interval struct
+  row := r.db.QueryRowContext(ctx,
+    "SELECT "+intervalColumns+" FROM interval WHERE id=?", id)
+  i, err := scanInterval(row)
+  if err == sql.ErrNoRows {
+    return i, fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+  }
   return i, err
 }
 
-func (r *dbRepo) Last() (pomodoro.Interval, error) {
-  // Search last item in the repository
+func (r *dbRepo) Last(ctx context.Context,
+  user string) (pomodoro.Interval, error) {
+
+  // Search last item of the user in the repository
   r.RLock()
   defer r.RUnlock()
 
   // Query and parse last row into Interval struct
-  last := pomodoro.Interval{}
-  err := r.db.QueryRow("SELECT * FROM interval ORDER BY id desc LIMIT 1").Scan(
-    &last.ID, &last.StartTime, &last.PlannedDuration,
-    &last.ActualDuration, &last.Category, &last.State,
-  )
+  last, err := scanInterval(r.db.QueryRowContext(ctx,
+    "SELECT "+intervalColumns+` FROM interval
+  WHERE NOT manual AND (?='' OR user_name = ?) ORDER BY id desc LIMIT 1`,
+    user, user))
 
   if err == sql.ErrNoRows {
     return last, pomodoro.ErrNoIntervals
@@ -142,63 +253,352 @@ func (r *dbRepo) Last() (pomodoro.Interval, error) {
   return last, nil
 }
 
-func (r *dbRepo) Breaks(n int) ([]pomodoro.Interval, error) {
-  // Search last n items of type break in the repository
+func (r *dbRepo) Breaks(ctx context.Context, user string,
+  n int) ([]pomodoro.Interval, error) {
+
+  // Search last n items of type break of the user in the repository
   r.RLock()
   defer r.RUnlock()
 
   // Define SELECT query for breaks
-  stmt := `SELECT * FROM interval WHERE category LIKE '%Break'
+  stmt := `SELECT ` + intervalColumns + ` FROM interval
+  WHERE category LIKE '%Break' AND NOT manual AND (?='' OR user_name = ?)
   ORDER BY id DESC LIMIT ?`
 
   // Query DB for breaks
-  rows, err := r.db.Query(stmt, n)
+  rows, err := r.db.QueryContext(ctx, stmt, user, user, n)
   if err != nil {
     return nil, err
   }
-  defer rows.Close()
 
   // Parse data into slice of Interval
-  data := []pomodoro.Interval{}
-  for rows.Next() {
-    i := pomodoro.Interval{}
-    err = rows.Scan(&i.ID, &i.StartTime, &i.PlannedDuration,
-      &i.ActualDuration, &i.Category, &i.State)
+  return scanIntervals(rows)
+}
+
+func (r *dbRepo) List(ctx context.Context,
+  q pomodoro.Query) ([]pomodoro.Interval, error) {
+
+  // Search items matching the query in the repository
+  r.RLock()
+  defer r.RUnlock()
+
+  stmt, args := sqliteDialect.list("interval", q)
+
+  rows, err := r.db.QueryContext(ctx, stmt, args...)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanIntervals(rows)
+}
+
+func (r *dbRepo) Delete(ctx context.Context, id int64,
+  changes ...pomodoro.Change) error {
+
+  // Delete entry from the repository
+  r.Lock()
+  defer r.Unlock()
+
+  exec := func(tx *sql.Tx) error {
+    res, err := tx.ExecContext(ctx, "DELETE FROM interval WHERE id=?", id)
     if err != nil {
-      return nil, err
+      return err
     }
 
-    data = append(data, i)
+    n, err := res.RowsAffected()
+    if err == nil && n == 0 {
+      err = fmt.Errorf("%w: %d", pomodoro.ErrInvalidID, id)
+    }
+    return err
   }
-  err = rows.Err()
+
+  return withChanges(ctx, r.db, sqliteAddChange, changes, exec)
+}
+
+// sqliteAddChange records an entry of the audit trail.
+const sqliteAddChange = `INSERT INTO interval_change
+  (interval_id, time, field, old_value, new_value, user_name)
+  VALUES(?,?,?,?,?,?)`
+
+func (r *dbRepo) Changes(ctx context.Context, id int64,
+  user string) ([]pomodoro.Change, error) {
+
+  // Search the audit trail of an interval, or all of it, seen by the user
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, "SELECT "+changeColumns+
+    ` FROM interval_change WHERE (?=0 OR interval_id=?)
+  AND (?='' OR user_name IN (?, '')) ORDER BY id`, id, id, user, user)
   if err != nil {
     return nil, err
   }
 
-  // Return data
-  return data, nil
+  return scanChanges(rows)
 }
 
-func (r *dbRepo) CategorySummary(day time.Time,
-  filter string) (time.Duration, error) {
+func (r *dbRepo) CategorySummary(ctx context.Context,
+  q pomodoro.Query) (time.Duration, error) {
 
-  // Return a daily summary
+  // Return a summary for the given period
   r.RLock()
   defer r.RUnlock()
 
-  // Define SELECT query for daily summary
-  stmt := `SELECT sum(actual_duration) FROM interval
-  WHERE category LIKE ? AND
-  strftime('%Y-%m-%d', start_time, 'localtime')=
-  strftime('%Y-%m-%d', ?, 'localtime')`
+  // Query intervals overlapping the period
+  where, args := sqliteDialect.where(q)
+  rows, err := r.db.QueryContext(ctx,
+    "SELECT "+intervalColumns+" FROM interval "+where, args...)
+  if err != nil {
+    return 0, err
+  }
 
-  var ds sql.NullInt64
-  err := r.db.QueryRow(stmt, filter, day).Scan(&ds)
+  data, err := scanIntervals(rows)
+  if err != nil {
+    return 0, err
+  }
 
+  // Add the part of each interval that falls within the period
   var d time.Duration
-  if ds.Valid {
-    d = time.Duration(ds.Int64)
+  for _, i := range data {
+    d += q.Duration(i)
   }
 
-  return d, err
-}
\ No newline at end of file
+  return d, nil
+}
+
+// Backup writes a copy of the database to path with VACUUM INTO, which
+// gives a consistent and compact copy while the database is in use.
+func (r *dbRepo) Backup(ctx context.Context, path string) error {
+  r.RLock()
+  defer r.RUnlock()
+
+  _, err := r.db.ExecContext(ctx, "VACUUM INTO ?", path)
+  return err
+}
+
+// sqliteURI returns the URI filename of path with query, escaping the
+// characters that would otherwise end the path.
+func sqliteURI(path, query string) string {
+  path = filepath.ToSlash(path)
+  if filepath.IsAbs(path) && path[0] != '/' {
+    // Windows paths start with their volume name
+    path = "/" + path
+  }
+
+  u := url.URL{Scheme: "file", Path: path, RawQuery: query}
+  return u.String()
+}
+
+// Restore checks the integrity of the backup at path and copies it over
+// the database with the online backup API. Backups of an older schema are
+// migrated afterwards; backups of a newer one are refused.
+func (r *dbRepo) Restore(ctx context.Context, path string) error {
+  src, err := sql.Open("sqlite3", sqliteURI(path, "mode=ro"))
+  if err != nil {
+    return err
+  }
+  defer src.Close()
+
+  if err := sqliteCheck(ctx, src); err != nil {
+    return fmt.Errorf("%s: %w", path, err)
+  }
+
+  r.Lock()
+  defer r.Unlock()
+
+  if err := sqliteCopy(ctx, r.db, src); err != nil {
+    return err
+  }
+
+  return sqliteMigrate(r.db)
+}
+
+// sqliteCopy replaces the content of dst with src using the online backup
+// API. The connections are released before it returns, as the repository
+// only has one.
+func sqliteCopy(ctx context.Context, dst, src *sql.DB) error {
+  srcConn, err := src.Conn(ctx)
+  if err != nil {
+    return err
+  }
+  defer srcConn.Close()
+
+  dstConn, err := dst.Conn(ctx)
+  if err != nil {
+    return err
+  }
+  defer dstConn.Close()
+
+  return dstConn.Raw(func(d any) error {
+    return srcConn.Raw(func(s any) error {
+      b, err := d.(*sqlite3.SQLiteConn).Backup("main",
+        s.(*sqlite3.SQLiteConn), "main")
+      if err != nil {
+        return err
+      }
+
+      if _, err := b.Step(-1); err != nil {
+        b.Finish()
+        return err
+      }
+
+      return b.Finish()
+    })
+  })
+}
+
+// sqliteCheck verifies that db is intact and has a schema this version
+// can use.
+func sqliteCheck(ctx context.Context, db *sql.DB) error {
+  rows, err := db.QueryContext(ctx, "PRAGMA integrity_check")
+  if err != nil {
+    return err
+  }
+  defer rows.Close()
+
+  problems := []string{}
+  for rows.Next() {
+    var msg string
+    if err := rows.Scan(&msg); err != nil {
+      return err
+    }
+    if msg != "ok" {
+      problems = append(problems, msg)
+    }
+  }
+  if err := rows.Err(); err != nil {
+    return err
+  }
+
+  if len(problems) > 0 {
+    return fmt.Errorf("%w: %s", ErrCorruptBackup, problems[0])
+  }
+
+  var version int
+  if err := db.QueryRowContext(ctx,
+    "PRAGMA user_version").Scan(&version); err != nil {
+    return err
+  }
+
+  if version == 0 || version > len(sqliteMigrations) {
+    return fmt.Errorf("%w: unknown schema version %d", ErrCorruptBackup,
+      version)
+  }
+
+  return nil
+}
+
+func (r *dbRepo) Totals(ctx context.Context,
+  user string) (pomodoro.Totals, error) {
+
+  r.RLock()
+  defer r.RUnlock()
+
+  // Sum up the intervals in the database rather than reading them all
+  stmt, args := sqliteDialect.totals("interval", user)
+  rows, err := r.db.QueryContext(ctx, stmt, args...)
+  if err != nil {
+    return pomodoro.Totals{}, err
+  }
+
+  return scanTotals(rows)
+}
+
+func (r *dbRepo) Users(ctx context.Context) ([]string, error) {
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, `SELECT user_name FROM interval
+  UNION SELECT user_name FROM task ORDER BY 1`)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanUsers(rows)
+}
+
+func (r *dbRepo) CreateTask(ctx context.Context,
+  t pomodoro.Task) (int64, error) {
+
+  // Add a task to the list
+  r.Lock()
+  defer r.Unlock()
+
+  res, err := r.db.ExecContext(ctx, `INSERT INTO task
+  (name, estimate, created, completed, source, external_id, user_name)
+  VALUES(?,?,?,?,?,?,?)`,
+    t.Name, t.Estimate, t.Created, nullTime(t.Completed), t.Source,
+    t.ExternalID, t.User)
+  if err != nil {
+    return 0, err
+  }
+
+  return res.LastInsertId()
+}
+
+func (r *dbRepo) UpdateTask(ctx context.Context, t pomodoro.Task) error {
+  // Update a task of the list
+  r.Lock()
+  defer r.Unlock()
+
+  res, err := r.db.ExecContext(ctx, `UPDATE task
+  SET name=?, estimate=?, created=?, completed=?, source=?, external_id=?,
+  user_name=? WHERE id=?`,
+    t.Name, t.Estimate, t.Created, nullTime(t.Completed), t.Source,
+    t.ExternalID, t.User, t.ID)
+  if err != nil {
+    return err
+  }
+
+  n, err := res.RowsAffected()
+  if err == nil && n == 0 {
+    err = fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, t.ID)
+  }
+  return err
+}
+
+func (r *dbRepo) TaskByID(ctx context.Context, id int64) (pomodoro.Task, error) {
+  // Search a task by ID
+  r.RLock()
+  defer r.RUnlock()
+
+  t, err := scanTask(r.db.QueryRowContext(ctx,
+    "SELECT "+taskColumns+" FROM task WHERE id=?", id))
+  if err == sql.ErrNoRows {
+    return t, fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+  }
+  return t, err
+}
+
+func (r *dbRepo) DeleteTask(ctx context.Context, id int64) error {
+  // Remove a task from the list
+  r.Lock()
+  defer r.Unlock()
+
+  res, err := r.db.ExecContext(ctx, "DELETE FROM task WHERE id=?", id)
+  if err != nil {
+    return err
+  }
+
+  n, err := res.RowsAffected()
+  if err == nil && n == 0 {
+    err = fmt.Errorf("%w: task %d", pomodoro.ErrInvalidID, id)
+  }
+  return err
+}
+
+func (r *dbRepo) Tasks(ctx context.Context,
+  user string) ([]pomodoro.Task, error) {
+
+  // Search every task in the list seen by the user
+  r.RLock()
+  defer r.RUnlock()
+
+  rows, err := r.db.QueryContext(ctx, "SELECT "+taskColumns+
+    " FROM task WHERE ?='' OR user_name IN (?, '') ORDER BY id", user, user)
+  if err != nil {
+    return nil, err
+  }
+
+  return scanTasks(rows)
+}
@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/repository"
+)
+
+func TestSQLiteChanges(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "pomo.db")
+
+	repo, err := repository.NewSQLite3Repo(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i := pomodoro.Interval{
+		StartTime:       time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+		PlannedDuration: 25 * time.Minute,
+		Category:        pomodoro.CategoryPomodoro,
+	}
+	if i.ID, err = repo.Create(ctx, i); err != nil {
+		t.Fatal(err)
+	}
+
+	edited := i
+	edited.Note = "lunch"
+	if err := repo.Update(ctx, edited, pomodoro.Change{IntervalID: i.ID,
+		Field: "note", NewValue: "lunch"}); err != nil {
+		t.Fatal(err)
+	}
+	changes, err := repo.Changes(ctx, i.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].NewValue != "lunch" {
+		t.Errorf("Expected the change of the note, got %v", changes)
+	}
+
+	// Neither the interval nor its change is saved if either fails
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("DROP TABLE interval_change"); err != nil {
+		t.Fatal(err)
+	}
+
+	edited.Note = "meeting"
+	if err := repo.Update(ctx, edited, pomodoro.Change{IntervalID: i.ID,
+		Field: "note", OldValue: "lunch", NewValue: "meeting"}); err == nil {
+		t.Fatal("Expected an error recording the change")
+	}
+	if err := repo.Delete(ctx, i.ID, pomodoro.Change{IntervalID: i.ID,
+		Field: pomodoro.FieldDeleted}); err == nil {
+		t.Fatal("Expected an error recording the deletion")
+	}
+
+	stored, err := repo.ByID(ctx, i.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Note != "lunch" {
+		t.Errorf("Expected the interval unchanged, got %+v", stored)
+	}
+}
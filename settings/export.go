@@ -0,0 +1,83 @@
+package settings
+
+import (
+	"strings"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/timesheet"
+)
+
+// Export configures the exports to time tracking tools and the
+// Timewarrior hook.
+type Export struct {
+	// Email is the user of the Toggl and Clockify entries.
+	Email string `mapstructure:"email"`
+	// Projects maps pomo projects, with their subprojects, to the project
+	// names of the tools.
+	Projects map[string]string `mapstructure:"projects"`
+	// Tasks maps task names to entry descriptions. Names are lower case,
+	// as config keys are.
+	Tasks map[string]string `mapstructure:"tasks"`
+	// Timewarrior appends each pomodoro done in the dashboard to the
+	// Timewarrior database TimewarriorDB, by default $TIMEWARRIORDB or
+	// ~/.timewarrior.
+	Timewarrior   bool   `mapstructure:"timewarrior"`
+	TimewarriorDB string `mapstructure:"timewarriordb"`
+}
+
+// mappingField splits a key like export.projects.work/api into the
+// mapping and the name mapped.
+func mappingField(key string) (string, string, bool) {
+	for _, m := range []string{"projects", "tasks"} {
+		name, ok := strings.CutPrefix(key, "export."+m+".")
+		if ok && name != "" {
+			return m, name, true
+		}
+	}
+
+	return "", "", false
+}
+
+// values returns the mappings that are set, by key.
+func (e Export) values() map[string]any {
+	values := map[string]any{}
+	for name, v := range e.Projects {
+		values["export.projects."+name] = v
+	}
+	for name, v := range e.Tasks {
+		values["export.tasks."+name] = v
+	}
+
+	return values
+}
+
+// Mapping returns the names of pomodoros in time tracking tools.
+func (e Export) Mapping() timesheet.Mapping {
+	return timesheet.Mapping{Projects: e.Projects, Tasks: e.Tasks}
+}
+
+// validateExport checks the export settings.
+func (s Settings) validateExport(fail func(key, format string,
+	args ...any)) {
+
+	e := s.Export
+	if e.Email != "" && !strings.Contains(e.Email, "@") {
+		fail("export.email", "%q is not an email address", e.Email)
+	}
+
+	for _, name := range sortedKeys(e.Projects) {
+		key := "export.projects." + name
+		if p, err := pomodoro.ParseProject(name); err != nil || p != name {
+			fail(key, "%q is not a project, use a path like work/api", name)
+		}
+		if strings.TrimSpace(e.Projects[name]) == "" {
+			fail(key, "needs a project name")
+		}
+	}
+
+	for _, name := range sortedKeys(e.Tasks) {
+		if strings.TrimSpace(e.Tasks[name]) == "" {
+			fail("export.tasks."+name, "needs a description")
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/notif"
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// Profile is a named work mode. Its unset fields keep the value of the
+// top-level setting.
+type Profile struct {
+	Pomodoro      time.Duration        `mapstructure:"pomo"`
+	ShortBreak    time.Duration        `mapstructure:"short"`
+	LongBreak     time.Duration        `mapstructure:"long"`
+	Cycle         int                  `mapstructure:"cycle"`
+	Notifications ProfileNotifications `mapstructure:"notifications"`
+}
+
+// ProfileNotifications overrides the notification settings in a profile.
+type ProfileNotifications struct {
+	Enabled  *bool  `mapstructure:"enabled"`
+	Severity string `mapstructure:"severity"`
+}
+
+// defaultProfiles are available without any configuration.
+func defaultProfiles() map[string]Profile {
+	return map[string]Profile{
+		"deep-work": {
+			Pomodoro:   50 * time.Minute,
+			ShortBreak: 10 * time.Minute,
+			LongBreak:  30 * time.Minute,
+		},
+		"study": {
+			Pomodoro:   25 * time.Minute,
+			ShortBreak: 5 * time.Minute,
+			LongBreak:  15 * time.Minute,
+		},
+		"meetings-day": {
+			Pomodoro:   15 * time.Minute,
+			ShortBreak: 3 * time.Minute,
+			LongBreak:  10 * time.Minute,
+		},
+	}
+}
+
+// profileFields holds a zero value of each profile setting by key, to
+// parse values of profiles that don't exist yet.
+var profileFields = map[string]any{
+	"pomo":                   time.Duration(0),
+	"short":                  time.Duration(0),
+	"long":                   time.Duration(0),
+	"cycle":                  0,
+	"notifications.enabled":  false,
+	"notifications.severity": "",
+}
+
+// values returns the profile settings that are set, by key.
+func (p Profile) values(name string) map[string]any {
+	prefix := "profiles." + name + "."
+	values := map[string]any{}
+
+	if p.Pomodoro != 0 {
+		values[prefix+"pomo"] = p.Pomodoro
+	}
+	if p.ShortBreak != 0 {
+		values[prefix+"short"] = p.ShortBreak
+	}
+	if p.LongBreak != 0 {
+		values[prefix+"long"] = p.LongBreak
+	}
+	if p.Cycle != 0 {
+		values[prefix+"cycle"] = p.Cycle
+	}
+	if p.Notifications.Enabled != nil {
+		values[prefix+"notifications.enabled"] = *p.Notifications.Enabled
+	}
+	if p.Notifications.Severity != "" {
+		values[prefix+"notifications.severity"] = p.Notifications.Severity
+	}
+
+	return values
+}
+
+// profileField splits a key like profiles.study.pomo into the profile name
+// and the setting.
+func profileField(key string) (string, string, bool) {
+	rest, ok := strings.CutPrefix(key, "profiles.")
+	if !ok {
+		return "", "", false
+	}
+
+	name, field, ok := strings.Cut(rest, ".")
+	if _, known := profileFields[field]; !ok || !known || name == "" {
+		return "", "", false
+	}
+
+	return name, field, true
+}
+
+// ProfileNames returns the sorted names of the profiles.
+func (s Settings) ProfileNames() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// WithProfile returns the settings with the profile name applied, or
+// without any profile if name is empty.
+func (s Settings) WithProfile(name string) (Settings, error) {
+	s.Profile = name
+	if name == "" {
+		return s, nil
+	}
+
+	p, ok := s.Profiles[name]
+	if !ok {
+		return s, fmt.Errorf("%w: unknown profile %q, use one of %s",
+			ErrInvalid, name, strings.Join(s.ProfileNames(), ", "))
+	}
+
+	if p.Pomodoro != 0 {
+		s.Pomodoro = p.Pomodoro
+	}
+	if p.ShortBreak != 0 {
+		s.ShortBreak = p.ShortBreak
+	}
+	if p.LongBreak != 0 {
+		s.LongBreak = p.LongBreak
+	}
+	if p.Cycle != 0 {
+		s.Cycle = p.Cycle
+	}
+	if p.Notifications.Enabled != nil {
+		s.Notifications.Enabled = *p.Notifications.Enabled
+	}
+	if p.Notifications.Severity != "" {
+		s.Notifications.Severity = p.Notifications.Severity
+	}
+
+	return s, nil
+}
+
+// validateProfiles checks the settings of each profile.
+func (s Settings) validateProfiles(fail func(key, format string,
+	args ...any)) {
+
+	if _, ok := s.Profiles[s.Profile]; s.Profile != "" && !ok {
+		fail("profile", "unknown profile %q, use one of %s", s.Profile,
+			strings.Join(s.ProfileNames(), ", "))
+	}
+
+	for _, name := range s.ProfileNames() {
+		p := s.Profiles[name]
+		prefix := "profiles." + name + "."
+
+		durations := []struct {
+			key string
+			d   time.Duration
+		}{
+			{"pomo", p.Pomodoro},
+			{"short", p.ShortBreak},
+			{"long", p.LongBreak},
+		}
+		for _, d := range durations {
+			if d.d != 0 && d.d < time.Second {
+				fail(prefix+d.key, "%s is too short, use a duration like 25m", d.d)
+			}
+		}
+
+		if p.Cycle < 0 {
+			fail(prefix+"cycle", "needs at least 1 pomodoro, got %d", p.Cycle)
+		}
+
+		switch p.Notifications.Severity {
+		case "", "low", "normal", "urgent":
+		default:
+			fail(prefix+"notifications.severity",
+				"must be low, normal or urgent, got %q", p.Notifications.Severity)
+		}
+	}
+}
+
+// severities maps the notification severity settings to notif ones.
+var severities = map[string]notif.Severity{
+	"low":    notif.SeverityLow,
+	"normal": notif.SeverityNormal,
+	"urgent": notif.SeverityUrgent,
+}
+
+// Configure sets the interval durations, cycle length, notifications and
+// profile of config from s.
+func (s Settings) Configure(config *pomodoro.IntervalConfig) {
+	config.PomodoroDuration = s.Pomodoro
+	config.ShortBreakDuration = s.ShortBreak
+	config.LongBreakDuration = s.LongBreak
+	config.CycleLength = s.Cycle
+	config.Notify = s.Notifications.Enabled
+	config.NotifySeverity = severities[s.Notifications.Severity]
+	config.Profile = s.Profile
+}
@@ -0,0 +1,479 @@
+// Package settings defines every pomo setting, their defaults and how
+// they're validated. Settings come from flags, the environment and the
+// config file through viper.
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/viper"
+)
+
+// ErrInvalid is returned for settings that fail validation.
+var ErrInvalid = errors.New("Invalid settings")
+
+// Settings holds every pomo setting. The mapstructure tags are the keys
+// used in the config file and by `pomo config`.
+type Settings struct {
+	DB         string        `mapstructure:"db"`
+	Pomodoro   time.Duration `mapstructure:"pomo"`
+	ShortBreak time.Duration `mapstructure:"short"`
+	LongBreak  time.Duration `mapstructure:"long"`
+	// Cycle is the number of pomodoros before a long break.
+	Cycle         int           `mapstructure:"cycle"`
+	Task          string        `mapstructure:"task"`
+	Timezone      string        `mapstructure:"timezone"`
+	DayStart      int           `mapstructure:"daystart"`
+	DBTimeout     time.Duration `mapstructure:"dbtimeout"`
+	ExcludeManual bool          `mapstructure:"excludemanual"`
+	BackupDir     string        `mapstructure:"backupdir"`
+	BackupKeep    int           `mapstructure:"backupkeep"`
+	AutoBackup    bool          `mapstructure:"autobackup"`
+	// Calendar is an .ics file of meetings to plan pomodoros around.
+	Calendar string `mapstructure:"calendar"`
+	// Metrics is the address to serve Prometheus metrics on, such as
+	// localhost:9191, none if empty.
+	Metrics string `mapstructure:"metrics"`
+	// Profile is the profile in use, none if empty.
+	Profile string `mapstructure:"profile"`
+	// User names whose history is used in a shared database, the user
+	// logged in if empty.
+	User string `mapstructure:"user"`
+	// Team lists, separated by commas, the addresses of the metrics
+	// servers of team members to show on the team board, such as
+	// http://alice:9191.
+	Team string `mapstructure:"team"`
+
+	Notifications Notifications `mapstructure:"notifications"`
+	Theme         Theme         `mapstructure:"theme"`
+	Goals         Goals         `mapstructure:"goals"`
+	Keys          Keys          `mapstructure:"keys"`
+	Export        Export        `mapstructure:"export"`
+
+	Profiles map[string]Profile `mapstructure:"profiles"`
+}
+
+// Notifications configures the desktop notification sent when an
+// interval ends.
+type Notifications struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Severity is low, normal or urgent.
+	Severity string `mapstructure:"severity"`
+}
+
+// Theme holds the dashboard colors, as names like "blue" or terminal
+// color numbers from 0 to 255.
+type Theme struct {
+	Pomodoro string `mapstructure:"pomodoro"`
+	Break    string `mapstructure:"break"`
+	Accent   string `mapstructure:"accent"`
+}
+
+// Goals are the number of pomodoros to complete each day and each week,
+// 0 for none.
+type Goals struct {
+	Daily  int `mapstructure:"daily"`
+	Weekly int `mapstructure:"weekly"`
+}
+
+// Keys are the dashboard key bindings, one character each.
+type Keys struct {
+	Start   string `mapstructure:"start"`
+	Pause   string `mapstructure:"pause"`
+	Quit    string `mapstructure:"quit"`
+	History string `mapstructure:"history"`
+	// Profile switches to the next profile between intervals.
+	Profile string `mapstructure:"profile"`
+	// Internal and External log an interruption of the running pomodoro.
+	Internal string `mapstructure:"internal"`
+	External string `mapstructure:"external"`
+	// Note attaches a note to the latest pomodoro.
+	Note string `mapstructure:"note"`
+	// Task picks the next open task of the task list.
+	Task string `mapstructure:"task"`
+	// Filter narrows the charts to the next project.
+	Filter string `mapstructure:"filter"`
+	// Shorten plans the next pomodoro to end before a meeting.
+	Shorten string `mapstructure:"shorten"`
+	// Board shows and hides the team board.
+	Board string `mapstructure:"board"`
+}
+
+// Defaults returns the settings used when nothing else is set. DB is left
+// empty, as its default depends on the environment.
+func Defaults() Settings {
+	return Settings{
+		Pomodoro:   25 * time.Minute,
+		ShortBreak: 5 * time.Minute,
+		LongBreak:  15 * time.Minute,
+		Cycle:      4,
+		DBTimeout:  5 * time.Second,
+		BackupKeep: 7,
+		Notifications: Notifications{
+			Enabled:  true,
+			Severity: "normal",
+		},
+		Theme: Theme{
+			Pomodoro: "blue",
+			Break:    "yellow",
+			Accent:   "220",
+		},
+		Keys: Keys{
+			Start:    "s",
+			Pause:    "p",
+			Quit:     "q",
+			History:  "h",
+			Profile:  "m",
+			Internal: "i",
+			External: "e",
+			Note:     "n",
+			Task:     "t",
+			Filter:   "f",
+			Shorten:  "o",
+			Board:    "w",
+		},
+		Profiles: defaultProfiles(),
+	}
+}
+
+// SetDefaults registers the default of every setting in v, so they're all
+// listed by v.AllKeys.
+func SetDefaults(v *viper.Viper) {
+	for key, value := range Defaults().values() {
+		v.SetDefault(key, value)
+	}
+}
+
+// values returns the settings by key, including the profile settings that
+// are set.
+func (s Settings) values() map[string]any {
+	values := map[string]any{
+		"db":                     s.DB,
+		"pomo":                   s.Pomodoro,
+		"short":                  s.ShortBreak,
+		"long":                   s.LongBreak,
+		"cycle":                  s.Cycle,
+		"task":                   s.Task,
+		"timezone":               s.Timezone,
+		"daystart":               s.DayStart,
+		"dbtimeout":              s.DBTimeout,
+		"excludemanual":          s.ExcludeManual,
+		"backupdir":              s.BackupDir,
+		"backupkeep":             s.BackupKeep,
+		"autobackup":             s.AutoBackup,
+		"calendar":               s.Calendar,
+		"metrics":                s.Metrics,
+		"notifications.enabled":  s.Notifications.Enabled,
+		"notifications.severity": s.Notifications.Severity,
+		"theme.pomodoro":         s.Theme.Pomodoro,
+		"theme.break":            s.Theme.Break,
+		"theme.accent":           s.Theme.Accent,
+		"goals.daily":            s.Goals.Daily,
+		"goals.weekly":           s.Goals.Weekly,
+		"keys.start":             s.Keys.Start,
+		"keys.pause":             s.Keys.Pause,
+		"keys.quit":              s.Keys.Quit,
+		"keys.history":           s.Keys.History,
+		"keys.profile":           s.Keys.Profile,
+		"keys.internal":          s.Keys.Internal,
+		"keys.external":          s.Keys.External,
+		"keys.note":              s.Keys.Note,
+		"keys.task":              s.Keys.Task,
+		"keys.filter":            s.Keys.Filter,
+		"keys.shorten":           s.Keys.Shorten,
+		"keys.board":             s.Keys.Board,
+		"export.email":           s.Export.Email,
+		"export.timewarrior":     s.Export.Timewarrior,
+		"export.timewarriordb":   s.Export.TimewarriorDB,
+		"profile":                s.Profile,
+		"user":                   s.User,
+		"team":                   s.Team,
+	}
+
+	for name, p := range s.Profiles {
+		for k, v := range p.values(name) {
+			values[k] = v
+		}
+	}
+	for k, v := range s.Export.values() {
+		values[k] = v
+	}
+
+	return values
+}
+
+// Names returns the sorted keys of all settings, and of the profile
+// settings that are set.
+func (s Settings) Names() []string {
+	values := s.values()
+
+	names := make([]string, 0, len(values))
+	for k := range values {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the value of setting key, formatted like in the config
+// file.
+func (s Settings) Get(key string) (string, error) {
+	v, ok := s.values()[key]
+	if !ok {
+		return "", fmt.Errorf("%w: unknown setting %q", ErrInvalid, key)
+	}
+
+	return fmt.Sprint(v), nil
+}
+
+// Parse converts value to the type of setting key, so it can be stored
+// in a config file.
+func Parse(key, value string) (any, error) {
+	def, ok := Defaults().values()[key]
+	if _, field, isProfile := profileField(key); isProfile {
+		def, ok = profileFields[field]
+	}
+	if _, _, isMapping := mappingField(key); isMapping {
+		def, ok = "", true
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown setting %q", ErrInvalid, key)
+	}
+
+	var (
+		v   any = value
+		err error
+	)
+
+	switch def.(type) {
+	case bool:
+		v, err = strconv.ParseBool(value)
+	case int:
+		v, err = strconv.Atoi(value)
+	case time.Duration:
+		_, err = time.ParseDuration(value)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %q is not a valid %T", ErrInvalid, key,
+			value, def)
+	}
+
+	return v, nil
+}
+
+// Load decodes and validates the settings in v. Unknown keys and values
+// of the wrong type are errors. The settings are returned with the
+// validation errors, so they can still be shown.
+func Load(v *viper.Viper) (Settings, error) {
+	s := Settings{}
+	if err := v.Unmarshal(&s); err != nil {
+		return s, fmt.Errorf("%w: %s", ErrInvalid, err)
+	}
+
+	known := Defaults().values()
+	errs := []error{}
+	for _, key := range v.AllKeys() {
+		_, _, isProfile := profileField(key)
+		_, _, isMapping := mappingField(key)
+		if _, ok := known[key]; !ok && !isProfile && !isMapping {
+			errs = append(errs, fmt.Errorf("%s: unknown setting", key))
+		}
+	}
+
+	if err := s.validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return s, invalid(errs)
+}
+
+// reservedKeys are used by the dashboard history view and can't be bound.
+const reservedKeys = "jk+-=cdxa"
+
+// Validate checks every setting and reports all problems found.
+func (s Settings) Validate() error {
+	return invalid([]error{s.validate()})
+}
+
+func (s Settings) validate() error {
+	errs := []error{}
+	fail := func(key, format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key},
+			args...)...))
+	}
+
+	durations := []struct {
+		key string
+		d   time.Duration
+	}{
+		{"pomo", s.Pomodoro},
+		{"short", s.ShortBreak},
+		{"long", s.LongBreak},
+	}
+	for _, d := range durations {
+		if d.d < time.Second {
+			fail(d.key, "%s is too short, use a duration like 25m", d.d)
+		}
+	}
+
+	if s.DBTimeout <= 0 {
+		fail("dbtimeout", "must be positive, got %s", s.DBTimeout)
+	}
+
+	if s.Cycle < 1 {
+		fail("cycle", "needs at least 1 pomodoro, got %d", s.Cycle)
+	}
+
+	if s.DayStart < 0 || s.DayStart > 23 {
+		fail("daystart", "hour must be 0-23, got %d", s.DayStart)
+	}
+
+	if s.Timezone != "" {
+		if _, err := time.LoadLocation(s.Timezone); err != nil {
+			fail("timezone", "unknown time zone %q", s.Timezone)
+		}
+	}
+
+	if s.Metrics != "" {
+		if _, _, err := net.SplitHostPort(s.Metrics); err != nil {
+			fail("metrics", "must be an address like localhost:9191, got %q",
+				s.Metrics)
+		}
+	}
+
+	for _, m := range s.TeamURLs() {
+		u, err := url.Parse(m)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+			fail("team", "must list addresses like http://alice:9191, got %q", m)
+		}
+	}
+
+	if s.BackupKeep < 0 {
+		fail("backupkeep", "can't be negative, got %d", s.BackupKeep)
+	}
+
+	switch s.Notifications.Severity {
+	case "low", "normal", "urgent":
+	default:
+		fail("notifications.severity", "must be low, normal or urgent, got %q",
+			s.Notifications.Severity)
+	}
+
+	colors := map[string]string{
+		"theme.pomodoro": s.Theme.Pomodoro,
+		"theme.break":    s.Theme.Break,
+		"theme.accent":   s.Theme.Accent,
+	}
+	for _, key := range sortedKeys(colors) {
+		if _, err := Color(colors[key]); err != nil {
+			fail(key, "%s", err)
+		}
+	}
+
+	if s.Goals.Daily < 0 {
+		fail("goals.daily", "can't be negative, got %d", s.Goals.Daily)
+	}
+	if s.Goals.Weekly < 0 {
+		fail("goals.weekly", "can't be negative, got %d", s.Goals.Weekly)
+	}
+
+	keys := map[string]string{
+		"keys.start":    s.Keys.Start,
+		"keys.pause":    s.Keys.Pause,
+		"keys.quit":     s.Keys.Quit,
+		"keys.history":  s.Keys.History,
+		"keys.profile":  s.Keys.Profile,
+		"keys.internal": s.Keys.Internal,
+		"keys.external": s.Keys.External,
+		"keys.note":     s.Keys.Note,
+		"keys.task":     s.Keys.Task,
+		"keys.filter":   s.Keys.Filter,
+		"keys.shorten":  s.Keys.Shorten,
+		"keys.board":    s.Keys.Board,
+	}
+	bound := map[string]string{}
+	for _, key := range sortedKeys(keys) {
+		k := strings.ToLower(keys[key])
+		switch {
+		case utf8.RuneCountInString(k) != 1:
+			fail(key, "must be a single character, got %q", keys[key])
+		case strings.Contains(reservedKeys, k):
+			fail(key, "%q is used by the history view", k)
+		case bound[k] != "":
+			fail(key, "%q is already bound to %s", k, bound[k])
+		default:
+			bound[k] = key
+		}
+	}
+
+	s.validateProfiles(fail)
+	s.validateExport(fail)
+
+	return errors.Join(errs...)
+}
+
+// TeamURLs returns the addresses listed in the team setting.
+func (s Settings) TeamURLs() []string {
+	urls := []string{}
+	for _, u := range strings.Split(s.Team, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			urls = append(urls, u)
+		}
+	}
+
+	return urls
+}
+
+// invalid wraps the errors found, if any, in ErrInvalid.
+func invalid(errs []error) error {
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+
+	return fmt.Errorf("%w:\n%w", ErrInvalid, err)
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// colorNames are the names of the first 8 terminal colors, in order.
+var colorNames = []string{
+	"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white",
+}
+
+// Color returns the terminal color number of name, which is a color name
+// or a number from 0 to 255.
+func Color(name string) (int, error) {
+	for n, c := range colorNames {
+		if strings.EqualFold(c, name) {
+			return n, nil
+		}
+	}
+
+	n, err := strconv.Atoi(name)
+	if err != nil || n < 0 || n > 255 {
+		return 0, fmt.Errorf("unknown color %q, use %s or 0-255", name,
+			strings.Join(colorNames, ", "))
+	}
+
+	return n, nil
+}
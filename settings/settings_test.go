@@ -0,0 +1,223 @@
+package settings_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/xasterKies/pomanalyzer/settings"
+)
+
+func load(t *testing.T, yaml string) (settings.Settings, error) {
+	t.Helper()
+
+	v := viper.New()
+	settings.SetDefaults(v)
+	v.SetConfigType("yaml")
+	if err := v.ReadConfig(strings.NewReader(yaml)); err != nil {
+		t.Fatal(err)
+	}
+
+	return settings.Load(v)
+}
+
+func TestLoad(t *testing.T) {
+	s, err := load(t, `
+pomo: 50m
+cycle: 3
+notifications:
+  enabled: false
+theme:
+  pomodoro: green
+keys:
+  start: b
+goals:
+  daily: 8
+export:
+  email: me@example.com
+  projects:
+    work/api: Acme API
+  tasks:
+    Write Report: Q4 report
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if s.Pomodoro != 50*time.Minute || s.ShortBreak != 5*time.Minute ||
+		s.Cycle != 3 || s.Notifications.Enabled || s.Theme.Pomodoro != "green" ||
+		s.Theme.Break != "yellow" || s.Keys.Start != "b" || s.Goals.Daily != 8 {
+		t.Errorf("Unexpected settings %+v\n", s)
+	}
+
+	m := s.Export.Mapping()
+	if m.Project("work/api/auth") != "Acme API" ||
+		m.Tasks["write report"] != "Q4 report" {
+		t.Errorf("Unexpected export mapping %+v\n", m)
+	}
+	if v, err := s.Get("export.projects.work/api"); err != nil || v != "Acme API" {
+		t.Errorf("Expected export.projects.work/api Acme API, got %q, %v", v, err)
+	}
+}
+
+func TestLoadInvalid(t *testing.T) {
+	testCases := []struct {
+		name    string
+		yaml    string
+		expMsgs []string
+	}{
+		{name: "Duration", yaml: "pomo: 25x",
+			expMsgs: []string{"pomo"}},
+		{name: "NoUnit", yaml: "short: 5",
+			expMsgs: []string{"short: 5ns is too short"}},
+		{name: "UnknownKey", yaml: "pomodoro: 25m",
+			expMsgs: []string{"pomodoro"}},
+		{name: "Several", yaml: "cycle: 0\ndaystart: 24\ntheme:\n  break: pink",
+			expMsgs: []string{"cycle:", "daystart:", `theme.break: unknown color "pink"`}},
+		{name: "Keys", yaml: "keys:\n  start: q\n  history: j",
+			expMsgs: []string{`keys.start: "q" is already bound`,
+				`keys.history: "j" is used by the history view`}},
+		{name: "Timezone", yaml: "timezone: Mars/Olympus",
+			expMsgs: []string{"timezone: unknown time zone"}},
+		{name: "Metrics", yaml: "metrics: 9191",
+			expMsgs: []string{`metrics: must be an address like localhost:9191, got "9191"`}},
+		{name: "Team", yaml: "team: http://alice:9191, bob:9191",
+			expMsgs: []string{`team: must list addresses like http://alice:9191, got "bob:9191"`}},
+		{name: "Severity", yaml: "notifications:\n  severity: loud",
+			expMsgs: []string{"notifications.severity"}},
+		{name: "UnknownProfile", yaml: "profile: nap",
+			expMsgs: []string{`profile: unknown profile "nap"`}},
+		{name: "Profile", yaml: "profiles:\n  nap:\n    pomo: 1ms\n    colour: red",
+			expMsgs: []string{"profiles.nap.pomo: 1ms is too short",
+				"profiles.nap.colour: unknown setting"}},
+		{name: "Export", yaml: "export:\n  email: me\n  projects:\n    Work API: Acme",
+			expMsgs: []string{`export.email: "me" is not an email address`,
+				`export.projects.work api: "work api" is not a project`}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := load(t, tc.yaml)
+			if !errors.Is(err, settings.ErrInvalid) {
+				t.Fatalf("Expected error %q, got %q", settings.ErrInvalid, err)
+			}
+
+			for _, msg := range tc.expMsgs {
+				if !strings.Contains(err.Error(), msg) {
+					t.Errorf("Expected %q in error, got %q", msg, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		key   string
+		value string
+		exp   any
+		fails bool
+	}{
+		{key: "pomo", value: "50m", exp: "50m"},
+		{key: "pomo", value: "fifty", fails: true},
+		{key: "cycle", value: "3", exp: 3},
+		{key: "cycle", value: "three", fails: true},
+		{key: "notifications.enabled", value: "false", exp: false},
+		{key: "theme.accent", value: "cyan", exp: "cyan"},
+		{key: "export.projects.work", value: "Acme", exp: "Acme"},
+		{key: "nope", value: "1", fails: true},
+	}
+
+	for _, tc := range testCases {
+		v, err := settings.Parse(tc.key, tc.value)
+		if tc.fails {
+			if !errors.Is(err, settings.ErrInvalid) {
+				t.Errorf("Expected error parsing %s=%s, got %q", tc.key, tc.value,
+					err)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v != tc.exp {
+			t.Errorf("Expected %v for %s, got %v instead\n", tc.exp, tc.key, v)
+		}
+	}
+}
+
+func TestColor(t *testing.T) {
+	testCases := map[string]int{"black": 0, "Blue": 4, "white": 7, "220": 220}
+	for name, exp := range testCases {
+		n, err := settings.Color(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != exp {
+			t.Errorf("Expected color %d for %q, got %d instead\n", exp, name, n)
+		}
+	}
+
+	for _, name := range []string{"pink", "256", "-1"} {
+		if _, err := settings.Color(name); err == nil {
+			t.Errorf("Expected error for color %q", name)
+		}
+	}
+}
+
+func TestWithProfile(t *testing.T) {
+	s, err := load(t, `
+pomo: 20m
+cycle: 3
+profile: writing
+profiles:
+  writing:
+    pomo: 40m
+    notifications:
+      enabled: false
+  study:
+    long: 20m
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := strings.Join(s.ProfileNames(), ",")
+	if names != "deep-work,meetings-day,study,writing" {
+		t.Errorf("Expected built-in and configured profiles, got %q", names)
+	}
+
+	p, err := s.WithProfile(s.Profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Pomodoro != 40*time.Minute || p.ShortBreak != 5*time.Minute ||
+		p.Cycle != 3 || p.Notifications.Enabled {
+		t.Errorf("Unexpected settings for profile writing %+v\n", p)
+	}
+
+	// Configured fields are merged with the built-in profile
+	p, err = s.WithProfile("study")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Pomodoro != 25*time.Minute || p.LongBreak != 20*time.Minute {
+		t.Errorf("Unexpected settings for profile study %+v\n", p)
+	}
+
+	if _, err := s.WithProfile("nap"); !errors.Is(err, settings.ErrInvalid) {
+		t.Errorf("Expected error %q, got %q", settings.ErrInvalid, err)
+	}
+
+	v, err := s.Get("profiles.writing.pomo")
+	if err != nil || v != "40m0s" {
+		t.Errorf("Expected profiles.writing.pomo 40m0s, got %q, %v", v, err)
+	}
+
+	if _, err := settings.Parse("profiles.new.cycle", "2"); err != nil {
+		t.Errorf("Expected settings of new profiles to parse, got %q", err)
+	}
+}
@@ -0,0 +1,86 @@
+// Package tasksync reads tasks kept in todo.txt files and Taskwarrior
+// exports, and writes the pomodoros done for them back.
+package tasksync
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// Sources recorded on the imported tasks.
+const (
+	SourceTodoTxt     = "todo.txt"
+	SourceTaskwarrior = "taskwarrior"
+)
+
+// ErrUnknownFormat is returned for a format that isn't supported.
+var ErrUnknownFormat = errors.New("Unknown task format")
+
+// Detect returns the source whose format data is in. Taskwarrior exports
+// are JSON, anything else is read as todo.txt.
+func Detect(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '[' || trimmed[0] == '{') {
+		return SourceTaskwarrior
+	}
+
+	return SourceTodoTxt
+}
+
+// ParseSource checks a source name given by the user, such as "todotxt".
+func ParseSource(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "todo.txt", "todotxt", "todo":
+		return SourceTodoTxt, nil
+	case "taskwarrior", "task", "tw":
+		return SourceTaskwarrior, nil
+	}
+
+	return "", fmt.Errorf("%w: %q, use todo.txt or taskwarrior",
+		ErrUnknownFormat, name)
+}
+
+// Read returns the tasks in data, which is in the format of source.
+func Read(source string, data []byte) ([]pomodoro.Task, error) {
+	switch source {
+	case SourceTodoTxt:
+		return ReadTodoTxt(bytes.NewReader(data))
+	case SourceTaskwarrior:
+		return ReadTaskwarrior(bytes.NewReader(data))
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, source)
+}
+
+// Write copies data, in the format of source, to w with the pomodoros done
+// for each task, by external ID.
+func Write(source string, data []byte, w io.Writer,
+	pomodoros map[string]int) error {
+
+	switch source {
+	case SourceTodoTxt:
+		return WriteTodoTxt(bytes.NewReader(data), w, pomodoros)
+	case SourceTaskwarrior:
+		return WriteTaskwarrior(bytes.NewReader(data), w, pomodoros)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, source)
+}
+
+// Pomodoros returns the pomodoros done for the tasks imported from source,
+// by external ID.
+func Pomodoros(tasks []pomodoro.TaskProgress, source string) map[string]int {
+	counts := map[string]int{}
+	for _, t := range tasks {
+		if t.Source == source {
+			counts[t.ExternalID] = t.Pomodoros
+		}
+	}
+
+	return counts
+}
@@ -0,0 +1,135 @@
+package tasksync_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/tasksync"
+)
+
+const todoTxt = `(A) 2026-10-18 Write report +thesis @desk est:3 due:2026-10-20
+x 2026-10-18 2026-10-16 Review PR +work pomo:1
+
+Read https://example.com/paper est:1
+`
+
+func TestTodoTxt(t *testing.T) {
+	if s := tasksync.Detect([]byte(todoTxt)); s != tasksync.SourceTodoTxt {
+		t.Fatalf("Expected %q, got %q instead\n", tasksync.SourceTodoTxt, s)
+	}
+
+	tasks, err := tasksync.Read(tasksync.SourceTodoTxt, []byte(todoTxt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := []struct {
+		name     string
+		estimate int
+		done     bool
+	}{
+		{"Write report +thesis @desk", 3, false},
+		{"Review PR +work", 0, true},
+		{"Read https://example.com/paper", 1, false},
+	}
+	if len(tasks) != len(exp) {
+		t.Fatalf("Expected %d tasks, got %d instead\n", len(exp), len(tasks))
+	}
+	for k, e := range exp {
+		got := tasks[k]
+		if got.Name != e.name || got.ExternalID != e.name ||
+			got.Estimate != e.estimate || got.Done() != e.done {
+			t.Errorf("Expected %q estimated at %d, done %t, got %+v", e.name,
+				e.estimate, e.done, got)
+		}
+	}
+
+	var out bytes.Buffer
+	err = tasksync.Write(tasksync.SourceTodoTxt, []byte(todoTxt), &out,
+		map[string]int{
+			"Write report +thesis @desk": 2,
+			"Review PR +work":            4,
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expOut := `(A) 2026-10-18 Write report +thesis @desk est:3 due:2026-10-20 pomo:2
+x 2026-10-18 2026-10-16 Review PR +work pomo:4
+
+Read https://example.com/paper est:1
+`
+	if out.String() != expOut {
+		t.Errorf("Expected:\n%s\ngot:\n%s", expOut, out.String())
+	}
+
+	_, err = tasksync.Read(tasksync.SourceTodoTxt, []byte("Plan est:lots\n"))
+	if err == nil {
+		t.Error("Expected error for an invalid estimate, got nil")
+	}
+}
+
+const twExport = `[
+{"id":1,"description":"Write report","entry":"20261018T090000Z","estimate":2,"status":"pending","uuid":"a1","annotations":[{"entry":"20261018T090000Z","description":"pomanalyzer: 1 pomodoro"},{"entry":"20261018T090000Z","description":"see notes"}]},
+{"id":0,"description":"Review PR","end":"20261018T120000Z","status":"completed","uuid":"b2"},
+{"id":0,"description":"Old idea","status":"deleted","uuid":"c3"}
+]`
+
+func TestTaskwarrior(t *testing.T) {
+	if s := tasksync.Detect([]byte(twExport)); s != tasksync.SourceTaskwarrior {
+		t.Fatalf("Expected %q, got %q instead\n", tasksync.SourceTaskwarrior, s)
+	}
+
+	// Older versions export one task per line
+	lines := strings.Trim(twExport, "[]\n")
+	for _, data := range []string{twExport, lines} {
+		tasks, err := tasksync.Read(tasksync.SourceTaskwarrior, []byte(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(tasks) != 2 {
+			t.Fatalf("Expected 2 tasks, got %d instead\n", len(tasks))
+		}
+		if tasks[0].ExternalID != "a1" || tasks[0].Estimate != 2 ||
+			tasks[0].Done() {
+			t.Errorf("Expected open task a1 estimated at 2, got %+v", tasks[0])
+		}
+		end := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+		if !tasks[1].Completed.Equal(end) {
+			t.Errorf("Expected task b2 done at 12:00, got %+v", tasks[1])
+		}
+	}
+
+	var out bytes.Buffer
+	err := tasksync.Write(tasksync.SourceTaskwarrior, []byte(twExport), &out,
+		map[string]int{"a1": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []map[string]any{}
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0]["uuid"] != "a1" {
+		t.Fatalf("Expected only task a1, got %v", got)
+	}
+
+	annotations := got[0]["annotations"].([]any)
+	descriptions := []string{}
+	for _, a := range annotations {
+		descriptions = append(descriptions,
+			a.(map[string]any)["description"].(string))
+	}
+	exp := "see notes|pomanalyzer: 3 pomodoros"
+	if d := strings.Join(descriptions, "|"); d != exp {
+		t.Errorf("Expected annotations %q, got %q instead\n", exp, d)
+	}
+	if got[0]["estimate"] != 2.0 {
+		t.Errorf("Expected other fields to be kept, got %v", got[0])
+	}
+}
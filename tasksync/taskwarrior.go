@@ -0,0 +1,170 @@
+package tasksync
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// twLayout is the time format of Taskwarrior exports.
+const twLayout = "20060102T150405Z"
+
+// twAnnotation starts the annotation pomo keeps on Taskwarrior tasks.
+const twAnnotation = "pomanalyzer:"
+
+// twTask holds the fields of a Taskwarrior task read by pomo. Estimate is
+// an optional user defined attribute.
+type twTask struct {
+	UUID        string  `json:"uuid"`
+	Description string  `json:"description"`
+	Status      string  `json:"status"`
+	End         string  `json:"end"`
+	Estimate    float64 `json:"estimate"`
+}
+
+// readTaskwarrior decodes a "task export", which is a JSON array or, for
+// older versions, one object per line.
+func readTaskwarrior(r io.Reader) ([]json.RawMessage, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	list := []json.RawMessage{}
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &list); err != nil {
+			return nil, fmt.Errorf("reading Taskwarrior export: %w", err)
+		}
+		return list, nil
+	}
+
+	for n, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimRight(bytes.TrimSpace(line), ",")
+		if len(line) == 0 {
+			continue
+		}
+
+		if !json.Valid(line) {
+			return nil, fmt.Errorf("reading Taskwarrior export: line %d is not JSON",
+				n+1)
+		}
+		list = append(list, json.RawMessage(line))
+	}
+
+	return list, nil
+}
+
+// ReadTaskwarrior returns the pending and completed tasks of a
+// "task export". Deleted tasks and recurring templates are skipped.
+func ReadTaskwarrior(r io.Reader) ([]pomodoro.Task, error) {
+	list, err := readTaskwarrior(r)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []pomodoro.Task{}
+	for _, raw := range list {
+		tw := twTask{}
+		if err := json.Unmarshal(raw, &tw); err != nil {
+			return nil, fmt.Errorf("reading Taskwarrior export: %w", err)
+		}
+
+		switch tw.Status {
+		case "pending", "waiting", "completed":
+		default:
+			continue
+		}
+
+		if tw.UUID == "" {
+			return nil, fmt.Errorf("%w: Taskwarrior task %q has no uuid",
+				pomodoro.ErrInvalidTask, tw.Description)
+		}
+
+		t := pomodoro.Task{
+			Name:       tw.Description,
+			Estimate:   int(tw.Estimate + 0.5),
+			Source:     SourceTaskwarrior,
+			ExternalID: tw.UUID,
+		}
+
+		if tw.Status == "completed" {
+			t.Completed = time.Now()
+			if end, err := time.Parse(twLayout, tw.End); err == nil {
+				t.Completed = end
+			}
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	return tasks, nil
+}
+
+// WriteTaskwarrior writes the tasks of a "task export" read from r that
+// have pomodoros done to w, as a JSON array for "task import". Each gets
+// an annotation with the pomodoros done, replacing the one written
+// before. Other fields are kept as they are.
+func WriteTaskwarrior(r io.Reader, w io.Writer,
+	pomodoros map[string]int) error {
+
+	list, err := readTaskwarrior(r)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Format(twLayout)
+	out := []map[string]any{}
+
+	for _, raw := range list {
+		task := map[string]any{}
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return fmt.Errorf("reading Taskwarrior export: %w", err)
+		}
+
+		uuid, _ := task["uuid"].(string)
+		n := pomodoros[uuid]
+		if n == 0 {
+			continue
+		}
+
+		text := fmt.Sprintf("%s %d pomodoros", twAnnotation, n)
+		if n == 1 {
+			text = twAnnotation + " 1 pomodoro"
+		}
+
+		annotations := []any{}
+		if list, ok := task["annotations"].([]any); ok {
+			for _, a := range list {
+				if m, ok := a.(map[string]any); ok {
+					d, _ := m["description"].(string)
+					if strings.HasPrefix(d, twAnnotation) {
+						continue
+					}
+				}
+				annotations = append(annotations, a)
+			}
+		}
+
+		task["annotations"] = append(annotations, map[string]any{
+			"entry":       now,
+			"description": text,
+		})
+		task["modified"] = now
+
+		out = append(out, task)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,169 @@
+package tasksync
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// todo.txt keys read and written by pomo. The estimate is read from
+// est:N, and the pomodoros done are written to pomo:N.
+const (
+	todoEstimateKey  = "est"
+	todoPomodorosKey = "pomo"
+)
+
+var (
+	todoPriority = regexp.MustCompile(`^\([A-Z]\)$`)
+	todoDate     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	todoKeyValue = regexp.MustCompile(`^([^\s:]+):([^\s:/][^\s]*)$`)
+)
+
+// todoLine is a parsed line of a todo.txt file.
+type todoLine struct {
+	done      bool
+	completed time.Time
+	// head are the completion mark, priority and dates, kept as they are
+	head []string
+	// words is the description, with its projects, contexts and key:value
+	// pairs
+	words []string
+}
+
+func parseTodoLine(line string) todoLine {
+	l := todoLine{}
+	fields := strings.Fields(line)
+
+	if len(fields) > 0 && fields[0] == "x" {
+		l.done = true
+		l.head = append(l.head, fields[0])
+		fields = fields[1:]
+
+		if len(fields) > 0 && todoDate.MatchString(fields[0]) {
+			l.completed, _ = time.ParseInLocation("2006-01-02", fields[0],
+				time.Local)
+			l.head = append(l.head, fields[0])
+			fields = fields[1:]
+		}
+	}
+
+	if len(fields) > 0 && !l.done && todoPriority.MatchString(fields[0]) {
+		l.head = append(l.head, fields[0])
+		fields = fields[1:]
+	}
+
+	if len(fields) > 0 && todoDate.MatchString(fields[0]) {
+		l.head = append(l.head, fields[0])
+		fields = fields[1:]
+	}
+
+	l.words = fields
+	return l
+}
+
+// name is the description without its key:value pairs. It identifies
+// the task, so editing the description makes it a new task.
+func (l todoLine) name() string {
+	words := []string{}
+	for _, w := range l.words {
+		if !todoKeyValue.MatchString(w) {
+			words = append(words, w)
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// value returns the value of key:value pair key.
+func (l todoLine) value(key string) (string, bool) {
+	for _, w := range l.words {
+		if m := todoKeyValue.FindStringSubmatch(w); m != nil && m[1] == key {
+			return m[2], true
+		}
+	}
+
+	return "", false
+}
+
+// set replaces the value of key:value pair key, or adds it.
+func (l *todoLine) set(key, value string) {
+	for k, w := range l.words {
+		if m := todoKeyValue.FindStringSubmatch(w); m != nil && m[1] == key {
+			l.words[k] = key + ":" + value
+			return
+		}
+	}
+
+	l.words = append(l.words, key+":"+value)
+}
+
+func (l todoLine) String() string {
+	return strings.Join(append(append([]string{}, l.head...), l.words...), " ")
+}
+
+// ReadTodoTxt returns the tasks of a todo.txt file.
+func ReadTodoTxt(r io.Reader) ([]pomodoro.Task, error) {
+	tasks := []pomodoro.Task{}
+
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		l := parseTodoLine(s.Text())
+		name := l.name()
+		if name == "" {
+			continue
+		}
+
+		t := pomodoro.Task{
+			Name:       name,
+			Source:     SourceTodoTxt,
+			ExternalID: name,
+		}
+
+		if est, ok := l.value(todoEstimateKey); ok {
+			e, err := strconv.Atoi(est)
+			if err != nil || e < 0 {
+				return nil, fmt.Errorf("line %d: %w: invalid estimate %q", n,
+					pomodoro.ErrInvalidTask, est)
+			}
+			t.Estimate = e
+		}
+
+		if l.done {
+			t.Completed = l.completed
+			if t.Completed.IsZero() {
+				t.Completed = time.Now()
+			}
+		}
+
+		tasks = append(tasks, t)
+	}
+
+	return tasks, s.Err()
+}
+
+// WriteTodoTxt copies a todo.txt file from r to w, setting pomo:N on the
+// tasks with pomodoros done. Other lines are copied unchanged.
+func WriteTodoTxt(r io.Reader, w io.Writer, pomodoros map[string]int) error {
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		line := s.Text()
+
+		l := parseTodoLine(line)
+		if n := pomodoros[l.name()]; n > 0 && l.name() != "" {
+			l.set(todoPomodorosKey, strconv.Itoa(n))
+			line = l.String()
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return s.Err()
+}
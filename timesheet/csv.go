@@ -0,0 +1,69 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// WriteToggl writes the pomodoros in list to w as the CSV imported by
+// Toggl Track.
+func WriteToggl(w io.Writer, list []pomodoro.Interval, o Options) error {
+	header := []string{"Email", "Start date", "Start time", "Duration",
+		"Project", "Description", "Tags"}
+
+	return writeCSV(w, header, list, func(i pomodoro.Interval) []string {
+		start := i.StartTime.In(o.Location)
+
+		return []string{o.Email, start.Format(time.DateOnly),
+			start.Format(time.TimeOnly), clock(i.ActualDuration),
+			o.Mapping.Project(i.Project), o.Mapping.Description(i),
+			strings.Join(i.Tags, ", ")}
+	})
+}
+
+// WriteClockify writes the pomodoros in list to w as the CSV imported by
+// Clockify, with dates as YYYY-MM-DD.
+func WriteClockify(w io.Writer, list []pomodoro.Interval, o Options) error {
+	header := []string{"Email", "Project", "Description", "Tags",
+		"Start Date", "Start Time", "End Date", "End Time", "Duration (h)"}
+
+	return writeCSV(w, header, list, func(i pomodoro.Interval) []string {
+		start, end := i.StartTime.In(o.Location), end(i).In(o.Location)
+
+		return []string{o.Email, o.Mapping.Project(i.Project),
+			o.Mapping.Description(i), strings.Join(i.Tags, ", "),
+			start.Format(time.DateOnly), start.Format(time.TimeOnly),
+			end.Format(time.DateOnly), end.Format(time.TimeOnly),
+			clock(i.ActualDuration)}
+	})
+}
+
+func writeCSV(w io.Writer, header []string, list []pomodoro.Interval,
+	record func(pomodoro.Interval) []string) error {
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, i := range list {
+		if err := cw.Write(record(i)); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// clock formats d as HH:MM:SS.
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60,
+		int(d.Seconds())%60)
+}
@@ -0,0 +1,112 @@
+// Package timesheet exports done pomodoros to time tracking tools:
+// Timewarrior interval lines and the CSV imported by Toggl Track and
+// Clockify.
+package timesheet
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// Formats of the exports.
+const (
+	FormatTimewarrior = "timewarrior"
+	FormatToggl       = "toggl"
+	FormatClockify    = "clockify"
+)
+
+// ErrUnknownFormat is returned for a format that isn't supported.
+var ErrUnknownFormat = errors.New("Unknown export format")
+
+// ParseFormat checks a format name given by the user, such as "timew".
+func ParseFormat(name string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "timewarrior", "timew", "tw":
+		return FormatTimewarrior, nil
+	case "toggl":
+		return FormatToggl, nil
+	case "clockify":
+		return FormatClockify, nil
+	}
+
+	return "", fmt.Errorf("%w: %q, use timewarrior, toggl or clockify",
+		ErrUnknownFormat, name)
+}
+
+// Mapping names pomodoros the way the time tracking tools know them.
+type Mapping struct {
+	// Projects maps pomo projects, with their subprojects, to the project
+	// names of the tools.
+	Projects map[string]string
+	// Tasks maps task names, in lower case, to entry descriptions.
+	Tasks map[string]string
+}
+
+// Project returns the name mapped to project or to its closest parent,
+// or project itself if none is mapped.
+func (m Mapping) Project(project string) string {
+	best := ""
+	for p := range m.Projects {
+		if pomodoro.InProject(project, p) && len(p) > len(best) {
+			best = p
+		}
+	}
+
+	if best == "" {
+		return project
+	}
+
+	return m.Projects[best]
+}
+
+// Description returns the description of the entry for pomodoro i: its
+// mapped task, its task, or the category when it has none.
+func (m Mapping) Description(i pomodoro.Interval) string {
+	if d, ok := m.Tasks[strings.ToLower(i.Task)]; ok && i.Task != "" {
+		return d
+	}
+	if i.Task != "" {
+		return i.Task
+	}
+
+	return i.Category
+}
+
+// Options are the settings of an export.
+type Options struct {
+	Mapping Mapping
+	// Email is the user the Toggl and Clockify entries belong to.
+	Email string
+	// Location is the time zone of the dates and times in the CSV.
+	Location *time.Location
+}
+
+// Write writes the pomodoros in list to w in format.
+func Write(w io.Writer, format string, list []pomodoro.Interval,
+	o Options) error {
+
+	if o.Location == nil {
+		o.Location = time.Local
+	}
+
+	switch format {
+	case FormatTimewarrior:
+		return WriteTimewarrior(w, list, o.Mapping)
+	case FormatToggl:
+		return WriteToggl(w, list, o)
+	case FormatClockify:
+		return WriteClockify(w, list, o)
+	}
+
+	return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+}
+
+// end returns the time pomodoro i ended, counting only the time it ran.
+func end(i pomodoro.Interval) time.Time {
+	return i.StartTime.Add(i.ActualDuration)
+}
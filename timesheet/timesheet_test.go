@@ -0,0 +1,127 @@
+package timesheet_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+	"github.com/xasterKies/pomanalyzer/timesheet"
+)
+
+var (
+	mapping = timesheet.Mapping{
+		Projects: map[string]string{"work": "Acme", "work/api": "Acme API"},
+		Tasks:    map[string]string{"write report": "Q4 report"},
+	}
+
+	paris = time.FixedZone("CEST", 2*60*60)
+
+	pomodoros = []pomodoro.Interval{
+		{ID: 1, StartTime: time.Date(2026, 10, 16, 9, 0, 0, 0, paris),
+			ActualDuration: 25 * time.Minute, Category: pomodoro.CategoryPomodoro,
+			State: pomodoro.StateDone, Task: "Write Report",
+			Project: "work/api/auth", Tags: []string{"backend", "review"}},
+		{ID: 3, StartTime: time.Date(2026, 10, 16, 23, 50, 0, 0, time.UTC),
+			ActualDuration: 20*time.Minute + 30*time.Second,
+			Category:       pomodoro.CategoryPomodoro, State: pomodoro.StateDone,
+			Task: `say "hi" #1`},
+	}
+)
+
+func TestMapping(t *testing.T) {
+	testCases := []struct {
+		project string
+		exp     string
+	}{
+		{"work", "Acme"},
+		{"work/docs", "Acme"},
+		{"work/api/auth", "Acme API"},
+		{"workshop", "workshop"},
+		{"", ""},
+	}
+
+	for _, tc := range testCases {
+		if got := mapping.Project(tc.project); got != tc.exp {
+			t.Errorf("Expected %q mapped to %q, got %q", tc.project, tc.exp, got)
+		}
+	}
+
+	i := pomodoro.Interval{Category: pomodoro.CategoryPomodoro}
+	if d := mapping.Description(i); d != pomodoro.CategoryPomodoro {
+		t.Errorf("Expected description %q without a task, got %q",
+			pomodoro.CategoryPomodoro, d)
+	}
+	if d := mapping.Description(pomodoros[0]); d != "Q4 report" {
+		t.Errorf("Expected mapped description, got %q", d)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	o := timesheet.Options{Mapping: mapping, Email: "me@example.com",
+		Location: paris}
+
+	testCases := []struct {
+		format string
+		exp    string
+	}{
+		{timesheet.FormatTimewarrior, `inc 20261016T070000Z - 20261016T072500Z # "Acme API" "Q4 report" backend review
+inc 20261016T235000Z - 20261017T001030Z # "say \"hi\" #1"
+`},
+		{timesheet.FormatToggl, `Email,Start date,Start time,Duration,Project,Description,Tags
+me@example.com,2026-10-16,09:00:00,00:25:00,Acme API,Q4 report,"backend, review"
+me@example.com,2026-10-17,01:50:00,00:20:30,,"say ""hi"" #1",
+`},
+		{timesheet.FormatClockify, `Email,Project,Description,Tags,Start Date,Start Time,End Date,End Time,Duration (h)
+me@example.com,Acme API,Q4 report,"backend, review",2026-10-16,09:00:00,2026-10-16,09:25:00,00:25:00
+me@example.com,,"say ""hi"" #1",,2026-10-17,01:50:00,2026-10-17,02:10:30,00:20:30
+`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			var out bytes.Buffer
+			if err := timesheet.Write(&out, tc.format, pomodoros, o); err != nil {
+				t.Fatal(err)
+			}
+
+			if out.String() != tc.exp {
+				t.Errorf("Expected:\n%s\ngot:\n%s", tc.exp, out.String())
+			}
+		})
+	}
+
+	if _, err := timesheet.ParseFormat("harvest"); !errors.Is(err,
+		timesheet.ErrUnknownFormat) {
+		t.Errorf("Expected error %q, got %v", timesheet.ErrUnknownFormat, err)
+	}
+}
+
+func TestAppendTimewarrior(t *testing.T) {
+	db := t.TempDir()
+
+	for _, i := range pomodoros {
+		if err := timesheet.AppendTimewarrior(db, i, mapping); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(db, "data", "2026-10.data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 2 {
+		t.Errorf("Expected 2 intervals in the data file, got %d:\n%s", lines,
+			data)
+	}
+
+	missing := filepath.Join(db, "missing")
+	err = timesheet.AppendTimewarrior(missing, pomodoros[0], mapping)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected error for a missing database, got %v", err)
+	}
+}
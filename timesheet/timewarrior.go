@@ -0,0 +1,101 @@
+package timesheet
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xasterKies/pomanalyzer/pomodoro"
+)
+
+// timewLayout is the time format of the Timewarrior data files, in UTC.
+const timewLayout = "20060102T150405Z"
+
+// TimewarriorLine returns pomodoro i as a line of a Timewarrior data file,
+// tagged with its project, description and tags.
+func TimewarriorLine(i pomodoro.Interval, m Mapping) string {
+	tags := []string{}
+	if i.Project != "" {
+		tags = append(tags, m.Project(i.Project))
+	}
+	tags = append(tags, m.Description(i))
+	tags = append(tags, i.Tags...)
+
+	for k, t := range tags {
+		tags[k] = timewTag(t)
+	}
+
+	return fmt.Sprintf("inc %s - %s # %s",
+		i.StartTime.UTC().Format(timewLayout), end(i).UTC().Format(timewLayout),
+		strings.Join(tags, " "))
+}
+
+// timewTag quotes tag the way Timewarrior does when it has spaces or
+// quotes.
+func timewTag(tag string) string {
+	if !strings.ContainsAny(tag, " \t\"#") {
+		return tag
+	}
+
+	return `"` + strings.ReplaceAll(tag, `"`, `\"`) + `"`
+}
+
+// WriteTimewarrior writes the pomodoros in list to w as Timewarrior
+// interval lines.
+func WriteTimewarrior(w io.Writer, list []pomodoro.Interval,
+	m Mapping) error {
+
+	for _, i := range list {
+		if _, err := fmt.Fprintln(w, TimewarriorLine(i, m)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TimewarriorDB returns the Timewarrior database directory: dir if set,
+// else $TIMEWARRIORDB or ~/.timewarrior.
+func TimewarriorDB(dir string) (string, error) {
+	if dir == "" {
+		dir = os.Getenv("TIMEWARRIORDB")
+	}
+	if dir != "" {
+		return dir, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, ".timewarrior"), nil
+}
+
+// AppendTimewarrior appends pomodoro i to the data file of the month it
+// started in, in the Timewarrior database db.
+func AppendTimewarrior(db string, i pomodoro.Interval, m Mapping) error {
+	if _, err := os.Stat(db); err != nil {
+		return fmt.Errorf("Timewarrior database: %w", err)
+	}
+
+	dir := filepath.Join(db, "data")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	name := filepath.Join(dir, i.StartTime.UTC().Format("2006-01")+".data")
+	f, err := os.OpenFile(name, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(f, TimewarriorLine(i, m)); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}